@@ -18,6 +18,8 @@ type Allergy struct {
 //
 // https://docs.athenahealth.com/api/api-ref/allergy#Search-for-available-allergies
 func (h *HTTPClient) SearchAllergies(ctx context.Context, searchVal string) ([]*Allergy, error) {
+	ctx = withOperation(ctx, "SearchAllergies")
+
 	out := []*Allergy{}
 
 	q := url.Values{}
@@ -9,6 +9,8 @@ import (
 // POST /v1/{practiceid}/appointments/{appointmentid}/cancelcheckin
 // https://docs.athenahealth.com/api/api-ref/appointment-check-in#Cancel-appointment-check-in-process
 func (h *HTTPClient) AppointmentCancelCheckIn(ctx context.Context, apptID string) error {
+	ctx = withOperation(ctx, "AppointmentCancelCheckIn")
+
 	if apptID == "" {
 		return fmt.Errorf("cannot AppointmentCancelCheckIn with empty apptID [%s]", apptID)
 	}
@@ -30,6 +32,8 @@ func (h *HTTPClient) AppointmentCancelCheckIn(ctx context.Context, apptID string
 // POST /v1/{practiceid}/appointments/{appointmentid}/checkin
 // https://docs.athenahealth.com/api/api-ref/appointment-check-in#Check-in-this-appointment.
 func (h *HTTPClient) AppointmentCheckIn(ctx context.Context, apptID string) error {
+	ctx = withOperation(ctx, "AppointmentCheckIn")
+
 	if apptID == "" {
 		return fmt.Errorf("cannot AppointmentCheckIn with empty apptID [%s]", apptID)
 	}
@@ -51,6 +55,8 @@ func (h *HTTPClient) AppointmentCheckIn(ctx context.Context, apptID string) erro
 // POST /v1/{practiceid}/appointments/{appointmentid}/checkout
 // https://docs.athenahealth.com/api/api-ref/check-out#Complete-appointment-check-out-process
 func (h *HTTPClient) AppointmentCheckOut(ctx context.Context, apptID string) error {
+	ctx = withOperation(ctx, "AppointmentCheckOut")
+
 	if apptID == "" {
 		return fmt.Errorf("cannot AppointmentCheckOut with empty apptID [%s]", apptID)
 	}
@@ -72,6 +78,8 @@ func (h *HTTPClient) AppointmentCheckOut(ctx context.Context, apptID string) err
 // POST /v1/{practiceid}/appointments/{appointmentid}/startcheckin
 // https://docs.athenahealth.com/api/api-ref/appointment-check-in#Initiate-appointment-check-in-process
 func (h *HTTPClient) AppointmentStartCheckIn(ctx context.Context, apptID string) error {
+	ctx = withOperation(ctx, "AppointmentStartCheckIn")
+
 	if apptID == "" {
 		return fmt.Errorf("cannot AppointmentStartCheckIn with empty apptID [%s]", apptID)
 	}
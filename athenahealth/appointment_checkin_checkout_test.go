@@ -16,7 +16,7 @@ func TestHTTPClient_AppointmentCancelCheckIn(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.NoError(r.ParseForm())
 
-		assert.Equal(r.URL.Path, "/appointments/54/cancelcheckin")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/appointments/54/cancelcheckin")
 		b, _ := os.ReadFile("./resources/AppointmentCancelCheckIn.json")
 		w.Write(b)
 	}
@@ -37,7 +37,7 @@ func TestHTTPClient_AppointmentCheckIn(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.NoError(r.ParseForm())
 
-		assert.Equal(r.URL.Path, "/appointments/54/checkin")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/appointments/54/checkin")
 		b, _ := os.ReadFile("./resources/AppointmentCheckIn.json")
 		w.Write(b)
 	}
@@ -58,7 +58,7 @@ func TestHTTPClient_AppointmentStartCheckIn(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.NoError(r.ParseForm())
 
-		assert.Equal(r.URL.Path, "/appointments/54/startcheckin")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/appointments/54/startcheckin")
 		b, _ := os.ReadFile("./resources/AppointmentStartCheckIn.json")
 		w.Write(b)
 	}
@@ -49,6 +49,8 @@ type ListAppointmentRemindersResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-reminders#Get-list-of-appointment-reminders
 func (h *HTTPClient) ListAppointmentReminders(ctx context.Context, opts *ListAppointmentRemindersOptions) (*ListAppointmentRemindersResult, error) {
+	ctx = withOperation(ctx, "ListAppointmentReminders")
+
 	if len(opts.DepartmentID) == 0 {
 		return nil, errors.New("missing DepartmentID")
 	}
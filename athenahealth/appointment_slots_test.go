@@ -31,7 +31,7 @@ func TestHTTPClient_CreateAppointmentSlot(t *testing.T) {
 		assert.Equal(r.Form.Get("departmentid"), strconv.Itoa(opts.DepartmentID))
 		assert.Equal(r.Form.Get("providerid"), strconv.Itoa(opts.ProviderID))
 		assert.Equal(r.Form.Get("reasonid"), strconv.Itoa(*opts.ReasonID))
-		assert.Equal(r.URL.Path, "/appointments/open")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/appointments/open")
 		b, _ := os.ReadFile("./resources/CreateAppointmentSlot.json")
 		w.Write(b)
 	}
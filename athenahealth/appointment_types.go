@@ -23,6 +23,8 @@ type CreateAppointmentTypeResult struct {
 // POST /v1/{practiceid}/appointmenttypes
 // https://docs.athenahealth.com/api/api-ref/appointment-types
 func (h *HTTPClient) CreateAppointmentType(ctx context.Context, opts *CreateAppointmentTypeOptions) (*CreateAppointmentTypeResult, error) {
+	ctx = withOperation(ctx, "CreateAppointmentType")
+
 	out := CreateAppointmentTypeResult{}
 
 	q := url.Values{}
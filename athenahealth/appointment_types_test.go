@@ -30,7 +30,7 @@ func TestHTTPClient_CreateAppointmentType(t *testing.T) {
 		assert.Equal(r.Form.Get("patient"), strconv.FormatBool(opts.Patient))
 		assert.Equal(r.Form.Get("shortname"), opts.ShortName)
 		assert.Equal(r.Form.Get("templatetypeonly"), strconv.FormatBool(*opts.TemplateTypeOnly))
-		assert.Equal(r.URL.Path, "/appointmenttypes")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/appointmenttypes")
 		b, _ := os.ReadFile("./resources/CreateAppointmentType.json")
 		w.Write(b)
 	}
@@ -33,6 +33,8 @@ type Appointment struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-appointment-details
 func (h *HTTPClient) GetAppointment(ctx context.Context, id string) (*Appointment, error) {
+	ctx = withOperation(ctx, "GetAppointment")
+
 	out := []*Appointment{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/appointments/%s", id), nil, &out)
@@ -71,6 +73,8 @@ type listAppointmentCustomFieldsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-custom-fields#Get-the-list-of-appointment-custom-fields
 func (h *HTTPClient) ListAppointmentCustomFields(ctx context.Context) ([]*AppointmentCustomField, error) {
+	ctx = withOperation(ctx, "ListAppointmentCustomFields")
+
 	out := &listAppointmentCustomFieldsResponse{}
 
 	_, err := h.Get(ctx, "/appointments/customfields", nil, &out)
@@ -193,6 +197,8 @@ type listBookedAppointmentsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-list-of-booked-appointments
 func (h *HTTPClient) ListBookedAppointments(ctx context.Context, opts *ListBookedAppointmentsOptions) (*ListBookedAppointmentsResult, error) {
+	ctx = withOperation(ctx, "ListBookedAppointments")
+
 	out := &listBookedAppointmentsResponse{}
 
 	q := url.Values{}
@@ -248,6 +254,28 @@ func (h *HTTPClient) ListBookedAppointments(ctx context.Context, opts *ListBooke
 	}, nil
 }
 
+// ListAllBookedAppointments - Get all booked appointments
+func (h *HTTPClient) ListAllBookedAppointments(ctx context.Context, opts *ListBookedAppointmentsOptions) ([]*BookedAppointment, error) {
+	ctx = withOperation(ctx, "ListAllBookedAppointments")
+
+	o := ListBookedAppointmentsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*BookedAppointment, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListBookedAppointments(ctx, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.BookedAppointments, res.Pagination, nil
+	})
+}
+
 type ListChangedAppointmentsOptions struct {
 	DepartmentID               string
 	LeaveUnprocessed           bool
@@ -268,6 +296,8 @@ type listChangedAppointmentsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-list-of-changes-in-appointment-slots-based-on-subscribed-events
 func (h *HTTPClient) ListChangedAppointments(ctx context.Context, opts *ListChangedAppointmentsOptions) ([]*BookedAppointment, error) {
+	ctx = withOperation(ctx, "ListChangedAppointments")
+
 	out := &listChangedAppointmentsResponse{}
 
 	q := url.Values{}
@@ -322,6 +352,8 @@ type CreateAppointmentNoteOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-notes#Create-appointment-note
 func (h *HTTPClient) CreateAppointmentNote(ctx context.Context, appointmentID string, opts *CreateAppointmentNoteOptions) error {
+	ctx = withOperation(ctx, "CreateAppointmentNote")
+
 	var form url.Values
 
 	if opts != nil {
@@ -371,6 +403,8 @@ type listAppointmentNotesResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-notes#Get-all-appointment-notes
 func (h *HTTPClient) ListAppointmentNotes(ctx context.Context, appointmentID string, opts *ListAppointmentNotesOptions) ([]*AppointmentNote, error) {
+	ctx = withOperation(ctx, "ListAppointmentNotes")
+
 	out := &listAppointmentNotesResponse{}
 
 	q := url.Values{}
@@ -406,6 +440,8 @@ type UpdateAppointmentNoteOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-notes#Update-appointment-note
 func (h *HTTPClient) UpdateAppointmentNote(ctx context.Context, appointmentID, noteID string, opts *UpdateAppointmentNoteOptions) error {
+	ctx = withOperation(ctx, "UpdateAppointmentNote")
+
 	var form url.Values
 
 	if opts != nil {
@@ -447,6 +483,8 @@ type DeleteAppointmentNoteOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-notes#Delete-appointment-note
 func (h *HTTPClient) DeleteAppointmentNote(ctx context.Context, appointmentID, noteID string, opts *DeleteAppointmentNoteOptions) error {
+	ctx = withOperation(ctx, "DeleteAppointmentNote")
+
 	var form url.Values
 
 	if opts != nil {
@@ -533,6 +571,8 @@ type ListOpenAppointmentSlotsResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment-slot#Get-list-of-open-appointment-slots
 func (h *HTTPClient) ListOpenAppointmentSlots(ctx context.Context, departmentID int, opts *ListOpenAppointmentSlotOptions) (*ListOpenAppointmentSlotsResult, error) {
+	ctx = withOperation(ctx, "ListOpenAppointmentSlots")
+
 	out := &listOpenAppointmentSlotsResponse{}
 
 	q := url.Values{}
@@ -602,6 +642,34 @@ func (h *HTTPClient) ListOpenAppointmentSlots(ctx context.Context, departmentID
 	}, nil
 }
 
+// ListAllOpenAppointmentSlots - Get all open appointment slots for a department
+func (h *HTTPClient) ListAllOpenAppointmentSlots(ctx context.Context, departmentID int, opts *ListOpenAppointmentSlotOptions) ([]*OpenAppointmentSlot, error) {
+	ctx = withOperation(ctx, "ListAllOpenAppointmentSlots")
+
+	o := ListOpenAppointmentSlotOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	pagination := &PaginationOptions{
+		Limit:  o.Limit,
+		Offset: o.Offset,
+	}
+
+	return listAll(ctx, pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*OpenAppointmentSlot, *PaginationResult, error) {
+		o := o
+		o.Limit = pagination.Limit
+		o.Offset = pagination.Offset
+
+		res, err := h.ListOpenAppointmentSlots(ctx, departmentID, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Appointments, res.Pagination, nil
+	})
+}
+
 type BookAppointmentOptions struct {
 	AppointmentTypeID           int
 	BookingNote                 string
@@ -619,6 +687,8 @@ type BookAppointmentOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Book-appointment
 func (h *HTTPClient) BookAppointment(ctx context.Context, patientID, appointmentID string, opts *BookAppointmentOptions) (*BookedAppointment, error) {
+	ctx = withOperation(ctx, "BookAppointment")
+
 	var out []*BookedAppointment
 
 	form := url.Values{}
@@ -690,6 +760,8 @@ var updateBookedApptSuccess = "1"
 // PUT /v1/{practiceid}/appointments/booked/{appointmentid}
 // https://docs.athenahealth.com/api/api-ref/appointment-booked#Appointment-Booked
 func (h *HTTPClient) UpdateBookedAppointment(ctx context.Context, appointmentID string, opts *UpdateBookedAppointmentOptions) error {
+	ctx = withOperation(ctx, "UpdateBookedAppointment")
+
 	form := url.Values{}
 
 	if opts.AppointmentTypeID != nil {
@@ -804,6 +876,8 @@ type RescheduleAppointmentOptions struct {
 // PUT /v1/{practiceid}/appointments/{appointmentid}/reschedule
 // https://docs.athenahealth.com/api/api-ref/appointment#Reschedule-appointment
 func (h *HTTPClient) RescheduleAppointment(ctx context.Context, appointmentID int, opts *RescheduleAppointmentOptions) (*RescheduleAppointmentResult, error) {
+	ctx = withOperation(ctx, "RescheduleAppointment")
+
 	var out []*RescheduleAppointmentResult
 
 	q := url.Values{}
@@ -891,6 +965,8 @@ func (h *HTTPClient) freezeOrUnfreezeAppointmentSlot(ctx context.Context, appoin
 // PUT /v1/{practiceid}/appointments/{appointmentid}/freeze
 // https://docs.athenahealth.com/api/api-ref/appointment-slot#Freeze-appointment-slot
 func (h *HTTPClient) FreezeAppointmentSlot(ctx context.Context, appointmentID string, opts *FreezeOrUnfreezeAppointmentSlotOptions) error {
+	ctx = withOperation(ctx, "FreezeAppointmentSlot")
+
 	return h.freezeOrUnfreezeAppointmentSlot(ctx, appointmentID, true, opts)
 }
 
@@ -898,5 +974,7 @@ func (h *HTTPClient) FreezeAppointmentSlot(ctx context.Context, appointmentID st
 // PUT /v1/{practiceid}/appointments/{appointmentid}/freeze
 // https://docs.athenahealth.com/api/api-ref/appointment-slot#Freeze-appointment-slot
 func (h *HTTPClient) UnfreezeAppointmentSlot(ctx context.Context, appointmentID string, opts *FreezeOrUnfreezeAppointmentSlotOptions) error {
+	ctx = withOperation(ctx, "UnfreezeAppointmentSlot")
+
 	return h.freezeOrUnfreezeAppointmentSlot(ctx, appointmentID, false, opts)
 }
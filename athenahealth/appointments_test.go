@@ -322,7 +322,7 @@ func TestHTTPClient_UpdateBookedAppointment_IntResponse(t *testing.T) {
 		assert.Equal(r.Form.Get("providerid"), *opts.ProviderID)
 		assert.Equal(r.Form.Get("supervisingproviderid"), *opts.SupervisingProviderID)
 
-		assert.Equal(r.URL.Path, fmt.Sprintf("/appointments/booked/%s", apptID))
+		assert.Equal(r.URL.Path, fmt.Sprintf("/%s/appointments/booked/%s", testPracticeID, apptID))
 
 		b, _ := os.ReadFile("./resources/UpdateBookedAppointment_IntResponse.json")
 		w.Write(b)
@@ -355,7 +355,7 @@ func TestHTTPClient_UpdateBookedAppointment_StringResponse(t *testing.T) {
 		assert.Equal(r.Form.Get("providerid"), *opts.ProviderID)
 		assert.Equal(r.Form.Get("supervisingproviderid"), *opts.SupervisingProviderID)
 
-		assert.Equal(r.URL.Path, fmt.Sprintf("/appointments/booked/%s", apptID))
+		assert.Equal(r.URL.Path, fmt.Sprintf("/%s/appointments/booked/%s", testPracticeID, apptID))
 
 		b, _ := os.ReadFile("./resources/UpdateBookedAppointment_StringResponse.json")
 		w.Write(b)
@@ -392,7 +392,7 @@ func TestHTTPClient_RescheduleAppointment(t *testing.T) {
 		assert.Equal(r.Form.Get("patientid"), strconv.Itoa(opts.PatientID))
 		assert.Equal(r.Form.Get("reasonid"), strconv.Itoa(*opts.ReasonID))
 		assert.Equal(r.Form.Get("reschedulereason"), *opts.RescheduleReason)
-		assert.Equal(r.URL.Path, fmt.Sprintf("/appointments/%d/reschedule", 998877))
+		assert.Equal(r.URL.Path, fmt.Sprintf("/%s/appointments/%d/reschedule", testPracticeID, 998877))
 
 		b, _ := os.ReadFile("./resources/RescheduleAppointment.json")
 		w.Write(b)
@@ -480,7 +480,7 @@ func TestHTTPClient_FreezeAppointmentSlot(t *testing.T) {
 			assert.Equal(r.Form.Get("requirescancellation"), strconv.FormatBool(opts.RequiresCancellation))
 			assert.Equal(r.Form.Get("freeze"), strconv.FormatBool(testCase.Freeze), testCase.Msg)
 
-			assert.Equal(r.URL.Path, fmt.Sprintf("/appointments/%s/freeze", apptID))
+			assert.Equal(r.URL.Path, fmt.Sprintf("/%s/appointments/%s/freeze", testPracticeID, apptID))
 
 			b, _ := os.ReadFile(testCase.Resource)
 			w.Write(b)
@@ -0,0 +1,59 @@
+// Package athenahealthmock provides a mock athenahealth.Client generated from the Client interface.
+//
+//	client := &athenahealthmock.Client{
+//		GetPatientFunc: func(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error) {
+//			return &athenahealth.Patient{PatientID: patientID}, nil
+//		},
+//	}
+package athenahealthmock
+
+//go:generate go run ../internal/mockgen -in ../client.go -out client.go
+
+// Call is a call to a method of Client.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+func (c *Client) record(method string, args ...interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.calls = append(c.calls, &Call{
+		Method: method,
+		Args:   args,
+	})
+}
+
+// Calls returns the calls made to the client in the order they were made. If methods are given, only calls
+// to those methods are returned.
+func (c *Client) Calls(methods ...string) []*Call {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var calls []*Call
+
+	for _, call := range c.calls {
+		if len(methods) == 0 {
+			calls = append(calls, call)
+			continue
+		}
+
+		for _, method := range methods {
+			if call.Method == method {
+				calls = append(calls, call)
+				break
+			}
+		}
+	}
+
+	return calls
+}
+
+// Reset forgets the calls made to the client.
+func (c *Client) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.calls = nil
+}
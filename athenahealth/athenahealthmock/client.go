@@ -0,0 +1,946 @@
+// Code generated by mockgen from client.go. DO NOT EDIT.
+
+package athenahealthmock
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+var _ athenahealth.Client = (*Client)(nil)
+
+// Client is a mock athenahealth.Client. Each method calls the function field of the same name with a Func suffix,
+// and panics if it is nil. Calls are recorded and returned by Calls.
+type Client struct {
+	DepartmentGetRequiredCheckInFieldsFunc          func(ctx context.Context, deptID string) (*athenahealth.GetRequiredCheckInFieldsResult, error)
+	GetDepartmentFunc                               func(ctx context.Context, departmentID string) (*athenahealth.Department, error)
+	ListDepartmentsFunc                             func(ctx context.Context, opts *athenahealth.ListDepartmentsOptions) (*athenahealth.ListDepartmentsResult, error)
+	CreatePatientFunc                               func(ctx context.Context, opts *athenahealth.CreatePatientOptions) (string, error)
+	GetPatientFunc                                  func(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error)
+	GetPatientsFunc                                 func(ctx context.Context, id string, opts *athenahealth.GetPatientOptions) ([]*athenahealth.Patient, error)
+	ListPatientsFunc                                func(ctx context.Context, opts *athenahealth.ListPatientsOptions) (*athenahealth.ListPatientsResult, error)
+	UpdatePatientFunc                               func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientOptions) (*athenahealth.UpdatePatientResult, error)
+	UpdatePatientInformationVerificationDetailsFunc func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientInformationVerificationDetailsOptions) error
+	UpdatePatientMedicationHistoryConsentFunc       func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientMedicationHistoryConsentOptions) error
+	GetPatientPhotoFunc                             func(ctx context.Context, patientID string, opts *athenahealth.GetPatientPhotoOptions) (string, error)
+	UpdatePatientPhotoFunc                          func(ctx context.Context, patientID string, data []byte) error
+	UpdatePatientPhotoReaderFunc                    func(ctx context.Context, patientID string, r io.Reader) error
+	ListProblemsFunc                                func(ctx context.Context, patientID string, opts *athenahealth.ListProblemsOptions) ([]*athenahealth.Problem, error)
+	ListAdminDocumentsFunc                          func(ctx context.Context, patientID string, opts *athenahealth.ListAdminDocumentsOptions) (*athenahealth.ListAdminDocumentsResult, error)
+	AddDocumentFunc                                 func(ctx context.Context, patientID string, opts *athenahealth.AddDocumentOptions) (string, error)
+	AddDocumentReaderFunc                           func(ctx context.Context, patientID string, opts *athenahealth.AddDocumentReaderOptions) (string, error)
+	AddClinicalDocumentFunc                         func(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentOptions) (*athenahealth.AddClinicalDocumentResponse, error)
+	AddClinicalDocumentReaderFunc                   func(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentReaderOptions) (*athenahealth.AddClinicalDocumentResponse, error)
+	AddPatientCaseDocumentFunc                      func(ctx context.Context, patientID string, opts *athenahealth.AddPatientCaseDocumentOptions) (int, error)
+	DeleteClinicalDocumentFunc                      func(ctx context.Context, patientID string, clinicalDocumentID string) (*athenahealth.DeleteClinicalDocumentResponse, error)
+	ListPatientsMatchingCustomFieldFunc             func(ctx context.Context, opts *athenahealth.ListPatientsMatchingCustomFieldOptions) (*athenahealth.ListPatientsMatchingCustomFieldResult, error)
+	ListCustomFieldsFunc                            func(ctx context.Context) ([]*athenahealth.CustomField, error)
+	GetPatientCustomFieldsFunc                      func(ctx context.Context, patientID string, departmentID string) ([]*athenahealth.CustomFieldValue, error)
+	UpdatePatientCustomFieldsFunc                   func(ctx context.Context, patientID string, departmentID string, customFields []*athenahealth.CustomFieldValue) error
+	CreatePatientInsurancePackageFunc               func(ctx context.Context, opts *athenahealth.CreatePatientInsurancePackageOptions) (*athenahealth.InsurancePackage, error)
+	DeletePatientInsurancePackageFunc               func(ctx context.Context, patientID string, insuranceID string, cancellationNote string) error
+	ListPatientInsurancePackagesFunc                func(ctx context.Context, opts *athenahealth.ListPatientInsurancePackagesOptions) (*athenahealth.ListPatientInsurancePackagesResult, error)
+	UpdatePatientInsurancePackageFunc               func(ctx context.Context, opts *athenahealth.UpdatePatientInsurancePackageOptions) error
+	ReactivatePatientInsurancePackageFunc           func(ctx context.Context, patientID string, insuranceID string, expirationDate *time.Time) error
+	UploadPatientInsuranceCardImageFunc             func(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error)
+	UploadPatientInsuranceCardImageReaderFunc       func(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageReaderOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error)
+	GetPatientInsuranceCardImageFunc                func(ctx context.Context, patientID string, insuranceID string) (*athenahealth.GetPatientInsuranceCardImageResult, error)
+	AddPatientDriversLicenseDocumentFunc            func(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error)
+	AddPatientDriversLicenseDocumentReaderFunc      func(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentReaderOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error)
+	AddLabResultDocumentReaderFunc                  func(ctx context.Context, patientID string, departmentID string, opts *athenahealth.AddLabResultDocumentOptions) (int, error)
+	ListLabResultsFunc                              func(ctx context.Context, patientID string, departmentID string, opts *athenahealth.ListLabResultsOptions) (*athenahealth.ListLabResultsResult, error)
+	ListChangedLabResultsFunc                       func(ctx context.Context, opts *athenahealth.ListChangedLabResultsOptions) (*athenahealth.ListChangedLabResultsResult, error)
+	ListSocialHistoryTemplatesFunc                  func(ctx context.Context) ([]*athenahealth.SocialHistoryTemplate, error)
+	GetPatientSocialHistoryFunc                     func(ctx context.Context, patientID string, opts *athenahealth.GetPatientSocialHistoryOptions) (*athenahealth.GetPatientSocialHistoryResponse, error)
+	UpdatePatientSocialHistoryFunc                  func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientSocialHistoryOptions) error
+	GetHealthHistoryFormForAppointmentFunc          func(ctx context.Context, appointmentID string, formID string) (*athenahealth.HealthHistoryForm, error)
+	UpdateHealthHistoryFormForAppointmentFunc       func(ctx context.Context, appointmentID string, formID string, form *athenahealth.HealthHistoryForm) error
+	SearchAllergiesFunc                             func(ctx context.Context, searchVal string) ([]*athenahealth.Allergy, error)
+	ListMedicationsFunc                             func(ctx context.Context, patientID string, opts *athenahealth.ListMedicationsOptions) (*athenahealth.ListMedicationsResult, error)
+	SearchMedicationsFunc                           func(ctx context.Context, searchVal string) ([]*athenahealth.SearchMedicationsResult, error)
+	GetAppointmentFunc                              func(ctx context.Context, appointmentID string) (*athenahealth.Appointment, error)
+	ListBookedAppointmentsFunc                      func(ctx context.Context, opts *athenahealth.ListBookedAppointmentsOptions) (*athenahealth.ListBookedAppointmentsResult, error)
+	ListChangedAppointmentsFunc                     func(ctx context.Context, opts *athenahealth.ListChangedAppointmentsOptions) ([]*athenahealth.BookedAppointment, error)
+	ListOpenAppointmentSlotsFunc                    func(ctx context.Context, departmentID int, opts *athenahealth.ListOpenAppointmentSlotOptions) (*athenahealth.ListOpenAppointmentSlotsResult, error)
+	BookAppointmentFunc                             func(ctx context.Context, patientID string, apptID string, opts *athenahealth.BookAppointmentOptions) (*athenahealth.BookedAppointment, error)
+	UpdateBookedAppointmentFunc                     func(ctx context.Context, apptID string, opts *athenahealth.UpdateBookedAppointmentOptions) error
+	RescheduleAppointmentFunc                       func(ctx context.Context, apptID int, opts *athenahealth.RescheduleAppointmentOptions) (*athenahealth.RescheduleAppointmentResult, error)
+	ListAppointmentRemindersFunc                    func(ctx context.Context, opts *athenahealth.ListAppointmentRemindersOptions) (*athenahealth.ListAppointmentRemindersResult, error)
+	CreateAppointmentSlotFunc                       func(ctx context.Context, opts *athenahealth.CreateAppointmentSlotOptions) (*athenahealth.CreateAppointmentSlotResult, error)
+	CreateAppointmentTypeFunc                       func(ctx context.Context, options *athenahealth.CreateAppointmentTypeOptions) (*athenahealth.CreateAppointmentTypeResult, error)
+	ListAppointmentCustomFieldsFunc                 func(ctx context.Context) ([]*athenahealth.AppointmentCustomField, error)
+	FreezeAppointmentSlotFunc                       func(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error
+	UnfreezeAppointmentSlotFunc                     func(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error
+	AppointmentCancelCheckInFunc                    func(ctx context.Context, apptID string) error
+	AppointmentCheckInFunc                          func(ctx context.Context, apptID string) error
+	AppointmentCheckOutFunc                         func(ctx context.Context, apptID string) error
+	AppointmentStartCheckInFunc                     func(ctx context.Context, apptID string) error
+	CreateAppointmentNoteFunc                       func(ctx context.Context, appointmentID string, opts *athenahealth.CreateAppointmentNoteOptions) error
+	DeleteAppointmentNoteFunc                       func(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.DeleteAppointmentNoteOptions) error
+	ListAppointmentNotesFunc                        func(ctx context.Context, appointmentID string, opts *athenahealth.ListAppointmentNotesOptions) ([]*athenahealth.AppointmentNote, error)
+	UpdateAppointmentNoteFunc                       func(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.UpdateAppointmentNoteOptions) error
+	GetPhysicalExamFunc                             func(ctx context.Context, encounterID string, opts *athenahealth.GetPhysicalExamOpts) (*athenahealth.PhysicalExam, error)
+	ListEncounterDocumentsFunc                      func(ctx context.Context, departmentID string, patientID string, opts *athenahealth.ListEncounterDocumentsOptions) (*athenahealth.ListEncounterDocumentsResult, error)
+	EncounterSummaryFunc                            func(ctx context.Context, encounterID string, opts *athenahealth.EncounterSummaryOptions) (*athenahealth.EncounterSummaryResponse, error)
+	ListProvidersFunc                               func(ctx context.Context, opts *athenahealth.ListProvidersOptions) (*athenahealth.ListProvidersResult, error)
+	GetProviderFunc                                 func(ctx context.Context, providerID string) (*athenahealth.Provider, error)
+	GetSubscriptionFunc                             func(ctx context.Context, feedType string) (*athenahealth.Subscription, error)
+	ListSubscriptionEventsFunc                      func(ctx context.Context, feedType string) ([]*athenahealth.SubscriptionEvent, error)
+	SubscribeFunc                                   func(ctx context.Context, feedType string, opts *athenahealth.SubscribeOptions) error
+	UnsubscribeFunc                                 func(ctx context.Context, feedType string, opts *athenahealth.UnsubscribeOptions) error
+	ListChangedPatientsFunc                         func(ctx context.Context, opts *athenahealth.ListChangedPatientOptions) ([]*athenahealth.Patient, error)
+	ListChangedProvidersFunc                        func(ctx context.Context, opts *athenahealth.ListChangedProviderOptions) ([]*athenahealth.Provider, error)
+	ListChangedProblemsFunc                         func(ctx context.Context, opts *athenahealth.ListChangedProblemsOptions) ([]*athenahealth.ChangedProblem, error)
+	ListChangedPrescriptionsFunc                    func(ctx context.Context, options *athenahealth.ListChangedPrescriptionsOptions) (*athenahealth.ListChangedPrescriptionsResult, error)
+	UpdatePrescriptionFunc                          func(ctx context.Context, departmentID int, patientID int, documentID int, opts *athenahealth.UpdatePrescriptionOptions) (*athenahealth.UpdatePrescriptionResult, error)
+	CreateFinancialClaimFunc                        func(ctx context.Context, opts *athenahealth.CreateClaimOptions) ([]string, error)
+	ListClaimsFunc                                  func(ctx context.Context, opts *athenahealth.ListClaimsOptions) (*athenahealth.ListClaimsResult, error)
+	GetTelehealthInviteURLFunc                      func(ctx context.Context, apptID string) (*athenahealth.GetTelehealthInviteURLResult, error)
+
+	calls []*Call
+	lock  sync.Mutex
+}
+
+func (c *Client) DepartmentGetRequiredCheckInFields(ctx context.Context, deptID string) (*athenahealth.GetRequiredCheckInFieldsResult, error) {
+	c.record("DepartmentGetRequiredCheckInFields", ctx, deptID)
+
+	if c.DepartmentGetRequiredCheckInFieldsFunc == nil {
+		panic("athenahealthmock: Client.DepartmentGetRequiredCheckInFields called but DepartmentGetRequiredCheckInFieldsFunc is not set")
+	}
+
+	return c.DepartmentGetRequiredCheckInFieldsFunc(ctx, deptID)
+}
+
+func (c *Client) GetDepartment(ctx context.Context, departmentID string) (*athenahealth.Department, error) {
+	c.record("GetDepartment", ctx, departmentID)
+
+	if c.GetDepartmentFunc == nil {
+		panic("athenahealthmock: Client.GetDepartment called but GetDepartmentFunc is not set")
+	}
+
+	return c.GetDepartmentFunc(ctx, departmentID)
+}
+
+func (c *Client) ListDepartments(ctx context.Context, opts *athenahealth.ListDepartmentsOptions) (*athenahealth.ListDepartmentsResult, error) {
+	c.record("ListDepartments", ctx, opts)
+
+	if c.ListDepartmentsFunc == nil {
+		panic("athenahealthmock: Client.ListDepartments called but ListDepartmentsFunc is not set")
+	}
+
+	return c.ListDepartmentsFunc(ctx, opts)
+}
+
+func (c *Client) CreatePatient(ctx context.Context, opts *athenahealth.CreatePatientOptions) (string, error) {
+	c.record("CreatePatient", ctx, opts)
+
+	if c.CreatePatientFunc == nil {
+		panic("athenahealthmock: Client.CreatePatient called but CreatePatientFunc is not set")
+	}
+
+	return c.CreatePatientFunc(ctx, opts)
+}
+
+func (c *Client) GetPatient(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error) {
+	c.record("GetPatient", ctx, patientID, opts)
+
+	if c.GetPatientFunc == nil {
+		panic("athenahealthmock: Client.GetPatient called but GetPatientFunc is not set")
+	}
+
+	return c.GetPatientFunc(ctx, patientID, opts)
+}
+
+func (c *Client) GetPatients(ctx context.Context, id string, opts *athenahealth.GetPatientOptions) ([]*athenahealth.Patient, error) {
+	c.record("GetPatients", ctx, id, opts)
+
+	if c.GetPatientsFunc == nil {
+		panic("athenahealthmock: Client.GetPatients called but GetPatientsFunc is not set")
+	}
+
+	return c.GetPatientsFunc(ctx, id, opts)
+}
+
+func (c *Client) ListPatients(ctx context.Context, opts *athenahealth.ListPatientsOptions) (*athenahealth.ListPatientsResult, error) {
+	c.record("ListPatients", ctx, opts)
+
+	if c.ListPatientsFunc == nil {
+		panic("athenahealthmock: Client.ListPatients called but ListPatientsFunc is not set")
+	}
+
+	return c.ListPatientsFunc(ctx, opts)
+}
+
+func (c *Client) UpdatePatient(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientOptions) (*athenahealth.UpdatePatientResult, error) {
+	c.record("UpdatePatient", ctx, patientID, opts)
+
+	if c.UpdatePatientFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatient called but UpdatePatientFunc is not set")
+	}
+
+	return c.UpdatePatientFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientInformationVerificationDetails(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientInformationVerificationDetailsOptions) error {
+	c.record("UpdatePatientInformationVerificationDetails", ctx, patientID, opts)
+
+	if c.UpdatePatientInformationVerificationDetailsFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientInformationVerificationDetails called but UpdatePatientInformationVerificationDetailsFunc is not set")
+	}
+
+	return c.UpdatePatientInformationVerificationDetailsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientMedicationHistoryConsent(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientMedicationHistoryConsentOptions) error {
+	c.record("UpdatePatientMedicationHistoryConsent", ctx, patientID, opts)
+
+	if c.UpdatePatientMedicationHistoryConsentFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientMedicationHistoryConsent called but UpdatePatientMedicationHistoryConsentFunc is not set")
+	}
+
+	return c.UpdatePatientMedicationHistoryConsentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) GetPatientPhoto(ctx context.Context, patientID string, opts *athenahealth.GetPatientPhotoOptions) (string, error) {
+	c.record("GetPatientPhoto", ctx, patientID, opts)
+
+	if c.GetPatientPhotoFunc == nil {
+		panic("athenahealthmock: Client.GetPatientPhoto called but GetPatientPhotoFunc is not set")
+	}
+
+	return c.GetPatientPhotoFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientPhoto(ctx context.Context, patientID string, data []byte) error {
+	c.record("UpdatePatientPhoto", ctx, patientID, data)
+
+	if c.UpdatePatientPhotoFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientPhoto called but UpdatePatientPhotoFunc is not set")
+	}
+
+	return c.UpdatePatientPhotoFunc(ctx, patientID, data)
+}
+
+func (c *Client) UpdatePatientPhotoReader(ctx context.Context, patientID string, r io.Reader) error {
+	c.record("UpdatePatientPhotoReader", ctx, patientID, r)
+
+	if c.UpdatePatientPhotoReaderFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientPhotoReader called but UpdatePatientPhotoReaderFunc is not set")
+	}
+
+	return c.UpdatePatientPhotoReaderFunc(ctx, patientID, r)
+}
+
+func (c *Client) ListProblems(ctx context.Context, patientID string, opts *athenahealth.ListProblemsOptions) ([]*athenahealth.Problem, error) {
+	c.record("ListProblems", ctx, patientID, opts)
+
+	if c.ListProblemsFunc == nil {
+		panic("athenahealthmock: Client.ListProblems called but ListProblemsFunc is not set")
+	}
+
+	return c.ListProblemsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) ListAdminDocuments(ctx context.Context, patientID string, opts *athenahealth.ListAdminDocumentsOptions) (*athenahealth.ListAdminDocumentsResult, error) {
+	c.record("ListAdminDocuments", ctx, patientID, opts)
+
+	if c.ListAdminDocumentsFunc == nil {
+		panic("athenahealthmock: Client.ListAdminDocuments called but ListAdminDocumentsFunc is not set")
+	}
+
+	return c.ListAdminDocumentsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddDocument(ctx context.Context, patientID string, opts *athenahealth.AddDocumentOptions) (string, error) {
+	c.record("AddDocument", ctx, patientID, opts)
+
+	if c.AddDocumentFunc == nil {
+		panic("athenahealthmock: Client.AddDocument called but AddDocumentFunc is not set")
+	}
+
+	return c.AddDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddDocumentReader(ctx context.Context, patientID string, opts *athenahealth.AddDocumentReaderOptions) (string, error) {
+	c.record("AddDocumentReader", ctx, patientID, opts)
+
+	if c.AddDocumentReaderFunc == nil {
+		panic("athenahealthmock: Client.AddDocumentReader called but AddDocumentReaderFunc is not set")
+	}
+
+	return c.AddDocumentReaderFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddClinicalDocument(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentOptions) (*athenahealth.AddClinicalDocumentResponse, error) {
+	c.record("AddClinicalDocument", ctx, patientID, opts)
+
+	if c.AddClinicalDocumentFunc == nil {
+		panic("athenahealthmock: Client.AddClinicalDocument called but AddClinicalDocumentFunc is not set")
+	}
+
+	return c.AddClinicalDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddClinicalDocumentReader(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentReaderOptions) (*athenahealth.AddClinicalDocumentResponse, error) {
+	c.record("AddClinicalDocumentReader", ctx, patientID, opts)
+
+	if c.AddClinicalDocumentReaderFunc == nil {
+		panic("athenahealthmock: Client.AddClinicalDocumentReader called but AddClinicalDocumentReaderFunc is not set")
+	}
+
+	return c.AddClinicalDocumentReaderFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddPatientCaseDocument(ctx context.Context, patientID string, opts *athenahealth.AddPatientCaseDocumentOptions) (int, error) {
+	c.record("AddPatientCaseDocument", ctx, patientID, opts)
+
+	if c.AddPatientCaseDocumentFunc == nil {
+		panic("athenahealthmock: Client.AddPatientCaseDocument called but AddPatientCaseDocumentFunc is not set")
+	}
+
+	return c.AddPatientCaseDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) DeleteClinicalDocument(ctx context.Context, patientID string, clinicalDocumentID string) (*athenahealth.DeleteClinicalDocumentResponse, error) {
+	c.record("DeleteClinicalDocument", ctx, patientID, clinicalDocumentID)
+
+	if c.DeleteClinicalDocumentFunc == nil {
+		panic("athenahealthmock: Client.DeleteClinicalDocument called but DeleteClinicalDocumentFunc is not set")
+	}
+
+	return c.DeleteClinicalDocumentFunc(ctx, patientID, clinicalDocumentID)
+}
+
+func (c *Client) ListPatientsMatchingCustomField(ctx context.Context, opts *athenahealth.ListPatientsMatchingCustomFieldOptions) (*athenahealth.ListPatientsMatchingCustomFieldResult, error) {
+	c.record("ListPatientsMatchingCustomField", ctx, opts)
+
+	if c.ListPatientsMatchingCustomFieldFunc == nil {
+		panic("athenahealthmock: Client.ListPatientsMatchingCustomField called but ListPatientsMatchingCustomFieldFunc is not set")
+	}
+
+	return c.ListPatientsMatchingCustomFieldFunc(ctx, opts)
+}
+
+func (c *Client) ListCustomFields(ctx context.Context) ([]*athenahealth.CustomField, error) {
+	c.record("ListCustomFields", ctx)
+
+	if c.ListCustomFieldsFunc == nil {
+		panic("athenahealthmock: Client.ListCustomFields called but ListCustomFieldsFunc is not set")
+	}
+
+	return c.ListCustomFieldsFunc(ctx)
+}
+
+func (c *Client) GetPatientCustomFields(ctx context.Context, patientID string, departmentID string) ([]*athenahealth.CustomFieldValue, error) {
+	c.record("GetPatientCustomFields", ctx, patientID, departmentID)
+
+	if c.GetPatientCustomFieldsFunc == nil {
+		panic("athenahealthmock: Client.GetPatientCustomFields called but GetPatientCustomFieldsFunc is not set")
+	}
+
+	return c.GetPatientCustomFieldsFunc(ctx, patientID, departmentID)
+}
+
+func (c *Client) UpdatePatientCustomFields(ctx context.Context, patientID string, departmentID string, customFields []*athenahealth.CustomFieldValue) error {
+	c.record("UpdatePatientCustomFields", ctx, patientID, departmentID, customFields)
+
+	if c.UpdatePatientCustomFieldsFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientCustomFields called but UpdatePatientCustomFieldsFunc is not set")
+	}
+
+	return c.UpdatePatientCustomFieldsFunc(ctx, patientID, departmentID, customFields)
+}
+
+func (c *Client) CreatePatientInsurancePackage(ctx context.Context, opts *athenahealth.CreatePatientInsurancePackageOptions) (*athenahealth.InsurancePackage, error) {
+	c.record("CreatePatientInsurancePackage", ctx, opts)
+
+	if c.CreatePatientInsurancePackageFunc == nil {
+		panic("athenahealthmock: Client.CreatePatientInsurancePackage called but CreatePatientInsurancePackageFunc is not set")
+	}
+
+	return c.CreatePatientInsurancePackageFunc(ctx, opts)
+}
+
+func (c *Client) DeletePatientInsurancePackage(ctx context.Context, patientID string, insuranceID string, cancellationNote string) error {
+	c.record("DeletePatientInsurancePackage", ctx, patientID, insuranceID, cancellationNote)
+
+	if c.DeletePatientInsurancePackageFunc == nil {
+		panic("athenahealthmock: Client.DeletePatientInsurancePackage called but DeletePatientInsurancePackageFunc is not set")
+	}
+
+	return c.DeletePatientInsurancePackageFunc(ctx, patientID, insuranceID, cancellationNote)
+}
+
+func (c *Client) ListPatientInsurancePackages(ctx context.Context, opts *athenahealth.ListPatientInsurancePackagesOptions) (*athenahealth.ListPatientInsurancePackagesResult, error) {
+	c.record("ListPatientInsurancePackages", ctx, opts)
+
+	if c.ListPatientInsurancePackagesFunc == nil {
+		panic("athenahealthmock: Client.ListPatientInsurancePackages called but ListPatientInsurancePackagesFunc is not set")
+	}
+
+	return c.ListPatientInsurancePackagesFunc(ctx, opts)
+}
+
+func (c *Client) UpdatePatientInsurancePackage(ctx context.Context, opts *athenahealth.UpdatePatientInsurancePackageOptions) error {
+	c.record("UpdatePatientInsurancePackage", ctx, opts)
+
+	if c.UpdatePatientInsurancePackageFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientInsurancePackage called but UpdatePatientInsurancePackageFunc is not set")
+	}
+
+	return c.UpdatePatientInsurancePackageFunc(ctx, opts)
+}
+
+func (c *Client) ReactivatePatientInsurancePackage(ctx context.Context, patientID string, insuranceID string, expirationDate *time.Time) error {
+	c.record("ReactivatePatientInsurancePackage", ctx, patientID, insuranceID, expirationDate)
+
+	if c.ReactivatePatientInsurancePackageFunc == nil {
+		panic("athenahealthmock: Client.ReactivatePatientInsurancePackage called but ReactivatePatientInsurancePackageFunc is not set")
+	}
+
+	return c.ReactivatePatientInsurancePackageFunc(ctx, patientID, insuranceID, expirationDate)
+}
+
+func (c *Client) UploadPatientInsuranceCardImage(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error) {
+	c.record("UploadPatientInsuranceCardImage", ctx, patientID, insuranceID, opts)
+
+	if c.UploadPatientInsuranceCardImageFunc == nil {
+		panic("athenahealthmock: Client.UploadPatientInsuranceCardImage called but UploadPatientInsuranceCardImageFunc is not set")
+	}
+
+	return c.UploadPatientInsuranceCardImageFunc(ctx, patientID, insuranceID, opts)
+}
+
+func (c *Client) UploadPatientInsuranceCardImageReader(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageReaderOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error) {
+	c.record("UploadPatientInsuranceCardImageReader", ctx, patientID, insuranceID, opts)
+
+	if c.UploadPatientInsuranceCardImageReaderFunc == nil {
+		panic("athenahealthmock: Client.UploadPatientInsuranceCardImageReader called but UploadPatientInsuranceCardImageReaderFunc is not set")
+	}
+
+	return c.UploadPatientInsuranceCardImageReaderFunc(ctx, patientID, insuranceID, opts)
+}
+
+func (c *Client) GetPatientInsuranceCardImage(ctx context.Context, patientID string, insuranceID string) (*athenahealth.GetPatientInsuranceCardImageResult, error) {
+	c.record("GetPatientInsuranceCardImage", ctx, patientID, insuranceID)
+
+	if c.GetPatientInsuranceCardImageFunc == nil {
+		panic("athenahealthmock: Client.GetPatientInsuranceCardImage called but GetPatientInsuranceCardImageFunc is not set")
+	}
+
+	return c.GetPatientInsuranceCardImageFunc(ctx, patientID, insuranceID)
+}
+
+func (c *Client) AddPatientDriversLicenseDocument(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error) {
+	c.record("AddPatientDriversLicenseDocument", ctx, patientID, opts)
+
+	if c.AddPatientDriversLicenseDocumentFunc == nil {
+		panic("athenahealthmock: Client.AddPatientDriversLicenseDocument called but AddPatientDriversLicenseDocumentFunc is not set")
+	}
+
+	return c.AddPatientDriversLicenseDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddPatientDriversLicenseDocumentReader(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentReaderOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error) {
+	c.record("AddPatientDriversLicenseDocumentReader", ctx, patientID, opts)
+
+	if c.AddPatientDriversLicenseDocumentReaderFunc == nil {
+		panic("athenahealthmock: Client.AddPatientDriversLicenseDocumentReader called but AddPatientDriversLicenseDocumentReaderFunc is not set")
+	}
+
+	return c.AddPatientDriversLicenseDocumentReaderFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddLabResultDocumentReader(ctx context.Context, patientID string, departmentID string, opts *athenahealth.AddLabResultDocumentOptions) (int, error) {
+	c.record("AddLabResultDocumentReader", ctx, patientID, departmentID, opts)
+
+	if c.AddLabResultDocumentReaderFunc == nil {
+		panic("athenahealthmock: Client.AddLabResultDocumentReader called but AddLabResultDocumentReaderFunc is not set")
+	}
+
+	return c.AddLabResultDocumentReaderFunc(ctx, patientID, departmentID, opts)
+}
+
+func (c *Client) ListLabResults(ctx context.Context, patientID string, departmentID string, opts *athenahealth.ListLabResultsOptions) (*athenahealth.ListLabResultsResult, error) {
+	c.record("ListLabResults", ctx, patientID, departmentID, opts)
+
+	if c.ListLabResultsFunc == nil {
+		panic("athenahealthmock: Client.ListLabResults called but ListLabResultsFunc is not set")
+	}
+
+	return c.ListLabResultsFunc(ctx, patientID, departmentID, opts)
+}
+
+func (c *Client) ListChangedLabResults(ctx context.Context, opts *athenahealth.ListChangedLabResultsOptions) (*athenahealth.ListChangedLabResultsResult, error) {
+	c.record("ListChangedLabResults", ctx, opts)
+
+	if c.ListChangedLabResultsFunc == nil {
+		panic("athenahealthmock: Client.ListChangedLabResults called but ListChangedLabResultsFunc is not set")
+	}
+
+	return c.ListChangedLabResultsFunc(ctx, opts)
+}
+
+func (c *Client) ListSocialHistoryTemplates(ctx context.Context) ([]*athenahealth.SocialHistoryTemplate, error) {
+	c.record("ListSocialHistoryTemplates", ctx)
+
+	if c.ListSocialHistoryTemplatesFunc == nil {
+		panic("athenahealthmock: Client.ListSocialHistoryTemplates called but ListSocialHistoryTemplatesFunc is not set")
+	}
+
+	return c.ListSocialHistoryTemplatesFunc(ctx)
+}
+
+func (c *Client) GetPatientSocialHistory(ctx context.Context, patientID string, opts *athenahealth.GetPatientSocialHistoryOptions) (*athenahealth.GetPatientSocialHistoryResponse, error) {
+	c.record("GetPatientSocialHistory", ctx, patientID, opts)
+
+	if c.GetPatientSocialHistoryFunc == nil {
+		panic("athenahealthmock: Client.GetPatientSocialHistory called but GetPatientSocialHistoryFunc is not set")
+	}
+
+	return c.GetPatientSocialHistoryFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientSocialHistory(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientSocialHistoryOptions) error {
+	c.record("UpdatePatientSocialHistory", ctx, patientID, opts)
+
+	if c.UpdatePatientSocialHistoryFunc == nil {
+		panic("athenahealthmock: Client.UpdatePatientSocialHistory called but UpdatePatientSocialHistoryFunc is not set")
+	}
+
+	return c.UpdatePatientSocialHistoryFunc(ctx, patientID, opts)
+}
+
+func (c *Client) GetHealthHistoryFormForAppointment(ctx context.Context, appointmentID string, formID string) (*athenahealth.HealthHistoryForm, error) {
+	c.record("GetHealthHistoryFormForAppointment", ctx, appointmentID, formID)
+
+	if c.GetHealthHistoryFormForAppointmentFunc == nil {
+		panic("athenahealthmock: Client.GetHealthHistoryFormForAppointment called but GetHealthHistoryFormForAppointmentFunc is not set")
+	}
+
+	return c.GetHealthHistoryFormForAppointmentFunc(ctx, appointmentID, formID)
+}
+
+func (c *Client) UpdateHealthHistoryFormForAppointment(ctx context.Context, appointmentID string, formID string, form *athenahealth.HealthHistoryForm) error {
+	c.record("UpdateHealthHistoryFormForAppointment", ctx, appointmentID, formID, form)
+
+	if c.UpdateHealthHistoryFormForAppointmentFunc == nil {
+		panic("athenahealthmock: Client.UpdateHealthHistoryFormForAppointment called but UpdateHealthHistoryFormForAppointmentFunc is not set")
+	}
+
+	return c.UpdateHealthHistoryFormForAppointmentFunc(ctx, appointmentID, formID, form)
+}
+
+func (c *Client) SearchAllergies(ctx context.Context, searchVal string) ([]*athenahealth.Allergy, error) {
+	c.record("SearchAllergies", ctx, searchVal)
+
+	if c.SearchAllergiesFunc == nil {
+		panic("athenahealthmock: Client.SearchAllergies called but SearchAllergiesFunc is not set")
+	}
+
+	return c.SearchAllergiesFunc(ctx, searchVal)
+}
+
+func (c *Client) ListMedications(ctx context.Context, patientID string, opts *athenahealth.ListMedicationsOptions) (*athenahealth.ListMedicationsResult, error) {
+	c.record("ListMedications", ctx, patientID, opts)
+
+	if c.ListMedicationsFunc == nil {
+		panic("athenahealthmock: Client.ListMedications called but ListMedicationsFunc is not set")
+	}
+
+	return c.ListMedicationsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) SearchMedications(ctx context.Context, searchVal string) ([]*athenahealth.SearchMedicationsResult, error) {
+	c.record("SearchMedications", ctx, searchVal)
+
+	if c.SearchMedicationsFunc == nil {
+		panic("athenahealthmock: Client.SearchMedications called but SearchMedicationsFunc is not set")
+	}
+
+	return c.SearchMedicationsFunc(ctx, searchVal)
+}
+
+func (c *Client) GetAppointment(ctx context.Context, appointmentID string) (*athenahealth.Appointment, error) {
+	c.record("GetAppointment", ctx, appointmentID)
+
+	if c.GetAppointmentFunc == nil {
+		panic("athenahealthmock: Client.GetAppointment called but GetAppointmentFunc is not set")
+	}
+
+	return c.GetAppointmentFunc(ctx, appointmentID)
+}
+
+func (c *Client) ListBookedAppointments(ctx context.Context, opts *athenahealth.ListBookedAppointmentsOptions) (*athenahealth.ListBookedAppointmentsResult, error) {
+	c.record("ListBookedAppointments", ctx, opts)
+
+	if c.ListBookedAppointmentsFunc == nil {
+		panic("athenahealthmock: Client.ListBookedAppointments called but ListBookedAppointmentsFunc is not set")
+	}
+
+	return c.ListBookedAppointmentsFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedAppointments(ctx context.Context, opts *athenahealth.ListChangedAppointmentsOptions) ([]*athenahealth.BookedAppointment, error) {
+	c.record("ListChangedAppointments", ctx, opts)
+
+	if c.ListChangedAppointmentsFunc == nil {
+		panic("athenahealthmock: Client.ListChangedAppointments called but ListChangedAppointmentsFunc is not set")
+	}
+
+	return c.ListChangedAppointmentsFunc(ctx, opts)
+}
+
+func (c *Client) ListOpenAppointmentSlots(ctx context.Context, departmentID int, opts *athenahealth.ListOpenAppointmentSlotOptions) (*athenahealth.ListOpenAppointmentSlotsResult, error) {
+	c.record("ListOpenAppointmentSlots", ctx, departmentID, opts)
+
+	if c.ListOpenAppointmentSlotsFunc == nil {
+		panic("athenahealthmock: Client.ListOpenAppointmentSlots called but ListOpenAppointmentSlotsFunc is not set")
+	}
+
+	return c.ListOpenAppointmentSlotsFunc(ctx, departmentID, opts)
+}
+
+func (c *Client) BookAppointment(ctx context.Context, patientID string, apptID string, opts *athenahealth.BookAppointmentOptions) (*athenahealth.BookedAppointment, error) {
+	c.record("BookAppointment", ctx, patientID, apptID, opts)
+
+	if c.BookAppointmentFunc == nil {
+		panic("athenahealthmock: Client.BookAppointment called but BookAppointmentFunc is not set")
+	}
+
+	return c.BookAppointmentFunc(ctx, patientID, apptID, opts)
+}
+
+func (c *Client) UpdateBookedAppointment(ctx context.Context, apptID string, opts *athenahealth.UpdateBookedAppointmentOptions) error {
+	c.record("UpdateBookedAppointment", ctx, apptID, opts)
+
+	if c.UpdateBookedAppointmentFunc == nil {
+		panic("athenahealthmock: Client.UpdateBookedAppointment called but UpdateBookedAppointmentFunc is not set")
+	}
+
+	return c.UpdateBookedAppointmentFunc(ctx, apptID, opts)
+}
+
+func (c *Client) RescheduleAppointment(ctx context.Context, apptID int, opts *athenahealth.RescheduleAppointmentOptions) (*athenahealth.RescheduleAppointmentResult, error) {
+	c.record("RescheduleAppointment", ctx, apptID, opts)
+
+	if c.RescheduleAppointmentFunc == nil {
+		panic("athenahealthmock: Client.RescheduleAppointment called but RescheduleAppointmentFunc is not set")
+	}
+
+	return c.RescheduleAppointmentFunc(ctx, apptID, opts)
+}
+
+func (c *Client) ListAppointmentReminders(ctx context.Context, opts *athenahealth.ListAppointmentRemindersOptions) (*athenahealth.ListAppointmentRemindersResult, error) {
+	c.record("ListAppointmentReminders", ctx, opts)
+
+	if c.ListAppointmentRemindersFunc == nil {
+		panic("athenahealthmock: Client.ListAppointmentReminders called but ListAppointmentRemindersFunc is not set")
+	}
+
+	return c.ListAppointmentRemindersFunc(ctx, opts)
+}
+
+func (c *Client) CreateAppointmentSlot(ctx context.Context, opts *athenahealth.CreateAppointmentSlotOptions) (*athenahealth.CreateAppointmentSlotResult, error) {
+	c.record("CreateAppointmentSlot", ctx, opts)
+
+	if c.CreateAppointmentSlotFunc == nil {
+		panic("athenahealthmock: Client.CreateAppointmentSlot called but CreateAppointmentSlotFunc is not set")
+	}
+
+	return c.CreateAppointmentSlotFunc(ctx, opts)
+}
+
+func (c *Client) CreateAppointmentType(ctx context.Context, options *athenahealth.CreateAppointmentTypeOptions) (*athenahealth.CreateAppointmentTypeResult, error) {
+	c.record("CreateAppointmentType", ctx, options)
+
+	if c.CreateAppointmentTypeFunc == nil {
+		panic("athenahealthmock: Client.CreateAppointmentType called but CreateAppointmentTypeFunc is not set")
+	}
+
+	return c.CreateAppointmentTypeFunc(ctx, options)
+}
+
+func (c *Client) ListAppointmentCustomFields(ctx context.Context) ([]*athenahealth.AppointmentCustomField, error) {
+	c.record("ListAppointmentCustomFields", ctx)
+
+	if c.ListAppointmentCustomFieldsFunc == nil {
+		panic("athenahealthmock: Client.ListAppointmentCustomFields called but ListAppointmentCustomFieldsFunc is not set")
+	}
+
+	return c.ListAppointmentCustomFieldsFunc(ctx)
+}
+
+func (c *Client) FreezeAppointmentSlot(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error {
+	c.record("FreezeAppointmentSlot", ctx, appointmentID, opts)
+
+	if c.FreezeAppointmentSlotFunc == nil {
+		panic("athenahealthmock: Client.FreezeAppointmentSlot called but FreezeAppointmentSlotFunc is not set")
+	}
+
+	return c.FreezeAppointmentSlotFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) UnfreezeAppointmentSlot(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error {
+	c.record("UnfreezeAppointmentSlot", ctx, appointmentID, opts)
+
+	if c.UnfreezeAppointmentSlotFunc == nil {
+		panic("athenahealthmock: Client.UnfreezeAppointmentSlot called but UnfreezeAppointmentSlotFunc is not set")
+	}
+
+	return c.UnfreezeAppointmentSlotFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) AppointmentCancelCheckIn(ctx context.Context, apptID string) error {
+	c.record("AppointmentCancelCheckIn", ctx, apptID)
+
+	if c.AppointmentCancelCheckInFunc == nil {
+		panic("athenahealthmock: Client.AppointmentCancelCheckIn called but AppointmentCancelCheckInFunc is not set")
+	}
+
+	return c.AppointmentCancelCheckInFunc(ctx, apptID)
+}
+
+func (c *Client) AppointmentCheckIn(ctx context.Context, apptID string) error {
+	c.record("AppointmentCheckIn", ctx, apptID)
+
+	if c.AppointmentCheckInFunc == nil {
+		panic("athenahealthmock: Client.AppointmentCheckIn called but AppointmentCheckInFunc is not set")
+	}
+
+	return c.AppointmentCheckInFunc(ctx, apptID)
+}
+
+func (c *Client) AppointmentCheckOut(ctx context.Context, apptID string) error {
+	c.record("AppointmentCheckOut", ctx, apptID)
+
+	if c.AppointmentCheckOutFunc == nil {
+		panic("athenahealthmock: Client.AppointmentCheckOut called but AppointmentCheckOutFunc is not set")
+	}
+
+	return c.AppointmentCheckOutFunc(ctx, apptID)
+}
+
+func (c *Client) AppointmentStartCheckIn(ctx context.Context, apptID string) error {
+	c.record("AppointmentStartCheckIn", ctx, apptID)
+
+	if c.AppointmentStartCheckInFunc == nil {
+		panic("athenahealthmock: Client.AppointmentStartCheckIn called but AppointmentStartCheckInFunc is not set")
+	}
+
+	return c.AppointmentStartCheckInFunc(ctx, apptID)
+}
+
+func (c *Client) CreateAppointmentNote(ctx context.Context, appointmentID string, opts *athenahealth.CreateAppointmentNoteOptions) error {
+	c.record("CreateAppointmentNote", ctx, appointmentID, opts)
+
+	if c.CreateAppointmentNoteFunc == nil {
+		panic("athenahealthmock: Client.CreateAppointmentNote called but CreateAppointmentNoteFunc is not set")
+	}
+
+	return c.CreateAppointmentNoteFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) DeleteAppointmentNote(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.DeleteAppointmentNoteOptions) error {
+	c.record("DeleteAppointmentNote", ctx, appointmentID, noteID, opts)
+
+	if c.DeleteAppointmentNoteFunc == nil {
+		panic("athenahealthmock: Client.DeleteAppointmentNote called but DeleteAppointmentNoteFunc is not set")
+	}
+
+	return c.DeleteAppointmentNoteFunc(ctx, appointmentID, noteID, opts)
+}
+
+func (c *Client) ListAppointmentNotes(ctx context.Context, appointmentID string, opts *athenahealth.ListAppointmentNotesOptions) ([]*athenahealth.AppointmentNote, error) {
+	c.record("ListAppointmentNotes", ctx, appointmentID, opts)
+
+	if c.ListAppointmentNotesFunc == nil {
+		panic("athenahealthmock: Client.ListAppointmentNotes called but ListAppointmentNotesFunc is not set")
+	}
+
+	return c.ListAppointmentNotesFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) UpdateAppointmentNote(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.UpdateAppointmentNoteOptions) error {
+	c.record("UpdateAppointmentNote", ctx, appointmentID, noteID, opts)
+
+	if c.UpdateAppointmentNoteFunc == nil {
+		panic("athenahealthmock: Client.UpdateAppointmentNote called but UpdateAppointmentNoteFunc is not set")
+	}
+
+	return c.UpdateAppointmentNoteFunc(ctx, appointmentID, noteID, opts)
+}
+
+func (c *Client) GetPhysicalExam(ctx context.Context, encounterID string, opts *athenahealth.GetPhysicalExamOpts) (*athenahealth.PhysicalExam, error) {
+	c.record("GetPhysicalExam", ctx, encounterID, opts)
+
+	if c.GetPhysicalExamFunc == nil {
+		panic("athenahealthmock: Client.GetPhysicalExam called but GetPhysicalExamFunc is not set")
+	}
+
+	return c.GetPhysicalExamFunc(ctx, encounterID, opts)
+}
+
+func (c *Client) ListEncounterDocuments(ctx context.Context, departmentID string, patientID string, opts *athenahealth.ListEncounterDocumentsOptions) (*athenahealth.ListEncounterDocumentsResult, error) {
+	c.record("ListEncounterDocuments", ctx, departmentID, patientID, opts)
+
+	if c.ListEncounterDocumentsFunc == nil {
+		panic("athenahealthmock: Client.ListEncounterDocuments called but ListEncounterDocumentsFunc is not set")
+	}
+
+	return c.ListEncounterDocumentsFunc(ctx, departmentID, patientID, opts)
+}
+
+func (c *Client) EncounterSummary(ctx context.Context, encounterID string, opts *athenahealth.EncounterSummaryOptions) (*athenahealth.EncounterSummaryResponse, error) {
+	c.record("EncounterSummary", ctx, encounterID, opts)
+
+	if c.EncounterSummaryFunc == nil {
+		panic("athenahealthmock: Client.EncounterSummary called but EncounterSummaryFunc is not set")
+	}
+
+	return c.EncounterSummaryFunc(ctx, encounterID, opts)
+}
+
+func (c *Client) ListProviders(ctx context.Context, opts *athenahealth.ListProvidersOptions) (*athenahealth.ListProvidersResult, error) {
+	c.record("ListProviders", ctx, opts)
+
+	if c.ListProvidersFunc == nil {
+		panic("athenahealthmock: Client.ListProviders called but ListProvidersFunc is not set")
+	}
+
+	return c.ListProvidersFunc(ctx, opts)
+}
+
+func (c *Client) GetProvider(ctx context.Context, providerID string) (*athenahealth.Provider, error) {
+	c.record("GetProvider", ctx, providerID)
+
+	if c.GetProviderFunc == nil {
+		panic("athenahealthmock: Client.GetProvider called but GetProviderFunc is not set")
+	}
+
+	return c.GetProviderFunc(ctx, providerID)
+}
+
+func (c *Client) GetSubscription(ctx context.Context, feedType string) (*athenahealth.Subscription, error) {
+	c.record("GetSubscription", ctx, feedType)
+
+	if c.GetSubscriptionFunc == nil {
+		panic("athenahealthmock: Client.GetSubscription called but GetSubscriptionFunc is not set")
+	}
+
+	return c.GetSubscriptionFunc(ctx, feedType)
+}
+
+func (c *Client) ListSubscriptionEvents(ctx context.Context, feedType string) ([]*athenahealth.SubscriptionEvent, error) {
+	c.record("ListSubscriptionEvents", ctx, feedType)
+
+	if c.ListSubscriptionEventsFunc == nil {
+		panic("athenahealthmock: Client.ListSubscriptionEvents called but ListSubscriptionEventsFunc is not set")
+	}
+
+	return c.ListSubscriptionEventsFunc(ctx, feedType)
+}
+
+func (c *Client) Subscribe(ctx context.Context, feedType string, opts *athenahealth.SubscribeOptions) error {
+	c.record("Subscribe", ctx, feedType, opts)
+
+	if c.SubscribeFunc == nil {
+		panic("athenahealthmock: Client.Subscribe called but SubscribeFunc is not set")
+	}
+
+	return c.SubscribeFunc(ctx, feedType, opts)
+}
+
+func (c *Client) Unsubscribe(ctx context.Context, feedType string, opts *athenahealth.UnsubscribeOptions) error {
+	c.record("Unsubscribe", ctx, feedType, opts)
+
+	if c.UnsubscribeFunc == nil {
+		panic("athenahealthmock: Client.Unsubscribe called but UnsubscribeFunc is not set")
+	}
+
+	return c.UnsubscribeFunc(ctx, feedType, opts)
+}
+
+func (c *Client) ListChangedPatients(ctx context.Context, opts *athenahealth.ListChangedPatientOptions) ([]*athenahealth.Patient, error) {
+	c.record("ListChangedPatients", ctx, opts)
+
+	if c.ListChangedPatientsFunc == nil {
+		panic("athenahealthmock: Client.ListChangedPatients called but ListChangedPatientsFunc is not set")
+	}
+
+	return c.ListChangedPatientsFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedProviders(ctx context.Context, opts *athenahealth.ListChangedProviderOptions) ([]*athenahealth.Provider, error) {
+	c.record("ListChangedProviders", ctx, opts)
+
+	if c.ListChangedProvidersFunc == nil {
+		panic("athenahealthmock: Client.ListChangedProviders called but ListChangedProvidersFunc is not set")
+	}
+
+	return c.ListChangedProvidersFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedProblems(ctx context.Context, opts *athenahealth.ListChangedProblemsOptions) ([]*athenahealth.ChangedProblem, error) {
+	c.record("ListChangedProblems", ctx, opts)
+
+	if c.ListChangedProblemsFunc == nil {
+		panic("athenahealthmock: Client.ListChangedProblems called but ListChangedProblemsFunc is not set")
+	}
+
+	return c.ListChangedProblemsFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedPrescriptions(ctx context.Context, options *athenahealth.ListChangedPrescriptionsOptions) (*athenahealth.ListChangedPrescriptionsResult, error) {
+	c.record("ListChangedPrescriptions", ctx, options)
+
+	if c.ListChangedPrescriptionsFunc == nil {
+		panic("athenahealthmock: Client.ListChangedPrescriptions called but ListChangedPrescriptionsFunc is not set")
+	}
+
+	return c.ListChangedPrescriptionsFunc(ctx, options)
+}
+
+func (c *Client) UpdatePrescription(ctx context.Context, departmentID int, patientID int, documentID int, opts *athenahealth.UpdatePrescriptionOptions) (*athenahealth.UpdatePrescriptionResult, error) {
+	c.record("UpdatePrescription", ctx, departmentID, patientID, documentID, opts)
+
+	if c.UpdatePrescriptionFunc == nil {
+		panic("athenahealthmock: Client.UpdatePrescription called but UpdatePrescriptionFunc is not set")
+	}
+
+	return c.UpdatePrescriptionFunc(ctx, departmentID, patientID, documentID, opts)
+}
+
+func (c *Client) CreateFinancialClaim(ctx context.Context, opts *athenahealth.CreateClaimOptions) ([]string, error) {
+	c.record("CreateFinancialClaim", ctx, opts)
+
+	if c.CreateFinancialClaimFunc == nil {
+		panic("athenahealthmock: Client.CreateFinancialClaim called but CreateFinancialClaimFunc is not set")
+	}
+
+	return c.CreateFinancialClaimFunc(ctx, opts)
+}
+
+func (c *Client) ListClaims(ctx context.Context, opts *athenahealth.ListClaimsOptions) (*athenahealth.ListClaimsResult, error) {
+	c.record("ListClaims", ctx, opts)
+
+	if c.ListClaimsFunc == nil {
+		panic("athenahealthmock: Client.ListClaims called but ListClaimsFunc is not set")
+	}
+
+	return c.ListClaimsFunc(ctx, opts)
+}
+
+func (c *Client) GetTelehealthInviteURL(ctx context.Context, apptID string) (*athenahealth.GetTelehealthInviteURLResult, error) {
+	c.record("GetTelehealthInviteURL", ctx, apptID)
+
+	if c.GetTelehealthInviteURLFunc == nil {
+		panic("athenahealthmock: Client.GetTelehealthInviteURL called but GetTelehealthInviteURLFunc is not set")
+	}
+
+	return c.GetTelehealthInviteURLFunc(ctx, apptID)
+}
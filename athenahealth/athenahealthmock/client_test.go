@@ -0,0 +1,54 @@
+package athenahealthmock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &Client{
+		GetPatientFunc: func(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error) {
+			return &athenahealth.Patient{PatientID: patientID}, nil
+		},
+		ListCustomFieldsFunc: func(ctx context.Context) ([]*athenahealth.CustomField, error) {
+			return nil, athenahealth.ErrNotFound
+		},
+	}
+
+	var athenaClient athenahealth.Client = client
+
+	patient, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+	assert.Equal("1", patient.PatientID)
+
+	_, err = athenaClient.ListCustomFields(context.Background())
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	calls := client.Calls()
+	if assert.Len(calls, 2) {
+		assert.Equal("GetPatient", calls[0].Method)
+		assert.Equal("1", calls[0].Args[1])
+		assert.Equal("ListCustomFields", calls[1].Method)
+	}
+
+	assert.Len(client.Calls("ListCustomFields"), 1)
+
+	client.Reset()
+	assert.Empty(client.Calls())
+}
+
+func TestClient_func_not_set(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &Client{}
+
+	assert.PanicsWithValue("athenahealthmock: Client.GetPatient called but GetPatientFunc is not set", func() {
+		client.GetPatient(context.Background(), "1", nil)
+	})
+	assert.Len(client.Calls("GetPatient"), 1)
+}
@@ -0,0 +1,464 @@
+package athenatest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// appointment returns the appointment named by the appointmentid path parameter, or an error response if it
+// does not exist.
+func (r *request) appointment() (*athenahealth.BookedAppointment, int, interface{}) {
+	appointment, ok := r.practice.appointments[r.param("appointmentid")]
+	if !ok {
+		status, body := notFound("The appointment is not found.")
+
+		return nil, status, body
+	}
+
+	return appointment, 0, nil
+}
+
+// sortedAppointments returns the appointments that match filter, sorted by date and start time.
+func sortedAppointments(p *practice, filter func(*athenahealth.BookedAppointment) bool) []*athenahealth.BookedAppointment {
+	appointments := []*athenahealth.BookedAppointment{}
+
+	for _, appointment := range sortedByID(p.appointments) {
+		if filter(appointment) {
+			appointments = append(appointments, appointment)
+		}
+	}
+
+	sort.SliceStable(appointments, func(i, j int) bool {
+		return appointmentTime(appointments[i]).Before(appointmentTime(appointments[j]))
+	})
+
+	return appointments
+}
+
+func appointmentTime(appointment *athenahealth.BookedAppointment) time.Time {
+	t, _ := time.Parse(dateFormat+" "+timeFormat, appointment.Date+" "+appointment.StartTime)
+
+	return t
+}
+
+// inDateRange reports whether an appointment is between the startdate and enddate query parameters, inclusive.
+func inDateRange(r *request, appointment *athenahealth.BookedAppointment) bool {
+	date, err := parseDate(appointment.Date)
+	if err != nil {
+		return false
+	}
+
+	if startDate, err := parseDate(r.form.Get("startdate")); err == nil && date.Before(startDate) {
+		return false
+	}
+
+	if endDate, err := parseDate(r.form.Get("enddate")); err == nil && date.After(endDate) {
+		return false
+	}
+
+	return true
+}
+
+func isFrozen(appointment *athenahealth.BookedAppointment) bool {
+	return appointment.FrozenYN == "Y"
+}
+
+func listOpenAppointmentSlots(r *request) (int, interface{}) {
+	if status, body, missing := missingFields(r.form, "departmentid"); missing {
+		return status, body
+	}
+
+	providerIDs := make(map[string]bool)
+	for _, providerID := range strings.Split(r.form.Get("providerid"), ",") {
+		if len(providerID) > 0 {
+			providerIDs[providerID] = true
+		}
+	}
+
+	showFrozenSlots, _ := strconv.ParseBool(r.form.Get("showfrozenslots"))
+
+	appointments := sortedAppointments(r.practice, func(appointment *athenahealth.BookedAppointment) bool {
+		return appointment.AppointmentStatus == athenahealth.AppointmentStatusOpen &&
+			appointment.DepartmentID == r.form.Get("departmentid") &&
+			matches(r.form.Get("appointmenttypeid"), appointment.AppointmentTypeID) &&
+			(len(providerIDs) == 0 || providerIDs[appointment.ProviderID]) &&
+			(showFrozenSlots || !isFrozen(appointment)) &&
+			inDateRange(r, appointment)
+	})
+
+	slots := make([]*athenahealth.OpenAppointmentSlot, 0, len(appointments))
+
+	for _, appointment := range appointments {
+		appointmentID, _ := strconv.Atoi(appointment.AppointmentID)
+		appointmentTypeID, _ := strconv.Atoi(appointment.AppointmentTypeID)
+		departmentID, _ := strconv.Atoi(appointment.DepartmentID)
+		providerID, _ := strconv.Atoi(appointment.ProviderID)
+
+		slots = append(slots, &athenahealth.OpenAppointmentSlot{
+			AppointmentID:              appointmentID,
+			AppointmentType:            appointment.AppointmentType,
+			AppointmentTypeID:          appointmentTypeID,
+			Date:                       appointment.Date,
+			DepartmentID:               departmentID,
+			Duration:                   appointment.Duration,
+			Frozen:                     isFrozen(appointment),
+			LocalProviderID:            providerID,
+			PatientAppointmentTypeName: appointment.PatientAppointmentTypeName,
+			ProviderID:                 providerID,
+			StartTime:                  appointment.StartTime,
+		})
+	}
+
+	page, p := paginate(r, slots)
+
+	return http.StatusOK, &struct {
+		Appointments []*athenahealth.OpenAppointmentSlot `json:"appointments"`
+		pagination
+	}{page, p}
+}
+
+func createAppointmentSlot(r *request) (int, interface{}) {
+	if status, body, missing := missingFields(r.form, "appointmentdate", "appointmenttime", "departmentid", "providerid"); missing {
+		return status, body
+	}
+
+	_, err := parseDate(r.form.Get("appointmentdate"))
+	if err != nil {
+		return badRequest("Invalid appointment date.")
+	}
+
+	startTimes := strings.Split(r.form.Get("appointmenttime"), ",")
+	for _, startTime := range startTimes {
+		_, err := time.Parse(timeFormat, startTime)
+		if err != nil {
+			return badRequest("Invalid appointment time " + startTime + ".")
+		}
+	}
+
+	appointmentIDs := make(map[string]string)
+
+	for _, startTime := range startTimes {
+		appointment := &athenahealth.BookedAppointment{
+			AppointmentID:     r.practice.newID(),
+			AppointmentStatus: athenahealth.AppointmentStatusOpen,
+			AppointmentTypeID: r.form.Get("appointmenttypeid"),
+			Date:              r.form.Get("appointmentdate"),
+			DepartmentID:      r.form.Get("departmentid"),
+			FrozenYN:          "N",
+			ProviderID:        r.form.Get("providerid"),
+			StartTime:         startTime,
+		}
+
+		r.practice.appointments[appointment.AppointmentID] = appointment
+		appointmentIDs[startTime] = appointment.AppointmentID
+	}
+
+	return http.StatusOK, &athenahealth.CreateAppointmentSlotResult{
+		AppointmentIDs: appointmentIDs,
+	}
+}
+
+// book books an open slot for a patient, returning an error response if the slot or patient can not be booked.
+func book(r *request, appointment *athenahealth.BookedAppointment, patientID string) (int, interface{}, bool) {
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusOpen {
+		status, body := badRequest("The appointment ID is already booked or is not a valid appointment.")
+
+		return status, body, false
+	}
+
+	if isFrozen(appointment) {
+		status, body := badRequest("The appointment slot is frozen.")
+
+		return status, body, false
+	}
+
+	if _, ok := r.practice.patients[patientID]; !ok {
+		status, body := notFound("The patient is not found.")
+
+		return status, body, false
+	}
+
+	now := time.Now()
+
+	appointment.AppointmentStatus = athenahealth.AppointmentStatusFuture
+	appointment.PatientID = patientID
+	appointment.ScheduledDatetime = now.Format(datetimeFormat)
+	appointment.LastModified = now.Format(datetimeFormat)
+
+	return 0, nil, true
+}
+
+func bookAppointment(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "patientid"); missing {
+		return status, body
+	}
+
+	if status, body, ok := book(r, appointment, r.form.Get("patientid")); !ok {
+		return status, body
+	}
+
+	if appointmentTypeID := r.form.Get("appointmenttypeid"); len(appointmentTypeID) > 0 {
+		appointment.AppointmentTypeID = appointmentTypeID
+	}
+
+	if departmentID := r.form.Get("departmentid"); len(departmentID) > 0 {
+		appointment.DepartmentID = departmentID
+	}
+
+	appointment.UrgentYN = "N"
+	if urgent, _ := strconv.ParseBool(r.form.Get("urgent")); urgent {
+		appointment.UrgentYN = "Y"
+	}
+
+	r.practice.appointmentChanged("ScheduleAppointment", appointment.AppointmentID)
+
+	return http.StatusOK, []*athenahealth.BookedAppointment{appointment}
+}
+
+func getAppointment(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	return http.StatusOK, []*athenahealth.Appointment{{
+		AppointmentID:              appointment.AppointmentID,
+		AppointmentStatus:          appointment.AppointmentStatus,
+		AppointmentType:            appointment.AppointmentType,
+		AppointmentTypeID:          appointment.AppointmentTypeID,
+		ChargeEntryNotRequired:     appointment.ChargeEntryNotRequired,
+		Date:                       appointment.Date,
+		DepartmentID:               appointment.DepartmentID,
+		Duration:                   appointment.Duration,
+		EncounterID:                appointment.EncounterID,
+		PatientAppointmentTypeName: appointment.PatientAppointmentTypeName,
+		PatientID:                  appointment.PatientID,
+		ProviderID:                 appointment.ProviderID,
+		RenderingProviderID:        appointment.RenderingProviderID,
+		StartTime:                  appointment.StartTime,
+	}}
+}
+
+func listBookedAppointments(r *request) (int, interface{}) {
+	if status, body, missing := missingFields(r.form, "enddate", "startdate"); missing {
+		return status, body
+	}
+
+	if len(r.form.Get("departmentid")) == 0 && len(r.form.Get("providerid")) == 0 {
+		return badRequest("Either a department ID or a provider ID is required.")
+	}
+
+	appointments := sortedAppointments(r.practice, func(appointment *athenahealth.BookedAppointment) bool {
+		return appointment.AppointmentStatus != athenahealth.AppointmentStatusOpen &&
+			matches(r.form.Get("departmentid"), appointment.DepartmentID) &&
+			matches(r.form.Get("providerid"), appointment.ProviderID) &&
+			matches(r.form.Get("patientid"), appointment.PatientID) &&
+			matches(r.form.Get("appointmentstatus"), appointment.AppointmentStatus.String()) &&
+			inDateRange(r, appointment)
+	})
+
+	page, p := paginate(r, appointments)
+
+	return http.StatusOK, &struct {
+		Appointments []*athenahealth.BookedAppointment `json:"appointments"`
+		pagination
+	}{page, p}
+}
+
+func updateBookedAppointment(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus == athenahealth.AppointmentStatusOpen ||
+		appointment.AppointmentStatus == athenahealth.AppointmentStatusCancelled {
+		return badRequest("The appointment is not booked.")
+	}
+
+	if appointmentTypeID := r.form.Get("appointmenttypeid"); len(appointmentTypeID) > 0 {
+		appointment.AppointmentTypeID = appointmentTypeID
+	}
+
+	if departmentID := r.form.Get("departmentid"); len(departmentID) > 0 {
+		appointment.DepartmentID = departmentID
+	}
+
+	if providerID := r.form.Get("providerid"); len(providerID) > 0 {
+		appointment.ProviderID = providerID
+	}
+
+	if supervisingProviderID := r.form.Get("supervisingproviderid"); len(supervisingProviderID) > 0 {
+		appointment.SupervisingProviderID = athenahealth.NumberString(supervisingProviderID)
+	}
+
+	appointment.LastModified = time.Now().Format(datetimeFormat)
+
+	r.practice.appointmentChanged("UpdateAppointment", appointment.AppointmentID)
+
+	// athena returns the number of updated appointments.
+	return http.StatusOK, "1"
+}
+
+func rescheduleAppointment(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "newappointmentid", "patientid"); missing {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusFuture || appointment.PatientID != r.form.Get("patientid") {
+		return badRequest("The appointment can not be rescheduled.")
+	}
+
+	newAppointment, ok := r.practice.appointments[r.form.Get("newappointmentid")]
+	if !ok {
+		return notFound("The new appointment is not found.")
+	}
+
+	if status, body, ok := book(r, newAppointment, appointment.PatientID); !ok {
+		return status, body
+	}
+
+	newAppointment.AppointmentTypeID = appointment.AppointmentTypeID
+	newAppointment.AppointmentType = appointment.AppointmentType
+	newAppointment.UrgentYN = appointment.UrgentYN
+
+	appointment.AppointmentStatus = athenahealth.AppointmentStatusCancelled
+	appointment.CancelledDatetime = time.Now().Format(datetimeFormat)
+	appointment.CancelReasonID = r.form.Get("appointmentcancelreasonid")
+	appointment.RescheduledAppointmentID = newAppointment.AppointmentID
+
+	r.practice.appointmentChanged("RescheduleAppointment", appointment.AppointmentID)
+	r.practice.appointmentChanged("ScheduleAppointment", newAppointment.AppointmentID)
+
+	return http.StatusOK, []*athenahealth.RescheduleAppointmentResult{{
+		AppointmentID:            newAppointment.AppointmentID,
+		AppointmentStatus:        newAppointment.AppointmentStatus,
+		AppointmentType:          newAppointment.AppointmentType,
+		AppointmentTypeID:        newAppointment.AppointmentTypeID,
+		Date:                     newAppointment.Date,
+		DepartmentID:             newAppointment.DepartmentID,
+		Duration:                 newAppointment.Duration,
+		FrozenYN:                 newAppointment.FrozenYN,
+		PatientID:                newAppointment.PatientID,
+		ProviderID:               newAppointment.ProviderID,
+		RescheduledAppointmentID: appointment.AppointmentID,
+		StartTime:                newAppointment.StartTime,
+		UrgentYN:                 newAppointment.UrgentYN,
+	}}
+}
+
+func freezeAppointmentSlot(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusOpen {
+		return badRequest("Only open appointment slots can be frozen.")
+	}
+
+	freeze, err := strconv.ParseBool(r.form.Get("freeze"))
+	if err != nil {
+		return badRequest("Invalid freeze.")
+	}
+
+	if freeze == isFrozen(appointment) {
+		message := "The appointment slot is already unfrozen."
+		if freeze {
+			message = "The appointment slot is already frozen."
+		}
+
+		return http.StatusOK, &athenahealth.ErrorMessageResponse{Message: message}
+	}
+
+	appointment.FrozenYN = "N"
+	if freeze {
+		appointment.FrozenYN = "Y"
+	}
+
+	return success()
+}
+
+func startCheckIn(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusFuture || len(appointment.StartCheckIn) > 0 {
+		return badRequest("Check-in can not be started for this appointment.")
+	}
+
+	appointment.StartCheckIn = time.Now().Format(datetimeFormat)
+
+	return http.StatusOK, &athenahealth.MessageResponse{Success: true}
+}
+
+func cancelCheckIn(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusFuture || len(appointment.StartCheckIn) == 0 {
+		return badRequest("Check-in has not been started for this appointment.")
+	}
+
+	appointment.StartCheckIn = ""
+
+	return http.StatusOK, &athenahealth.MessageResponse{Success: true}
+}
+
+func checkIn(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusFuture || len(appointment.StartCheckIn) == 0 {
+		return badRequest("Check-in must be started before the appointment is checked in.")
+	}
+
+	now := time.Now().Format(datetimeFormat)
+
+	appointment.AppointmentStatus = athenahealth.AppointmentStatusCheckedIn
+	appointment.CheckInDateTime = now
+	appointment.StopCheckIn = now
+
+	r.practice.appointmentChanged("CheckInAppointment", appointment.AppointmentID)
+
+	return http.StatusOK, &athenahealth.MessageResponse{Success: true}
+}
+
+func checkOut(r *request) (int, interface{}) {
+	appointment, status, body := r.appointment()
+	if appointment == nil {
+		return status, body
+	}
+
+	if appointment.AppointmentStatus != athenahealth.AppointmentStatusCheckedIn {
+		return badRequest("The appointment must be checked in before it is checked out.")
+	}
+
+	appointment.AppointmentStatus = athenahealth.AppointmentStatusCheckedOut
+	appointment.CheckOutDateTime = time.Now().Format(datetimeFormat)
+
+	r.practice.appointmentChanged("CheckOutAppointment", appointment.AppointmentID)
+
+	return http.StatusOK, &athenahealth.ErrorMessageResponse{Success: true}
+}
@@ -0,0 +1,180 @@
+package athenatest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCreateAppointmentSlots(t *testing.T, athenaClient *athenahealth.HTTPClient, times ...string) map[string]string {
+	res, err := athenaClient.CreateAppointmentSlot(context.Background(), &athenahealth.CreateAppointmentSlotOptions{
+		AppointmentDate: "06/01/2030",
+		AppointmentTime: times,
+		DepartmentID:    1,
+		ProviderID:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.AppointmentIDs
+}
+
+func TestServer_appointments(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+	appointmentIDs := testCreateAppointmentSlots(t, athenaClient, "10:00", "09:00")
+
+	open, err := athenaClient.ListOpenAppointmentSlots(context.Background(), 1, &athenahealth.ListOpenAppointmentSlotOptions{
+		ProviderIDs: []int{2},
+		StartDate:   time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(err)
+	if assert.Len(open.Appointments, 2) {
+		assert.Equal("09:00", open.Appointments[0].StartTime)
+		assert.Equal("10:00", open.Appointments[1].StartTime)
+	}
+
+	booked, err := athenaClient.BookAppointment(context.Background(), patientID, appointmentIDs["09:00"], &athenahealth.BookAppointmentOptions{
+		AppointmentTypeID: 3,
+	})
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusFuture, booked.AppointmentStatus)
+	assert.Equal(patientID, booked.PatientID)
+	assert.Equal("3", booked.AppointmentTypeID)
+
+	// Booked slots are no longer open and can not be booked again.
+	open, err = athenaClient.ListOpenAppointmentSlots(context.Background(), 1, nil)
+	assert.NoError(err)
+	assert.Len(open.Appointments, 1)
+
+	_, err = athenaClient.BookAppointment(context.Background(), patientID, appointmentIDs["09:00"], nil)
+	assert.ErrorContains(err, "already booked")
+
+	list, err := athenaClient.ListBookedAppointments(context.Background(), &athenahealth.ListBookedAppointmentsOptions{
+		DepartmentID: "1",
+		StartDate:    time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(err)
+	if assert.Len(list.BookedAppointments, 1) {
+		assert.Equal(booked.AppointmentID, list.BookedAppointments[0].AppointmentID)
+	}
+
+	providerID := "4"
+	err = athenaClient.UpdateBookedAppointment(context.Background(), booked.AppointmentID, &athenahealth.UpdateBookedAppointmentOptions{
+		ProviderID: &providerID,
+	})
+	assert.NoError(err)
+
+	appointment, err := athenaClient.GetAppointment(context.Background(), booked.AppointmentID)
+	assert.NoError(err)
+	assert.Equal(providerID, appointment.ProviderID)
+}
+
+func TestServer_reschedule_appointment(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+	appointmentIDs := testCreateAppointmentSlots(t, athenaClient, "09:00", "10:00")
+
+	_, err := athenaClient.BookAppointment(context.Background(), patientID, appointmentIDs["09:00"], nil)
+	assert.NoError(err)
+
+	appointmentID, _ := strconv.Atoi(appointmentIDs["09:00"])
+	newAppointmentID, _ := strconv.Atoi(appointmentIDs["10:00"])
+	patientIDInt, _ := strconv.Atoi(patientID)
+
+	res, err := athenaClient.RescheduleAppointment(context.Background(), appointmentID, &athenahealth.RescheduleAppointmentOptions{
+		NewAppointmentID: newAppointmentID,
+		PatientID:        patientIDInt,
+	})
+	assert.NoError(err)
+	assert.Equal(appointmentIDs["10:00"], res.AppointmentID)
+	assert.Equal(appointmentIDs["09:00"], res.RescheduledAppointmentID)
+	assert.Equal(athenahealth.AppointmentStatusFuture, res.AppointmentStatus)
+
+	original, err := athenaClient.GetAppointment(context.Background(), appointmentIDs["09:00"])
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusCancelled, original.AppointmentStatus)
+}
+
+func TestServer_freeze_appointment_slot(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+	appointmentIDs := testCreateAppointmentSlots(t, athenaClient, "09:00")
+
+	err := athenaClient.FreezeAppointmentSlot(context.Background(), appointmentIDs["09:00"], nil)
+	assert.NoError(err)
+
+	err = athenaClient.FreezeAppointmentSlot(context.Background(), appointmentIDs["09:00"], nil)
+	assert.ErrorIs(err, athenahealth.ErrAppointmentSlotAlreadyFrozen)
+
+	// Frozen slots are hidden and can not be booked.
+	open, err := athenaClient.ListOpenAppointmentSlots(context.Background(), 1, nil)
+	assert.NoError(err)
+	assert.Empty(open.Appointments)
+
+	open, err = athenaClient.ListOpenAppointmentSlots(context.Background(), 1, &athenahealth.ListOpenAppointmentSlotOptions{ShowFrozenSlots: true})
+	assert.NoError(err)
+	if assert.Len(open.Appointments, 1) {
+		assert.True(open.Appointments[0].Frozen)
+	}
+
+	_, err = athenaClient.BookAppointment(context.Background(), patientID, appointmentIDs["09:00"], nil)
+	assert.ErrorContains(err, "frozen")
+
+	err = athenaClient.UnfreezeAppointmentSlot(context.Background(), appointmentIDs["09:00"], nil)
+	assert.NoError(err)
+
+	err = athenaClient.UnfreezeAppointmentSlot(context.Background(), appointmentIDs["09:00"], nil)
+	assert.ErrorIs(err, athenahealth.ErrAppointmentSlotAlreadyUnfrozen)
+}
+
+func TestServer_check_in(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+	appointmentID := testCreateAppointmentSlots(t, athenaClient, "09:00")["09:00"]
+
+	// Open slots can not be checked in.
+	assert.Error(athenaClient.AppointmentStartCheckIn(context.Background(), appointmentID))
+
+	_, err := athenaClient.BookAppointment(context.Background(), patientID, appointmentID, nil)
+	assert.NoError(err)
+
+	assert.Error(athenaClient.AppointmentCheckIn(context.Background(), appointmentID))
+	assert.Error(athenaClient.AppointmentCheckOut(context.Background(), appointmentID))
+
+	assert.NoError(athenaClient.AppointmentStartCheckIn(context.Background(), appointmentID))
+	assert.NoError(athenaClient.AppointmentCancelCheckIn(context.Background(), appointmentID))
+	assert.Error(athenaClient.AppointmentCancelCheckIn(context.Background(), appointmentID))
+
+	assert.NoError(athenaClient.AppointmentStartCheckIn(context.Background(), appointmentID))
+	assert.NoError(athenaClient.AppointmentCheckIn(context.Background(), appointmentID))
+
+	appointment, err := athenaClient.GetAppointment(context.Background(), appointmentID)
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusCheckedIn, appointment.AppointmentStatus)
+
+	assert.NoError(athenaClient.AppointmentCheckOut(context.Background(), appointmentID))
+
+	appointment, err = athenaClient.GetAppointment(context.Background(), appointmentID)
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusCheckedOut, appointment.AppointmentStatus)
+}
@@ -0,0 +1,145 @@
+package athenatest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+func listCustomFields(r *request) (int, interface{}) {
+	return http.StatusOK, sortedByID(r.practice.customFields)
+}
+
+func getPatientCustomFields(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "departmentid"); missing {
+		return status, body
+	}
+
+	customFields := patient.CustomFields
+	if customFields == nil {
+		customFields = []athenahealth.CustomFieldValue{}
+	}
+
+	return http.StatusOK, customFields
+}
+
+func updatePatientCustomFields(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "customfields", "departmentid"); missing {
+		return status, body
+	}
+
+	values := []*athenahealth.CustomFieldValue{}
+
+	err := json.Unmarshal([]byte(r.form.Get("customfields")), &values)
+	if err != nil {
+		return badRequest("The custom fields are not valid JSON.")
+	}
+
+	updated, disallowed := 0, 0
+
+	for _, value := range values {
+		customField, ok := r.practice.customFields[value.CustomFieldID]
+		if !ok {
+			return badRequest("Invalid custom field ID " + value.CustomFieldID + ".")
+		}
+
+		if customField.DisallowUpdate {
+			disallowed++
+			continue
+		}
+
+		if customField.Select {
+			option := selectOption(customField, value.OptionID)
+			if option == nil {
+				return badRequest("Invalid option ID for custom field " + value.CustomFieldID + ".")
+			}
+
+			value = &athenahealth.CustomFieldValue{
+				CustomFieldID:    value.CustomFieldID,
+				CustomFieldValue: option.OptionValue,
+				OptionID:         option.OptionID,
+			}
+		}
+
+		setCustomFieldValue(patient, value)
+		updated++
+	}
+
+	if updated > 0 {
+		r.practice.patientChanged("UpdatePatient", patient.PatientID)
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"success":         true,
+		"updatedCount":    updated,
+		"disallowedCount": disallowed,
+	}
+}
+
+func selectOption(customField *athenahealth.CustomField, optionID string) *athenahealth.SelectOption {
+	for _, option := range customField.SelectList {
+		if option.OptionID == optionID {
+			return option
+		}
+	}
+
+	return nil
+}
+
+func setCustomFieldValue(patient *athenahealth.Patient, value *athenahealth.CustomFieldValue) {
+	for i := range patient.CustomFields {
+		if patient.CustomFields[i].CustomFieldID == value.CustomFieldID {
+			patient.CustomFields[i] = *value
+			return
+		}
+	}
+
+	patient.CustomFields = append(patient.CustomFields, *value)
+}
+
+func listPatientsMatchingCustomField(r *request) (int, interface{}) {
+	customField, ok := r.practice.customFields[r.param("customfieldid")]
+	if !ok || !customField.Searchable {
+		return badRequest("The custom field is not searchable.")
+	}
+
+	search := r.param("customfieldvalue")
+
+	patients := []*athenahealth.Patient{}
+
+	for _, patient := range sortedByID(r.practice.patients) {
+		for _, value := range patient.CustomFields {
+			if value.CustomFieldID != customField.CustomFieldID {
+				continue
+			}
+
+			matched := value.CustomFieldValue == search || (len(value.OptionID) > 0 && value.OptionID == search)
+			if !customField.CaseSensitive {
+				matched = matched || strings.EqualFold(value.CustomFieldValue, search)
+			}
+
+			if matched {
+				patients = append(patients, patient)
+			}
+		}
+	}
+
+	page, p := paginate(r, patients)
+
+	return http.StatusOK, &struct {
+		Patients []*athenahealth.Patient `json:"patients"`
+		pagination
+	}{page, p}
+}
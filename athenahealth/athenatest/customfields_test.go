@@ -0,0 +1,68 @@
+package athenatest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_custom_fields(t *testing.T) {
+	assert := assert.New(t)
+
+	s, athenaClient := testClient(t)
+
+	s.Seed(testPracticeID, &Fixtures{
+		CustomFields: []*athenahealth.CustomField{
+			{CustomFieldID: "1", Name: "Member ID", Searchable: true},
+			{CustomFieldID: "2", Name: "Plan", Select: true, SelectList: []*athenahealth.SelectOption{{OptionID: "10", OptionValue: "Gold"}}},
+			{CustomFieldID: "3", Name: "Legacy ID", DisallowUpdate: true},
+		},
+	})
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	customFields, err := athenaClient.ListCustomFields(context.Background())
+	assert.NoError(err)
+	assert.Len(customFields, 3)
+
+	err = athenaClient.UpdatePatientCustomFields(context.Background(), patientID, "1", []*athenahealth.CustomFieldValue{
+		{CustomFieldID: "1", CustomFieldValue: "M-42"},
+		{CustomFieldID: "2", OptionID: "10"},
+		{CustomFieldID: "3", CustomFieldValue: "ignored"},
+	})
+	assert.NoError(err)
+
+	values, err := athenaClient.GetPatientCustomFields(context.Background(), patientID, "1")
+	assert.NoError(err)
+	assert.Equal([]*athenahealth.CustomFieldValue{
+		{CustomFieldID: "1", CustomFieldValue: "M-42"},
+		{CustomFieldID: "2", CustomFieldValue: "Gold", OptionID: "10"},
+	}, values)
+
+	// Custom fields are only shown with the patient when requested.
+	patient, err := athenaClient.GetPatient(context.Background(), patientID, nil)
+	assert.NoError(err)
+	assert.Empty(patient.CustomFields)
+
+	matching, err := athenaClient.ListPatientsMatchingCustomField(context.Background(), &athenahealth.ListPatientsMatchingCustomFieldOptions{
+		CustomFieldID:    "1",
+		CustomFieldValue: "m-42",
+	})
+	assert.NoError(err)
+	if assert.Len(matching.Patients, 1) {
+		assert.Equal(patientID, matching.Patients[0].PatientID)
+	}
+
+	_, err = athenaClient.ListPatientsMatchingCustomField(context.Background(), &athenahealth.ListPatientsMatchingCustomFieldOptions{
+		CustomFieldID:    "2",
+		CustomFieldValue: "10",
+	})
+	assert.ErrorContains(err, "not searchable")
+
+	err = athenaClient.UpdatePatientCustomFields(context.Background(), patientID, "1", []*athenahealth.CustomFieldValue{
+		{CustomFieldID: "4", CustomFieldValue: "unknown"},
+	})
+	assert.ErrorContains(err, "Invalid custom field ID")
+}
@@ -0,0 +1,129 @@
+package athenatest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+func addDocument(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "documentsubclass"); missing {
+		return status, body
+	}
+
+	documentID := r.practice.newIntID()
+
+	// Only admin documents can be listed, so other classes of document are not kept.
+	subclass := r.form.Get("documentsubclass")
+	if strings.HasPrefix(subclass, "ADMIN_") {
+		providerID, _ := strconv.Atoi(r.form.Get("providerid"))
+		now := time.Now()
+
+		r.practice.adminDocuments[patient.PatientID] = append(r.practice.adminDocuments[patient.PatientID], &athenahealth.AdminDocument{
+			AdminID:              documentID,
+			CreatedDate:          now.Format(dateFormat),
+			CreatedDateTime:      now.Format(time.RFC3339),
+			DepartmentID:         r.form.Get("departmentid"),
+			DocumentClass:        "ADMIN",
+			DocumentDate:         now.Format(dateFormat),
+			DocumentSource:       "INTERFACE",
+			InternalNote:         r.form.Get("internalnote"),
+			LastModifiedDate:     now.Format(dateFormat),
+			LastModifiedDatetime: now.Format(time.RFC3339),
+			ProviderID:           providerID,
+			Status:               "REVIEW",
+		})
+	}
+
+	return http.StatusOK, map[string]string{"documentid": strconv.Itoa(documentID)}
+}
+
+func listAdminDocuments(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	documents := []*athenahealth.AdminDocument{}
+
+	for _, document := range r.practice.adminDocuments[patient.PatientID] {
+		if matches(r.form.Get("departmentid"), document.DepartmentID) {
+			documents = append(documents, document)
+		}
+	}
+
+	page, p := paginate(r, documents)
+
+	return http.StatusOK, &struct {
+		AdminDocuments []*athenahealth.AdminDocument `json:"admins"`
+		pagination
+	}{page, p}
+}
+
+func addClinicalDocument(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "departmentid", "documentsubclass"); missing {
+		return status, body
+	}
+
+	documentID := r.practice.newIntID()
+
+	if r.practice.clinicalDocuments[patient.PatientID] == nil {
+		r.practice.clinicalDocuments[patient.PatientID] = make(map[int]bool)
+	}
+
+	r.practice.clinicalDocuments[patient.PatientID][documentID] = true
+
+	return http.StatusOK, &athenahealth.AddClinicalDocumentResponse{
+		ClinicalDocumentID: documentID,
+		Success:            true,
+	}
+}
+
+func deleteClinicalDocument(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	documentID, _ := strconv.Atoi(r.param("clinicaldocumentid"))
+
+	if !r.practice.clinicalDocuments[patient.PatientID][documentID] {
+		return notFound("The clinical document is not found.")
+	}
+
+	delete(r.practice.clinicalDocuments[patient.PatientID], documentID)
+
+	return http.StatusOK, &athenahealth.DeleteClinicalDocumentResponse{
+		ClinicalDocumentID: documentID,
+		Success:            true,
+	}
+}
+
+func addPatientCaseDocument(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "departmentid", "documentsource", "documentsubclass"); missing {
+		return status, body
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"patientcaseid": r.practice.newIntID(),
+		"success":       true,
+	}
+}
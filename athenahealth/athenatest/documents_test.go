@@ -0,0 +1,73 @@
+package athenatest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_documents(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+	departmentID := 1
+
+	documentID, err := athenaClient.AddDocument(context.Background(), patientID, &athenahealth.AddDocumentOptions{
+		AttachmentContents: []byte("consent"),
+		DepartmentID:       &departmentID,
+		DocumentSubclass:   "ADMIN_CONSENT",
+	})
+	assert.NoError(err)
+
+	_, err = athenaClient.AddDocument(context.Background(), patientID, &athenahealth.AddDocumentOptions{
+		AttachmentContents: []byte("history"),
+		DocumentSubclass:   "MEDICALRECORD_HISTORICAL",
+	})
+	assert.NoError(err)
+
+	admin, err := athenaClient.ListAdminDocuments(context.Background(), patientID, nil)
+	assert.NoError(err)
+	if assert.Len(admin.AdminDocuments, 1) {
+		assert.Equal(documentID, strconv.Itoa(admin.AdminDocuments[0].AdminID))
+		assert.Equal("1", admin.AdminDocuments[0].DepartmentID)
+	}
+
+	_, err = athenaClient.AddDocument(context.Background(), "0", &athenahealth.AddDocumentOptions{DocumentSubclass: "ADMIN_CONSENT"})
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
+
+func TestServer_clinical_documents(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	added, err := athenaClient.AddClinicalDocument(context.Background(), patientID, &athenahealth.AddClinicalDocumentOptions{
+		AttachmentContents: []byte("note"),
+		DepartmentID:       1,
+		DocumentSubclass:   "CLINICALDOCUMENT_CONSULTNOTE",
+	})
+	assert.NoError(err)
+	assert.True(added.Success)
+
+	deleted, err := athenaClient.DeleteClinicalDocument(context.Background(), patientID, strconv.Itoa(added.ClinicalDocumentID))
+	assert.NoError(err)
+	assert.Equal(added.ClinicalDocumentID, deleted.ClinicalDocumentID)
+
+	_, err = athenaClient.DeleteClinicalDocument(context.Background(), patientID, strconv.Itoa(added.ClinicalDocumentID))
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	patientCaseID, err := athenaClient.AddPatientCaseDocument(context.Background(), patientID, &athenahealth.AddPatientCaseDocumentOptions{
+		DepartmentID:     1,
+		DocumentSource:   "PORTAL",
+		DocumentSubclass: "PATIENTCASE_CLINICALQUESTION",
+	})
+	assert.NoError(err)
+	assert.NotZero(patientCaseID)
+}
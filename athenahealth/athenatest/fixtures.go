@@ -0,0 +1,242 @@
+package athenatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// Fixtures is the state of a practice. Appointments with status "o" are open slots; other appointments are
+// booked. Insurances are keyed by patient ID. Patients and appointments without an ID are assigned one.
+//
+// The fake has no endpoints that change providers, prescriptions, lab results or problems, so seeding them
+// is what delivers them to their subscribed /changed feeds.
+type Fixtures struct {
+	Patients      []*athenahealth.Patient                     `json:"patients"`
+	CustomFields  []*athenahealth.CustomField                 `json:"customfields"`
+	Appointments  []*athenahealth.BookedAppointment           `json:"appointments"`
+	Insurances    map[string][]*athenahealth.InsurancePackage `json:"insurances"`
+	Providers     []*athenahealth.Provider                    `json:"providers"`
+	Prescriptions []*athenahealth.ChangedPrescription         `json:"prescriptions"`
+	LabResults    []*athenahealth.ChangedLabResult            `json:"labresults"`
+	Problems      []*athenahealth.ChangedProblem              `json:"problems"`
+}
+
+// LoadFixtures reads fixtures from a JSON file.
+func LoadFixtures(path string) (*Fixtures, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := &Fixtures{}
+
+	err = json.Unmarshal(b, fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling fixtures: %w", err)
+	}
+
+	return fixtures, nil
+}
+
+// Seed adds fixtures to the state of a practice. Seeding an entity with the ID of an existing one replaces
+// it. Seeded providers, prescriptions, lab results and problems are queued for their /changed feeds with
+// an Add or Update event, e.g. AddProvider or UpdateProvider.
+func (s *Server) Seed(practiceID string, fixtures *Fixtures) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p := s.practice(practiceID)
+
+	// IDs are assigned after every seeded ID has been seen so they do not collide.
+	for _, patient := range fixtures.Patients {
+		p.seenID(patient.PatientID)
+	}
+
+	for _, appointment := range fixtures.Appointments {
+		p.seenID(appointment.AppointmentID)
+	}
+
+	for _, insurances := range fixtures.Insurances {
+		for _, insurance := range insurances {
+			p.seenID(insurance.InsuranceID)
+		}
+	}
+
+	for _, provider := range fixtures.Providers {
+		p.seenID(strconv.Itoa(provider.ProviderID))
+	}
+
+	for _, prescription := range fixtures.Prescriptions {
+		p.seenID(strconv.Itoa(prescription.DocumentID))
+	}
+
+	for _, labResult := range fixtures.LabResults {
+		p.seenID(strconv.Itoa(labResult.LabResultID))
+	}
+
+	for _, problem := range fixtures.Problems {
+		p.seenID(strconv.Itoa(problem.ProblemID))
+	}
+
+	for _, patient := range fixtures.Patients {
+		if len(patient.PatientID) == 0 {
+			patient.PatientID = p.newID()
+		}
+
+		p.patients[patient.PatientID] = patient
+	}
+
+	for _, customField := range fixtures.CustomFields {
+		p.customFields[customField.CustomFieldID] = customField
+	}
+
+	for _, appointment := range fixtures.Appointments {
+		if len(appointment.AppointmentID) == 0 {
+			appointment.AppointmentID = p.newID()
+		}
+
+		p.appointments[appointment.AppointmentID] = appointment
+	}
+
+	for patientID, insurances := range fixtures.Insurances {
+		for _, insurance := range insurances {
+			if len(insurance.InsuranceID) == 0 {
+				insurance.InsuranceID = p.newID()
+			}
+
+			p.insurances[patientID] = append(p.insurances[patientID], insurance)
+		}
+	}
+
+	for _, provider := range fixtures.Providers {
+		seedChanged(p, p.providers, provider, &provider.ProviderID, feedProviders, "Provider")
+	}
+
+	for _, prescription := range fixtures.Prescriptions {
+		seedChanged(p, p.prescriptions, prescription, &prescription.DocumentID, feedPrescriptions, "Prescription")
+	}
+
+	for _, labResult := range fixtures.LabResults {
+		seedChanged(p, p.labResults, labResult, &labResult.LabResultID, feedLabResults, "LabResult")
+	}
+
+	for _, problem := range fixtures.Problems {
+		seedChanged(p, p.problems, problem, &problem.ProblemID, feedProblems, "Problem")
+	}
+}
+
+// seedChanged adds a seeded entity to m, assigning it an ID if it has none, and queues it for its feed with
+// an Add or Update event depending on whether it replaced an existing one.
+func seedChanged[T any](p *practice, m map[string]*T, entity *T, id *int, feed, entityName string) {
+	if *id == 0 {
+		*id = p.newIntID()
+	}
+
+	key := strconv.Itoa(*id)
+
+	event := "Add" + entityName
+	if _, ok := m[key]; ok {
+		event = "Update" + entityName
+	}
+
+	m[key] = entity
+
+	p.queueChange(feed, event, key)
+}
+
+// practice is the state of a practice.
+type practice struct {
+	lastID int
+
+	patients       map[string]*athenahealth.Patient
+	patientPhotos  map[string]string
+	customFields   map[string]*athenahealth.CustomField
+	appointments   map[string]*athenahealth.BookedAppointment
+	adminDocuments map[string][]*athenahealth.AdminDocument
+	// clinicalDocuments are the IDs of clinical documents that have not been deleted, by patient ID.
+	clinicalDocuments map[string]map[int]bool
+	insurances        map[string][]*athenahealth.InsurancePackage
+	insuranceImages   map[string]string
+	providers         map[string]*athenahealth.Provider
+	prescriptions     map[string]*athenahealth.ChangedPrescription
+	labResults        map[string]*athenahealth.ChangedLabResult
+	problems          map[string]*athenahealth.ChangedProblem
+
+	// subscriptions are the subscribed events of each feed.
+	subscriptions map[string]map[string]bool
+	// changed are the IDs of changed entities that have not been processed for each feed, in the order
+	// they changed.
+	changed map[string][]string
+}
+
+func newPractice() *practice {
+	return &practice{
+		patients:          make(map[string]*athenahealth.Patient),
+		patientPhotos:     make(map[string]string),
+		customFields:      make(map[string]*athenahealth.CustomField),
+		appointments:      make(map[string]*athenahealth.BookedAppointment),
+		adminDocuments:    make(map[string][]*athenahealth.AdminDocument),
+		clinicalDocuments: make(map[string]map[int]bool),
+		insurances:        make(map[string][]*athenahealth.InsurancePackage),
+		insuranceImages:   make(map[string]string),
+		providers:         make(map[string]*athenahealth.Provider),
+		prescriptions:     make(map[string]*athenahealth.ChangedPrescription),
+		labResults:        make(map[string]*athenahealth.ChangedLabResult),
+		problems:          make(map[string]*athenahealth.ChangedProblem),
+		subscriptions:     make(map[string]map[string]bool),
+		changed:           make(map[string][]string),
+	}
+}
+
+// newID returns an ID that is not used by any entity in the practice.
+func (p *practice) newID() string {
+	p.lastID++
+
+	return strconv.Itoa(p.lastID)
+}
+
+func (p *practice) newIntID() int {
+	p.lastID++
+
+	return p.lastID
+}
+
+// seenID makes sure that IDs returned by newID do not collide with a seeded ID.
+func (p *practice) seenID(id string) {
+	n, err := strconv.Atoi(id)
+	if err == nil && n > p.lastID {
+		p.lastID = n
+	}
+}
+
+// sortedByID returns the values of m sorted by their numeric ID.
+func sortedByID[T any](m map[string]T) []T {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return lessID(ids[i], ids[j])
+	})
+
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, m[id])
+	}
+
+	return values
+}
+
+func lessID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+
+	return a < b
+}
@@ -0,0 +1,90 @@
+package athenatest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+
+	err := os.WriteFile(path, []byte(`{
+		"patients": [{"patientid": "100", "firstname": "Jane", "lastname": "Doe", "dob": "01/01/1980", "departmentid": "1"}],
+		"appointments": [{"appointmentid": "200", "appointmentstatus": "o", "date": "06/01/2030", "starttime": "09:00", "departmentid": "1", "providerid": "2"}],
+		"insurances": {"100": [{"insuranceid": "300", "insurancepackageid": 1, "sequencenumber": 1}]}
+	}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures, err := LoadFixtures(path)
+	assert.NoError(err)
+
+	s, athenaClient := testClient(t)
+	s.Seed(testPracticeID, fixtures)
+
+	patient, err := athenaClient.GetPatient(context.Background(), "100", nil)
+	assert.NoError(err)
+	assert.Equal("Jane", patient.FirstName)
+
+	insurances, err := athenaClient.ListPatientInsurancePackages(context.Background(), &athenahealth.ListPatientInsurancePackagesOptions{PatientID: "100"})
+	assert.NoError(err)
+	assert.Len(insurances.InsurancePackages, 1)
+
+	// New IDs do not collide with seeded IDs.
+	patientID, err := athenaClient.CreatePatient(context.Background(), &athenahealth.CreatePatientOptions{
+		DepartmentID: "1",
+		DOB:          time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		FirstName:    "John",
+		LastName:     "Doe",
+	})
+	assert.NoError(err)
+	assert.Equal("301", patientID)
+}
+
+func TestLoadFixtures_missing(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := LoadFixtures(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(err)
+}
+
+func TestServer_Seed_practices(t *testing.T) {
+	assert := assert.New(t)
+
+	s, athenaClient := testClient(t)
+
+	s.Seed(testPracticeID, &Fixtures{
+		Patients: []*athenahealth.Patient{{PatientID: "1", FirstName: "Jane"}},
+	})
+	s.Seed("1959222", &Fixtures{
+		Patients: []*athenahealth.Patient{{PatientID: "1", FirstName: "John"}, {PatientID: "2", FirstName: "Joan"}},
+	})
+
+	patients, err := athenahealth.FanOutMerge(context.Background(), []string{testPracticeID, "1959222"}, nil, func(ctx context.Context, practiceID string) ([]*athenahealth.Patient, error) {
+		res, err := athenaClient.ListPatients(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return res.Patients, nil
+	})
+	assert.NoError(err)
+	if assert.Len(patients, 3) {
+		assert.Equal("Jane", patients[0].FirstName)
+		assert.Equal("John", patients[1].FirstName)
+		assert.Equal("Joan", patients[2].FirstName)
+	}
+
+	patient, err := athenaClient.ForPractice("1959222").GetPatient(context.Background(), "2", nil)
+	assert.NoError(err)
+	assert.Equal("Joan", patient.FirstName)
+}
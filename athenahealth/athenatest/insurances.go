@@ -0,0 +1,199 @@
+package athenatest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// insurance returns the insurance named by the insuranceid path parameter, or an error response if it or the
+// patient does not exist.
+func (r *request) insurance() (*athenahealth.InsurancePackage, int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return nil, status, body
+	}
+
+	for _, insurance := range r.practice.insurances[patient.PatientID] {
+		if insurance.InsuranceID == r.param("insuranceid") {
+			return insurance, 0, nil
+		}
+	}
+
+	status, body = notFound("The insurance is not found.")
+
+	return nil, status, body
+}
+
+// activeInsurance returns the patient's active insurance with a sequence number, if there is one.
+func (p *practice) activeInsurance(patientID string, sequenceNumber int) *athenahealth.InsurancePackage {
+	for _, insurance := range p.insurances[patientID] {
+		if len(insurance.Cancelled) == 0 && insurance.SequenceNumber == sequenceNumber {
+			return insurance
+		}
+	}
+
+	return nil
+}
+
+func createPatientInsurance(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "insurancepackageid", "sequencenumber"); missing {
+		return status, body
+	}
+
+	insurancePackageID, err := strconv.Atoi(r.form.Get("insurancepackageid"))
+	if err != nil {
+		return badRequest("Invalid insurance package ID.")
+	}
+
+	sequenceNumber, err := strconv.Atoi(r.form.Get("sequencenumber"))
+	if err != nil {
+		return badRequest("Invalid sequence number.")
+	}
+
+	if r.practice.activeInsurance(patient.PatientID, sequenceNumber) != nil {
+		return badRequest("The patient already has an active insurance with this sequence number.")
+	}
+
+	insurance := &athenahealth.InsurancePackage{
+		EligibilityStatus:              "Unverified",
+		InsuranceID:                    r.practice.newID(),
+		InsuranceIDNumber:              r.form.Get("insuranceidnumber"),
+		InsurancePackageID:             insurancePackageID,
+		InsurancePolicyHolderdDOB:      r.form.Get("insurancepolicyholderdob"),
+		InsurancePolicyHolderFirstName: r.form.Get("insurancepolicyholderfirstname"),
+		InsurancePolicyHolderLastName:  r.form.Get("insurancepolicyholderlastname"),
+		InsurancePolicyHolderSex:       r.form.Get("insurancepolicyholdersex"),
+		SequenceNumber:                 sequenceNumber,
+	}
+
+	r.practice.insurances[patient.PatientID] = append(r.practice.insurances[patient.PatientID], insurance)
+
+	return http.StatusOK, []*athenahealth.InsurancePackage{insurance}
+}
+
+func listPatientInsurances(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	showCancelled, _ := strconv.ParseBool(r.form.Get("showcancelled"))
+
+	insurances := []*athenahealth.InsurancePackage{}
+
+	for _, insurance := range r.practice.insurances[patient.PatientID] {
+		if showCancelled || len(insurance.Cancelled) == 0 {
+			insurances = append(insurances, insurance)
+		}
+	}
+
+	page, p := paginate(r, insurances)
+
+	return http.StatusOK, &struct {
+		Insurances []*athenahealth.InsurancePackage `json:"insurances"`
+		pagination
+	}{page, p}
+}
+
+func updatePatientInsurance(r *request) (int, interface{}) {
+	insurance, status, body := r.insurance()
+	if insurance == nil {
+		return status, body
+	}
+
+	if newSequenceNumber := r.form.Get("newsequencenumber"); len(newSequenceNumber) > 0 {
+		sequenceNumber, err := strconv.Atoi(newSequenceNumber)
+		if err != nil {
+			return badRequest("Invalid sequence number.")
+		}
+
+		active := r.practice.activeInsurance(r.param("patientid"), sequenceNumber)
+		if active != nil && active != insurance {
+			return badRequest("The patient already has an active insurance with this sequence number.")
+		}
+
+		insurance.SequenceNumber = sequenceNumber
+	}
+
+	err := applyForm(insurance, r.form)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+
+	return success()
+}
+
+func deletePatientInsurance(r *request) (int, interface{}) {
+	insurance, status, body := r.insurance()
+	if insurance == nil {
+		return status, body
+	}
+
+	if len(insurance.Cancelled) > 0 {
+		return badRequest("The insurance is already cancelled.")
+	}
+
+	insurance.Cancelled = time.Now().Format(dateFormat)
+
+	return success()
+}
+
+func reactivatePatientInsurance(r *request) (int, interface{}) {
+	insurance, status, body := r.insurance()
+	if insurance == nil {
+		return status, body
+	}
+
+	if len(insurance.Cancelled) == 0 {
+		return badRequest("The insurance is not cancelled.")
+	}
+
+	if r.practice.activeInsurance(r.param("patientid"), insurance.SequenceNumber) != nil {
+		return badRequest("The patient already has an active insurance with this sequence number.")
+	}
+
+	insurance.Cancelled = ""
+
+	if expirationDate := r.form.Get("expirationdate"); len(expirationDate) > 0 {
+		insurance.ExpirationDate = expirationDate
+	}
+
+	return success()
+}
+
+func uploadPatientInsuranceCardImage(r *request) (int, interface{}) {
+	insurance, status, body := r.insurance()
+	if insurance == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "image"); missing {
+		return status, body
+	}
+
+	r.practice.insuranceImages[insurance.InsuranceID] = r.form.Get("image")
+
+	return success()
+}
+
+func getPatientInsuranceCardImage(r *request) (int, interface{}) {
+	insurance, status, body := r.insurance()
+	if insurance == nil {
+		return status, body
+	}
+
+	image, ok := r.practice.insuranceImages[insurance.InsuranceID]
+	if !ok {
+		return notFound("The insurance does not have a card image.")
+	}
+
+	return http.StatusOK, map[string]string{"image": image}
+}
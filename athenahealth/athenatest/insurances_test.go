@@ -0,0 +1,104 @@
+package athenatest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_insurances(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	createOpts := &athenahealth.CreatePatientInsurancePackageOptions{
+		PatientID:                      patientID,
+		InsurancePackageID:             10,
+		InsuranceIDNumber:              "ABC123",
+		InsurancePolicyHolderFirstName: "Jane",
+		InsurancePolicyHolderLastName:  "Doe",
+		InsurancePolicyHolderDOB:       time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		InsurancePolicyHolderSex:       "F",
+		SequenceNumber:                 1,
+	}
+
+	insurance, err := athenaClient.CreatePatientInsurancePackage(context.Background(), createOpts)
+	assert.NoError(err)
+	assert.Equal("ABC123", insurance.InsuranceIDNumber)
+	assert.Equal(1, insurance.SequenceNumber)
+
+	// A patient can only have one active insurance for each sequence number.
+	_, err = athenaClient.CreatePatientInsurancePackage(context.Background(), createOpts)
+	assert.ErrorContains(err, "sequence number")
+
+	idNumber := "XYZ789"
+	err = athenaClient.UpdatePatientInsurancePackage(context.Background(), &athenahealth.UpdatePatientInsurancePackageOptions{
+		PatientID:         patientID,
+		InsuranceID:       insurance.InsuranceID,
+		InsuranceIDNumber: &idNumber,
+	})
+	assert.NoError(err)
+
+	patient, err := athenaClient.GetPatient(context.Background(), patientID, &athenahealth.GetPatientOptions{ShowInsurance: true})
+	assert.NoError(err)
+	if assert.Len(patient.Insurances, 1) {
+		assert.Equal(idNumber, patient.Insurances[0].InsuranceIDNumber)
+	}
+
+	err = athenaClient.DeletePatientInsurancePackage(context.Background(), patientID, insurance.InsuranceID, "")
+	assert.NoError(err)
+
+	list, err := athenaClient.ListPatientInsurancePackages(context.Background(), &athenahealth.ListPatientInsurancePackagesOptions{PatientID: patientID})
+	assert.NoError(err)
+	assert.Empty(list.InsurancePackages)
+
+	list, err = athenaClient.ListPatientInsurancePackages(context.Background(), &athenahealth.ListPatientInsurancePackagesOptions{
+		PatientID:     patientID,
+		ShowCancelled: true,
+		Pagination:    &athenahealth.PaginationOptions{},
+	})
+	assert.NoError(err)
+	if assert.Len(list.InsurancePackages, 1) {
+		assert.NotEmpty(list.InsurancePackages[0].Cancelled)
+	}
+
+	err = athenaClient.ReactivatePatientInsurancePackage(context.Background(), patientID, insurance.InsuranceID, nil)
+	assert.NoError(err)
+
+	list, err = athenaClient.ListPatientInsurancePackages(context.Background(), &athenahealth.ListPatientInsurancePackagesOptions{PatientID: patientID})
+	assert.NoError(err)
+	assert.Len(list.InsurancePackages, 1)
+}
+
+func TestServer_insurance_card_image(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	insurance, err := athenaClient.CreatePatientInsurancePackage(context.Background(), &athenahealth.CreatePatientInsurancePackageOptions{
+		PatientID:          patientID,
+		InsurancePackageID: 10,
+		SequenceNumber:     1,
+	})
+	assert.NoError(err)
+
+	_, err = athenaClient.GetPatientInsuranceCardImage(context.Background(), patientID, insurance.InsuranceID)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	res, err := athenaClient.UploadPatientInsuranceCardImage(context.Background(), patientID, insurance.InsuranceID, &athenahealth.UploadPatientInsuranceCardImageOptions{
+		Image: []byte("card"),
+	})
+	assert.NoError(err)
+	assert.True(res.Success)
+
+	image, err := athenaClient.GetPatientInsuranceCardImage(context.Background(), patientID, insurance.InsuranceID)
+	assert.NoError(err)
+	assert.Equal("Y2FyZA==", image.Image)
+}
@@ -0,0 +1,276 @@
+package athenatest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// formAliases are form fields whose name differs from the JSON field they update.
+var formAliases = map[string]string{
+	"hasmobileyn": "hasmobile",
+}
+
+// applyForm sets the JSON fields of v that are named by form fields, converting values to the type of the
+// field. Form fields that do not name a JSON field of v are ignored.
+func applyForm(v interface{}, form url.Values) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{})
+
+	err = json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+
+	for key := range form {
+		value := form.Get(key)
+
+		field := key
+		if alias, ok := formAliases[key]; ok {
+			field = alias
+		}
+
+		current, ok := fields[field]
+		if !ok {
+			continue
+		}
+
+		switch current.(type) {
+		case bool:
+			fields[field] = value == "true" || value == "Y"
+
+		case float64:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+
+			fields[field] = n
+
+		case []interface{}, nil:
+			fields[field] = strings.Split(value, "\t")
+
+		default:
+			fields[field] = value
+		}
+	}
+
+	b, err = json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// patient returns the patient named by the patientid path parameter, or an error response if it does not exist.
+func (r *request) patient() (*athenahealth.Patient, int, interface{}) {
+	patient, ok := r.practice.patients[r.param("patientid")]
+	if !ok {
+		status, body := notFound("The patient is not found.")
+
+		return nil, status, body
+	}
+
+	return patient, 0, nil
+}
+
+func createPatient(r *request) (int, interface{}) {
+	if status, body, missing := missingFields(r.form, "departmentid", "dob", "firstname", "lastname"); missing {
+		return status, body
+	}
+
+	_, err := parseDate(r.form.Get("dob"))
+	if err != nil {
+		return badRequest("Invalid date of birth.")
+	}
+
+	bypassPatientMatching, _ := strconv.ParseBool(r.form.Get("bypasspatientmatching"))
+
+	if !bypassPatientMatching {
+		for _, patient := range sortedByID(r.practice.patients) {
+			if strings.EqualFold(patient.FirstName, r.form.Get("firstname")) &&
+				strings.EqualFold(patient.LastName, r.form.Get("lastname")) &&
+				patient.DOB == r.form.Get("dob") {
+				return http.StatusOK, []map[string]string{{"patientid": patient.PatientID}}
+			}
+		}
+	}
+
+	patient := &athenahealth.Patient{
+		PatientID:        r.practice.newID(),
+		Status:           athenahealth.UpdatePatientStatusActiveOption,
+		RegistrationDate: time.Now().Format(dateFormat),
+	}
+
+	err = applyForm(patient, r.form)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+
+	if len(patient.Status) == 0 {
+		patient.Status = athenahealth.UpdatePatientStatusActiveOption
+	}
+
+	patient.PrimaryDepartmentID = patient.DepartmentID
+	patient.LastUpdated = time.Now().Format(dateFormat)
+
+	r.practice.patients[patient.PatientID] = patient
+	r.practice.patientChanged("AddPatient", patient.PatientID)
+
+	return http.StatusOK, []map[string]string{{"patientid": patient.PatientID}}
+}
+
+func getPatient(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	// Copy the patient so custom fields and insurances are only shown when requested.
+	out := *patient
+	out.CustomFields = nil
+
+	if r.form.Get("showcustomfields") == "true" {
+		out.CustomFields = patient.CustomFields
+	}
+
+	if r.form.Get("showinsurance") == "true" {
+		out.Insurances = []athenahealth.Insurance{}
+
+		for _, insurance := range r.practice.insurances[patient.PatientID] {
+			if len(insurance.Cancelled) > 0 {
+				continue
+			}
+
+			out.Insurances = append(out.Insurances, athenahealth.Insurance{
+				InsuranceID:                    insurance.InsuranceID,
+				InsuranceIDNumber:              insurance.InsuranceIDNumber,
+				InsurancePackageID:             insurance.InsurancePackageID,
+				InsurancePlanName:              insurance.InsurancePlanName,
+				InsurancePolicyHolderFirstName: insurance.InsurancePolicyHolderFirstName,
+				InsurancePolicyHolderLastName:  insurance.InsurancePolicyHolderLastName,
+				InsurancePolicyHolderSex:       insurance.InsurancePolicyHolderSex,
+				InsuranceType:                  insurance.InsuranceType,
+				SequenceNumber:                 insurance.SequenceNumber,
+			})
+		}
+	}
+
+	return http.StatusOK, []*athenahealth.Patient{&out}
+}
+
+func listPatients(r *request) (int, interface{}) {
+	patients := []*athenahealth.Patient{}
+
+	for _, patient := range sortedByID(r.practice.patients) {
+		if (len(r.form.Get("firstname")) == 0 || strings.EqualFold(r.form.Get("firstname"), patient.FirstName)) &&
+			(len(r.form.Get("lastname")) == 0 || strings.EqualFold(r.form.Get("lastname"), patient.LastName)) &&
+			matches(r.form.Get("departmentid"), patient.DepartmentID) &&
+			matches(r.form.Get("status"), patient.Status) {
+			patients = append(patients, patient)
+		}
+	}
+
+	page, p := paginate(r, patients)
+
+	return http.StatusOK, &struct {
+		Patients []*athenahealth.Patient `json:"patients"`
+		pagination
+	}{page, p}
+}
+
+func updatePatient(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if dob := r.form.Get("dob"); len(dob) > 0 {
+		_, err := parseDate(dob)
+		if err != nil {
+			return badRequest("Invalid date of birth.")
+		}
+	}
+
+	err := applyForm(patient, r.form)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+
+	patient.LastUpdated = time.Now().Format(dateFormat)
+
+	r.practice.patientChanged("UpdatePatient", patient.PatientID)
+
+	return http.StatusOK, []map[string]string{{"patientid": patient.PatientID}}
+}
+
+func getPatientPhoto(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	image, ok := r.practice.patientPhotos[patient.PatientID]
+	if !ok {
+		return notFound("The patient does not have a photo.")
+	}
+
+	return http.StatusOK, map[string]string{"image": image}
+}
+
+func updatePatientPhoto(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "image"); missing {
+		return status, body
+	}
+
+	r.practice.patientPhotos[patient.PatientID] = r.form.Get("image")
+	patient.PatientPhoto = true
+
+	return success()
+}
+
+func updatePrivacyInformationVerified(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "departmentid", "signaturedatetime", "signaturename"); missing {
+		return status, body
+	}
+
+	patient.PrivacyInformationVerified = true
+
+	return http.StatusOK, []map[string]bool{{"success": true}}
+}
+
+func updateMedicationHistoryConsentVerified(r *request) (int, interface{}) {
+	patient, status, body := r.patient()
+	if patient == nil {
+		return status, body
+	}
+
+	if status, body, missing := missingFields(r.form, "departmentid", "signaturedatetime", "signaturename"); missing {
+		return status, body
+	}
+
+	patient.MedicationHistoryConsentVerified = true
+
+	// athena returns success as a string from this endpoint.
+	return http.StatusOK, []map[string]string{{"success": "true"}}
+}
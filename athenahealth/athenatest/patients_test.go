@@ -0,0 +1,157 @@
+package athenatest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCreatePatient(t *testing.T, athenaClient *athenahealth.HTTPClient, firstName string) string {
+	patientID, err := athenaClient.CreatePatient(context.Background(), &athenahealth.CreatePatientOptions{
+		DepartmentID: "1",
+		DOB:          time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		FirstName:    firstName,
+		LastName:     "Doe",
+		MobilePhone:  "5555555555",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return patientID
+}
+
+func TestServer_patients(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	patient, err := athenaClient.GetPatient(context.Background(), patientID, nil)
+	assert.NoError(err)
+	assert.Equal("Jane", patient.FirstName)
+	assert.Equal("01/01/1980", patient.DOB)
+	assert.Equal("5555555555", patient.MobilePhone)
+	assert.Equal("a", patient.Status)
+	assert.Equal("1", patient.PrimaryDepartmentID)
+
+	// Patients with the same name and date of birth are matched unless matching is bypassed.
+	assert.Equal(patientID, testCreatePatient(t, athenaClient, "jane"))
+
+	bypassedID, err := athenaClient.CreatePatient(context.Background(), &athenahealth.CreatePatientOptions{
+		DepartmentID:          "1",
+		DOB:                   time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		FirstName:             "Jane",
+		LastName:              "Doe",
+		BypassPatientMatching: true,
+	})
+	assert.NoError(err)
+	assert.NotEqual(patientID, bypassedID)
+
+	email := "jane@example.com"
+	hasMobile := true
+	status := athenahealth.UpdatePatientStatusInactiveOption
+
+	res, err := athenaClient.UpdatePatient(context.Background(), patientID, &athenahealth.UpdatePatientOptions{
+		Email:     &email,
+		HasMobile: &hasMobile,
+		Race:      []string{"2106-3", "2054-5"},
+		Status:    &status,
+	})
+	assert.NoError(err)
+	assert.Equal(patientID, res.PatientID)
+
+	patient, err = athenaClient.GetPatient(context.Background(), patientID, nil)
+	assert.NoError(err)
+	assert.Equal(email, patient.Email)
+	assert.True(patient.HasMobile)
+	assert.Equal([]string{"2106-3", "2054-5"}, patient.Race)
+
+	list, err := athenaClient.ListPatients(context.Background(), &athenahealth.ListPatientsOptions{
+		Status: athenahealth.UpdatePatientStatusActiveOption,
+	})
+	assert.NoError(err)
+	assert.Len(list.Patients, 1)
+	assert.Equal(bypassedID, list.Patients[0].PatientID)
+}
+
+func TestServer_list_all_patients(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	for _, firstName := range []string{"Jane", "John", "Joan", "Jack", "Jill"} {
+		testCreatePatient(t, athenaClient, firstName)
+	}
+
+	patients, err := athenaClient.ListAllPatients(context.Background(), &athenahealth.ListPatientsOptions{
+		LastName:   "Doe",
+		Pagination: &athenahealth.PaginationOptions{Limit: 2},
+	})
+	assert.NoError(err)
+	if assert.Len(patients, 5) {
+		assert.Equal("Jane", patients[0].FirstName)
+		assert.Equal("Jill", patients[4].FirstName)
+	}
+}
+
+func TestServer_create_patient_missing_fields(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	_, err := athenaClient.CreatePatient(context.Background(), &athenahealth.CreatePatientOptions{
+		DOB:       time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		FirstName: "Jane",
+	})
+	assert.ErrorContains(err, "departmentid, lastname")
+}
+
+func TestServer_patient_photo(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	_, err := athenaClient.GetPatientPhoto(context.Background(), patientID, nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	err = athenaClient.UpdatePatientPhoto(context.Background(), patientID, []byte("photo"))
+	assert.NoError(err)
+
+	photo, err := athenaClient.GetPatientPhoto(context.Background(), patientID, nil)
+	assert.NoError(err)
+	assert.Equal("cGhvdG8=", photo)
+}
+
+func TestServer_patient_verification(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+
+	err := athenaClient.UpdatePatientInformationVerificationDetails(context.Background(), patientID, &athenahealth.UpdatePatientInformationVerificationDetailsOptions{
+		DepartmentID:      1,
+		SignatureDatetime: time.Now(),
+		SignatureName:     "Jane Doe",
+	})
+	assert.NoError(err)
+
+	err = athenaClient.UpdatePatientMedicationHistoryConsent(context.Background(), patientID, &athenahealth.UpdatePatientMedicationHistoryConsentOptions{
+		DepartmentID:      1,
+		SignatureDatetime: time.Now(),
+		SignatureName:     "Jane Doe",
+	})
+	assert.NoError(err)
+
+	patient, err := athenaClient.GetPatient(context.Background(), patientID, nil)
+	assert.NoError(err)
+	assert.True(patient.PrivacyInformationVerified)
+	assert.True(patient.MedicationHistoryConsentVerified)
+}
@@ -0,0 +1,71 @@
+package athenatest
+
+import "net/http"
+
+// registerRoutes registers the endpoints of the fake. Routes with literal segments are registered before
+// routes with parameters in the same position, e.g. /patients/changed before /patients/:patientid.
+func (s *Server) registerRoutes() {
+	// Subscriptions and /changed feeds
+	s.handle(http.MethodGet, "/patients/changed", listChangedPatients)
+	s.handle(http.MethodGet, "/appointments/changed", listChangedAppointments)
+	s.handle(http.MethodGet, "/providers/changed", listChangedProviders)
+	s.handle(http.MethodGet, "/prescriptions/changed", listChangedPrescriptions)
+	s.handle(http.MethodGet, "/labresults/changed", listChangedLabResults)
+	s.handle(http.MethodGet, "/chart/healthhistory/problems/changed", listChangedProblems)
+
+	// Only the feeds above can be subscribed to; subscribing to any other feed is an invalid URL as in athena.
+	for feed := range feedEvents {
+		s.handle(http.MethodGet, "/"+feed+"/changed/subscription", getSubscription(feed))
+		s.handle(http.MethodPost, "/"+feed+"/changed/subscription", subscribe(feed))
+		s.handle(http.MethodDelete, "/"+feed+"/changed/subscription", unsubscribe(feed))
+		s.handle(http.MethodGet, "/"+feed+"/changed/subscription/events", listSubscriptionEvents(feed))
+	}
+
+	// Custom fields
+	s.handle(http.MethodGet, "/customfields", listCustomFields)
+	s.handle(http.MethodGet, "/patients/customfields/:customfieldid/:customfieldvalue", listPatientsMatchingCustomField)
+	s.handle(http.MethodGet, "/patients/:patientid/customfields", getPatientCustomFields)
+	s.handle(http.MethodPut, "/patients/:patientid/customfields", updatePatientCustomFields)
+
+	// Patients
+	s.handle(http.MethodPost, "/patients", createPatient)
+	s.handle(http.MethodGet, "/patients", listPatients)
+	s.handle(http.MethodGet, "/patients/:patientid", getPatient)
+	s.handle(http.MethodPut, "/patients/:patientid", updatePatient)
+	s.handle(http.MethodGet, "/patients/:patientid/photo", getPatientPhoto)
+	s.handle(http.MethodPost, "/patients/:patientid/photo", updatePatientPhoto)
+	s.handle(http.MethodPost, "/patients/:patientid/privacyinformationverified", updatePrivacyInformationVerified)
+	s.handle(http.MethodPost, "/patients/:patientid/medicationhistoryconsentverified", updateMedicationHistoryConsentVerified)
+
+	// Documents
+	s.handle(http.MethodPost, "/patients/:patientid/documents", addDocument)
+	s.handle(http.MethodGet, "/patients/:patientid/documents/admin", listAdminDocuments)
+	s.handle(http.MethodPost, "/patients/:patientid/documents/clinicaldocument", addClinicalDocument)
+	s.handle(http.MethodDelete, "/patients/:patientid/documents/clinicaldocument/:clinicaldocumentid", deleteClinicalDocument)
+	s.handle(http.MethodPost, "/patients/:patientid/documents/patientcase", addPatientCaseDocument)
+
+	// Insurances
+	s.handle(http.MethodPost, "/patients/:patientid/insurances", createPatientInsurance)
+	s.handle(http.MethodGet, "/patients/:patientid/insurances", listPatientInsurances)
+	s.handle(http.MethodPut, "/patients/:patientid/insurances/:insuranceid", updatePatientInsurance)
+	s.handle(http.MethodDelete, "/patients/:patientid/insurances/:insuranceid", deletePatientInsurance)
+	s.handle(http.MethodPost, "/patients/:patientid/insurances/:insuranceid/reactivate", reactivatePatientInsurance)
+	s.handle(http.MethodPost, "/patients/:patientid/insurances/:insuranceid/image", uploadPatientInsuranceCardImage)
+	s.handle(http.MethodGet, "/patients/:patientid/insurances/:insuranceid/image", getPatientInsuranceCardImage)
+
+	// Appointments
+	s.handle(http.MethodGet, "/appointments/open", listOpenAppointmentSlots)
+	s.handle(http.MethodPost, "/appointments/open", createAppointmentSlot)
+	s.handle(http.MethodGet, "/appointments/booked", listBookedAppointments)
+	s.handle(http.MethodPut, "/appointments/booked/:appointmentid", updateBookedAppointment)
+	s.handle(http.MethodGet, "/appointments/:appointmentid", getAppointment)
+	s.handle(http.MethodPut, "/appointments/:appointmentid", bookAppointment)
+	s.handle(http.MethodPut, "/appointments/:appointmentid/reschedule", rescheduleAppointment)
+	s.handle(http.MethodPut, "/appointments/:appointmentid/freeze", freezeAppointmentSlot)
+
+	// Appointment check-in
+	s.handle(http.MethodPost, "/appointments/:appointmentid/startcheckin", startCheckIn)
+	s.handle(http.MethodPost, "/appointments/:appointmentid/cancelcheckin", cancelCheckIn)
+	s.handle(http.MethodPost, "/appointments/:appointmentid/checkin", checkIn)
+	s.handle(http.MethodPost, "/appointments/:appointmentid/checkout", checkOut)
+}
@@ -0,0 +1,397 @@
+// Package athenatest provides an in-memory fake of the athenahealth API for tests.
+//
+// The fake keeps state for each practice, so patients created with CreatePatient can be found with
+// ListPatients, booked appointments leave the open slots, check-in moves appointments through their
+// statuses, and changes are delivered to subscribed /changed feeds. Errors are returned with the same
+// status codes and bodies as athena, so they surface as *athenahealth.APIError.
+package athenatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+const (
+	// defaultLimit is the number of results athena returns in a page when no limit is requested.
+	defaultLimit = 1500
+	// maxLimit is the largest page athena returns.
+	maxLimit = 5000
+
+	dateFormat     = "01/02/2006"
+	timeFormat     = "15:04"
+	datetimeFormat = "01/02/2006 15:04:05"
+)
+
+// Server is a fake athenahealth API. Requests are authenticated with tokens from its /oauth2/v1/token
+// endpoint.
+type Server struct {
+	*httptest.Server
+
+	routes []*route
+
+	practices map[string]*practice
+	tokens    map[string]bool
+	nextToken int
+
+	lock sync.Mutex
+}
+
+// NewServer starts a fake athenahealth API. Callers should call Close when finished to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		practices: make(map[string]*practice),
+		tokens:    make(map[string]bool),
+	}
+
+	s.registerRoutes()
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// BaseURL returns the base URL of the API, which takes the place of athenahealth.PreviewBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1/"
+}
+
+// AuthURL returns the URL of the token endpoint, which takes the place of tokenprovider.PreviewAuthURL.
+func (s *Server) AuthURL() string {
+	return s.URL + "/oauth2/v1/token"
+}
+
+// Environment returns an athenahealth.Environment pointing at the fake, for use with
+// athenahealth.HTTPClient.WithEnvironment instead of Client.
+func (s *Server) Environment() athenahealth.Environment {
+	return athenahealth.Environment{
+		Name:    "athenatest",
+		BaseURL: s.BaseURL(),
+		AuthURL: s.AuthURL(),
+		Preview: true,
+	}
+}
+
+// Client returns an http.Client that sends every request to the fake regardless of its host, so that it
+// can be passed to athenahealth.NewHTTPClient without changing the client's base URL or auth URL.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+
+	return &http.Client{
+		Transport: &rewriteTransport{
+			target:    target,
+			transport: s.Server.Client().Transport,
+		},
+	}
+}
+
+type rewriteTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+
+	return t.transport.RoundTrip(req)
+}
+
+// ExpireTokens invalidates every token issued so far, so the next request with one of them is rejected
+// with a 401 as athena does when a token expires early.
+func (s *Server) ExpireTokens() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tokens = make(map[string]bool)
+}
+
+// practice returns the state of a practice, creating it if it does not exist. s.lock must be held.
+func (s *Server) practice(practiceID string) *practice {
+	p, ok := s.practices[practiceID]
+	if !ok {
+		p = newPractice()
+		s.practices[practiceID] = p
+	}
+
+	return p
+}
+
+// request is a request to the API of a practice.
+type request struct {
+	*http.Request
+
+	params   map[string]string
+	form     url.Values
+	practice *practice
+}
+
+func (r *request) param(name string) string {
+	return r.params[name]
+}
+
+type handler func(r *request) (int, interface{})
+
+type route struct {
+	method   string
+	segments []string
+	handler  handler
+}
+
+// handle registers a handler for a method and a path relative to the practice, e.g. /patients/:patientid.
+// Routes are matched in the order they are registered.
+func (s *Server) handle(method, path string, h handler) {
+	s.routes = append(s.routes, &route{
+		method:   method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+		handler:  h,
+	})
+}
+
+func (rt *route) match(method string, segments []string) (map[string]string, bool) {
+	if rt.method != method || len(rt.segments) != len(segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, ":") {
+			params[segment[1:]] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth2/v1/token" {
+		s.serveToken(w, r)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 3 || segments[0] != "v1" {
+		writeError(w, http.StatusNotFound, "Invalid URL.")
+		return
+	}
+
+	form, err := parseForm(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !s.tokens[token] {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	practiceID, segments := segments[1], segments[2:]
+
+	for _, rt := range s.routes {
+		params, ok := rt.match(r.Method, segments)
+		if !ok {
+			continue
+		}
+
+		status, body := rt.handler(&request{
+			Request:  r,
+			params:   params,
+			form:     form,
+			practice: s.practice(practiceID),
+		})
+
+		writeJSON(w, status, body)
+
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "Invalid URL.")
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	form, err := parseForm(r)
+	if err != nil || form.Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	_, _, basicAuth := r.BasicAuth()
+	if !basicAuth && len(form.Get("client_assertion")) == 0 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.nextToken++
+	token := fmt.Sprintf("athenatest-token-%d", s.nextToken)
+	s.tokens[token] = true
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   "3600",
+	})
+}
+
+// parseForm parses a form encoded body for any method, including DELETE, and the query.
+func parseForm(r *http.Request) (url.Values, error) {
+	form := r.URL.Query()
+
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return form, nil
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range values {
+		form[k] = append(form[k], v...)
+	}
+
+	return form, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	//nolint
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorBody(message))
+}
+
+// apiError is the body athena returns with an error status.
+type apiError struct {
+	Error           string   `json:"error"`
+	DetailedMessage string   `json:"detailedmessage,omitempty"`
+	MissingFields   []string `json:"missingfields,omitempty"`
+}
+
+func errorBody(message string) *apiError {
+	return &apiError{
+		Error: message,
+	}
+}
+
+func notFound(message string) (int, interface{}) {
+	return http.StatusNotFound, errorBody(message)
+}
+
+func badRequest(message string) (int, interface{}) {
+	return http.StatusBadRequest, errorBody(message)
+}
+
+// missingFields returns an error listing the required fields missing from the form, or false if none are.
+func missingFields(form url.Values, fields ...string) (int, interface{}, bool) {
+	var missing []string
+
+	for _, field := range fields {
+		if len(form.Get(field)) == 0 {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) == 0 {
+		return 0, nil, false
+	}
+
+	return http.StatusBadRequest, &apiError{
+		Error:           "Additional fields are required.",
+		DetailedMessage: fmt.Sprintf("The following fields are required: %s.", strings.Join(missing, ", ")),
+		MissingFields:   missing,
+	}, true
+}
+
+// paginate returns the page of items requested by the limit and offset query parameters, with the next
+// and previous page URLs and total count athena returns alongside them.
+func paginate[T any](r *request, items []T) ([]T, pagination) {
+	query := r.URL.Query()
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+
+	limit = min(limit, maxLimit)
+
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	p := pagination{
+		TotalCount: len(items),
+	}
+
+	pageURL := func(offset int) string {
+		q := r.URL.Query()
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(offset))
+
+		return r.URL.Path + "?" + q.Encode()
+	}
+
+	if offset+limit < len(items) {
+		p.Next = pageURL(offset + limit)
+	}
+
+	if offset > 0 {
+		p.Previous = pageURL(max(offset-limit, 0))
+	}
+
+	if offset >= len(items) {
+		return []T{}, p
+	}
+
+	return items[offset:min(offset+limit, len(items))], p
+}
+
+type pagination struct {
+	Next       string `json:"next,omitempty"`
+	Previous   string `json:"previous,omitempty"`
+	TotalCount int    `json:"totalcount"`
+}
+
+func success() (int, interface{}) {
+	return http.StatusOK, map[string]interface{}{"success": true}
+}
+
+func parseDate(s string) (time.Time, error) {
+	return time.Parse(dateFormat, s)
+}
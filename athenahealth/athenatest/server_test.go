@@ -0,0 +1,135 @@
+package athenatest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPracticeID = "195900"
+
+func testClient(t *testing.T) (*Server, *athenahealth.HTTPClient) {
+	s := NewServer()
+	t.Cleanup(s.Close)
+
+	return s, athenahealth.NewHTTPClient(s.Client(), testPracticeID, "client-id", "secret")
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer()
+	defer s.Close()
+
+	res, err := s.Client().Get(s.BaseURL() + testPracticeID + "/patients")
+	assert.NoError(err)
+	res.Body.Close()
+
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestServer_ExpireTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	s, athenaClient := testClient(t)
+
+	_, err := athenaClient.ListPatients(context.Background(), nil)
+	assert.NoError(err)
+
+	s.ExpireTokens()
+
+	// The client replays the request with a new token after a 401.
+	_, err = athenaClient.ListPatients(context.Background(), nil)
+	assert.NoError(err)
+}
+
+func TestServer_Environment(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer()
+	defer s.Close()
+
+	athenaClient := athenahealth.NewHTTPClient(s.Server.Client(), testPracticeID, "client-id", "secret").
+		WithEnvironment(s.Environment())
+
+	_, err := athenaClient.ListPatients(context.Background(), nil)
+	assert.NoError(err)
+}
+
+func TestServer_not_found(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	var apiErr *athenahealth.APIError
+	if assert.True(errors.As(err, &apiErr)) {
+		assert.Equal("The patient is not found.", apiErr.AthenaError)
+	}
+
+	_, err = athenaClient.Get(context.Background(), "/unknown", nil, nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
+
+func TestServer_missing_fields(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	_, err := athenaClient.ListBookedAppointments(context.Background(), nil)
+
+	var apiErr *athenahealth.APIError
+	if assert.True(errors.As(err, &apiErr)) {
+		assert.Equal(http.StatusBadRequest, apiErr.HTTPResponse.StatusCode)
+		assert.Equal("Additional fields are required.", apiErr.AthenaError)
+		assert.Equal("The following fields are required: enddate, startdate.", apiErr.AthenaDetailedMessage)
+	}
+}
+
+func TestServer_pagination(t *testing.T) {
+	assert := assert.New(t)
+
+	s, athenaClient := testClient(t)
+
+	fixtures := &Fixtures{}
+	for i := 0; i < 5; i++ {
+		fixtures.Patients = append(fixtures.Patients, &athenahealth.Patient{FirstName: "Jane", LastName: "Doe"})
+	}
+
+	s.Seed(testPracticeID, fixtures)
+
+	res, err := athenaClient.ListPatients(context.Background(), &athenahealth.ListPatientsOptions{
+		Pagination: &athenahealth.PaginationOptions{Limit: 2},
+	})
+	assert.NoError(err)
+	assert.Len(res.Patients, 2)
+	assert.Equal("1", res.Patients[0].PatientID)
+	assert.Equal(&athenahealth.PaginationResult{NextOffset: 2, TotalCount: 5}, res.Pagination)
+
+	res, err = athenaClient.ListPatients(context.Background(), &athenahealth.ListPatientsOptions{
+		Pagination: &athenahealth.PaginationOptions{Limit: 2, Offset: 4},
+	})
+	assert.NoError(err)
+	assert.Len(res.Patients, 1)
+	assert.Equal("5", res.Patients[0].PatientID)
+	assert.Equal(&athenahealth.PaginationResult{PreviousOffset: 2, TotalCount: 5}, res.Pagination)
+}
+
+func TestServer_practices(t *testing.T) {
+	assert := assert.New(t)
+
+	s, athenaClient := testClient(t)
+
+	s.Seed("1", &Fixtures{
+		Patients: []*athenahealth.Patient{{PatientID: "1"}},
+	})
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
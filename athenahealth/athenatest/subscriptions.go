@@ -0,0 +1,359 @@
+package athenatest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+const (
+	feedAppointments  = "appointments"
+	feedLabResults    = "labresults"
+	feedPatients      = "patients"
+	feedPrescriptions = "prescriptions"
+	feedProblems      = "chart/healthhistory/problems"
+	feedProviders     = "providers"
+)
+
+// feedEvents are the events that can be subscribed to for each /changed feed.
+var feedEvents = map[string][]string{
+	feedAppointments: {
+		"CancelAppointment",
+		"CheckInAppointment",
+		"CheckOutAppointment",
+		"RescheduleAppointment",
+		"ScheduleAppointment",
+		"UpdateAppointment",
+	},
+	feedLabResults: {
+		"AddLabResult",
+		"UpdateLabResult",
+	},
+	feedPatients: {
+		"AddPatient",
+		"UpdatePatient",
+	},
+	feedPrescriptions: {
+		"AddPrescription",
+		"UpdatePrescription",
+	},
+	feedProblems: {
+		"AddProblem",
+		"UpdateProblem",
+	},
+	feedProviders: {
+		"AddProvider",
+		"UpdateProvider",
+	},
+}
+
+// queueChange queues an entity for a /changed feed if the feed is subscribed to the event.
+func (p *practice) queueChange(feed, event, id string) {
+	if !p.subscriptions[feed][event] {
+		return
+	}
+
+	for _, changedID := range p.changed[feed] {
+		if changedID == id {
+			return
+		}
+	}
+
+	p.changed[feed] = append(p.changed[feed], id)
+}
+
+func (p *practice) patientChanged(event, patientID string) {
+	p.queueChange(feedPatients, event, patientID)
+}
+
+func (p *practice) appointmentChanged(event, appointmentID string) {
+	p.queueChange(feedAppointments, event, appointmentID)
+}
+
+func getSubscription(feed string) handler {
+	return func(r *request) (int, interface{}) {
+		subscription := &athenahealth.Subscription{
+			Status:        "INACTIVE",
+			Subscriptions: subscriptionEvents(r.practice.subscriptions[feed]),
+		}
+
+		if len(subscription.Subscriptions) > 0 {
+			subscription.Status = "ACTIVE"
+		}
+
+		return http.StatusOK, subscription
+	}
+}
+
+func listSubscriptionEvents(feed string) handler {
+	return func(r *request) (int, interface{}) {
+		events := make(map[string]bool)
+		for _, event := range feedEvents[feed] {
+			events[event] = true
+		}
+
+		return http.StatusOK, map[string]interface{}{
+			"subscriptions": subscriptionEvents(events),
+		}
+	}
+}
+
+func subscriptionEvents(events map[string]bool) []*athenahealth.SubscriptionEvent {
+	subscriptionEvents := []*athenahealth.SubscriptionEvent{}
+
+	for event, subscribed := range events {
+		if subscribed {
+			subscriptionEvents = append(subscriptionEvents, &athenahealth.SubscriptionEvent{EventName: event})
+		}
+	}
+
+	sort.Slice(subscriptionEvents, func(i, j int) bool {
+		return subscriptionEvents[i].EventName < subscriptionEvents[j].EventName
+	})
+
+	return subscriptionEvents
+}
+
+// feedEventNames returns the events named by the eventname field, or every event of the feed if it is
+// empty, and false if the event does not belong to the feed.
+func feedEventNames(r *request, feed string) ([]string, bool) {
+	events := feedEvents[feed]
+
+	eventName := r.form.Get("eventname")
+	if len(eventName) == 0 {
+		return events, true
+	}
+
+	for _, event := range events {
+		if event == eventName {
+			return []string{event}, true
+		}
+	}
+
+	return nil, false
+}
+
+func subscribe(feed string) handler {
+	return func(r *request) (int, interface{}) {
+		events, ok := feedEventNames(r, feed)
+		if !ok {
+			return badRequest("Invalid event name.")
+		}
+
+		if r.practice.subscriptions[feed] == nil {
+			r.practice.subscriptions[feed] = make(map[string]bool)
+		}
+
+		for _, event := range events {
+			r.practice.subscriptions[feed][event] = true
+		}
+
+		return success()
+	}
+}
+
+func unsubscribe(feed string) handler {
+	return func(r *request) (int, interface{}) {
+		events, ok := feedEventNames(r, feed)
+		if !ok {
+			return badRequest("Invalid event name.")
+		}
+
+		for _, event := range events {
+			delete(r.practice.subscriptions[feed], event)
+		}
+
+		if len(r.practice.subscriptions[feed]) == 0 {
+			delete(r.practice.changed, feed)
+		}
+
+		return success()
+	}
+}
+
+// processChanged returns the queued IDs of a feed that match filter, removing them from the queue unless
+// leaveunprocessed is set.
+func processChanged(r *request, feed string, filter func(id string) bool) ([]string, bool) {
+	ids, ok := queuedChanges(r, feed, filter)
+	if ok {
+		processed(r, feed, ids)
+	}
+
+	return ids, ok
+}
+
+// queuedChanges returns the queued IDs of a feed that match filter, or false if the feed is not subscribed to.
+func queuedChanges(r *request, feed string, filter func(id string) bool) ([]string, bool) {
+	if len(r.practice.subscriptions[feed]) == 0 {
+		return nil, false
+	}
+
+	ids := []string{}
+
+	for _, id := range r.practice.changed[feed] {
+		if filter(id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, true
+}
+
+// processed removes IDs from the queue of a feed unless leaveunprocessed is set. Paginated feeds only
+// process the page that was returned.
+func processed(r *request, feed string, ids []string) {
+	leaveUnprocessed, _ := strconv.ParseBool(r.form.Get("leaveunprocessed"))
+	if leaveUnprocessed {
+		return
+	}
+
+	done := make(map[string]bool)
+	for _, id := range ids {
+		done[id] = true
+	}
+
+	var unprocessed []string
+
+	for _, id := range r.practice.changed[feed] {
+		if !done[id] {
+			unprocessed = append(unprocessed, id)
+		}
+	}
+
+	r.practice.changed[feed] = unprocessed
+}
+
+func listChangedPatients(r *request) (int, interface{}) {
+	ids, ok := processChanged(r, feedPatients, func(id string) bool {
+		patient := r.practice.patients[id]
+
+		return patient != nil &&
+			matches(r.form.Get("patientid"), patient.PatientID) &&
+			matches(r.form.Get("departmentid"), patient.DepartmentID)
+	})
+	if !ok {
+		return badRequest("You are not subscribed to this feed.")
+	}
+
+	patients := []*athenahealth.Patient{}
+	for _, id := range ids {
+		patients = append(patients, r.practice.patients[id])
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"patients": patients,
+	}
+}
+
+func listChangedAppointments(r *request) (int, interface{}) {
+	ids, ok := processChanged(r, feedAppointments, func(id string) bool {
+		appointment := r.practice.appointments[id]
+
+		return appointment != nil &&
+			matches(r.form.Get("patientid"), appointment.PatientID) &&
+			matches(r.form.Get("departmentid"), appointment.DepartmentID) &&
+			matches(r.form.Get("providerid"), appointment.ProviderID)
+	})
+	if !ok {
+		return badRequest("You are not subscribed to this feed.")
+	}
+
+	appointments := []*athenahealth.BookedAppointment{}
+	for _, id := range ids {
+		appointments = append(appointments, r.practice.appointments[id])
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"appointments": appointments,
+	}
+}
+
+func listChangedProviders(r *request) (int, interface{}) {
+	ids, ok := processChanged(r, feedProviders, func(id string) bool {
+		return r.practice.providers[id] != nil
+	})
+	if !ok {
+		return badRequest("You are not subscribed to this feed.")
+	}
+
+	providers := []*athenahealth.Provider{}
+	for _, id := range ids {
+		providers = append(providers, r.practice.providers[id])
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"providers": providers,
+	}
+}
+
+func listChangedPrescriptions(r *request) (int, interface{}) {
+	ids, ok := queuedChanges(r, feedPrescriptions, func(id string) bool {
+		return r.practice.prescriptions[id] != nil
+	})
+	if !ok {
+		return badRequest("You are not subscribed to this feed.")
+	}
+
+	page, p := paginate(r, ids)
+	processed(r, feedPrescriptions, page)
+
+	prescriptions := []*athenahealth.ChangedPrescription{}
+	for _, id := range page {
+		prescriptions = append(prescriptions, r.practice.prescriptions[id])
+	}
+
+	return http.StatusOK, &struct {
+		Prescriptions []*athenahealth.ChangedPrescription `json:"prescriptions"`
+		pagination
+	}{prescriptions, p}
+}
+
+func listChangedLabResults(r *request) (int, interface{}) {
+	ids, ok := queuedChanges(r, feedLabResults, func(id string) bool {
+		return r.practice.labResults[id] != nil
+	})
+	if !ok {
+		return badRequest("You are not subscribed to this feed.")
+	}
+
+	page, p := paginate(r, ids)
+	processed(r, feedLabResults, page)
+
+	labResults := []*athenahealth.ChangedLabResult{}
+	for _, id := range page {
+		labResults = append(labResults, r.practice.labResults[id])
+	}
+
+	return http.StatusOK, &struct {
+		LabResults []*athenahealth.ChangedLabResult `json:"labresults"`
+		pagination
+	}{labResults, p}
+}
+
+func listChangedProblems(r *request) (int, interface{}) {
+	ids, ok := processChanged(r, feedProblems, func(id string) bool {
+		problem := r.practice.problems[id]
+
+		return problem != nil &&
+			matches(r.form.Get("patientid"), strconv.Itoa(problem.PatientID))
+	})
+	if !ok {
+		return badRequest("You are not subscribed to this feed.")
+	}
+
+	problems := []*athenahealth.ChangedProblem{}
+	for _, id := range ids {
+		problems = append(problems, r.practice.problems[id])
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"problems": problems,
+	}
+}
+
+// matches reports whether value matches a filter, which matches every value if it is empty.
+func matches(filter, value string) bool {
+	return len(filter) == 0 || filter == value
+}
@@ -0,0 +1,269 @@
+package athenatest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_subscriptions(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	subscription, err := athenaClient.GetSubscription(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Equal("INACTIVE", subscription.Status)
+
+	events, err := athenaClient.ListSubscriptionEvents(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Equal([]*athenahealth.SubscriptionEvent{{EventName: "AddPatient"}, {EventName: "UpdatePatient"}}, events)
+
+	err = athenaClient.Subscribe(context.Background(), "patients", &athenahealth.SubscribeOptions{EventName: "AddPatient"})
+	assert.NoError(err)
+
+	subscription, err = athenaClient.GetSubscription(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Equal("ACTIVE", subscription.Status)
+	assert.Equal([]*athenahealth.SubscriptionEvent{{EventName: "AddPatient"}}, subscription.Subscriptions)
+
+	err = athenaClient.Subscribe(context.Background(), "patients", &athenahealth.SubscribeOptions{EventName: "ScheduleAppointment"})
+	assert.ErrorContains(err, "Invalid event name")
+
+	err = athenaClient.Unsubscribe(context.Background(), "patients", nil)
+	assert.NoError(err)
+
+	subscription, err = athenaClient.GetSubscription(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Equal("INACTIVE", subscription.Status)
+}
+
+func TestServer_changed_patients(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	_, err := athenaClient.ListChangedPatients(context.Background(), nil)
+	assert.ErrorContains(err, "not subscribed")
+
+	// Changes are only queued while subscribed.
+	testCreatePatient(t, athenaClient, "Jane")
+
+	err = athenaClient.Subscribe(context.Background(), "patients", nil)
+	assert.NoError(err)
+
+	patientID := testCreatePatient(t, athenaClient, "John")
+
+	changed, err := athenaClient.ListChangedPatients(context.Background(), &athenahealth.ListChangedPatientOptions{LeaveUnprocessed: true})
+	assert.NoError(err)
+	if assert.Len(changed, 1) {
+		assert.Equal(patientID, changed[0].PatientID)
+	}
+
+	changed, err = athenaClient.ListChangedPatients(context.Background(), nil)
+	assert.NoError(err)
+	assert.Len(changed, 1)
+
+	changed, err = athenaClient.ListChangedPatients(context.Background(), nil)
+	assert.NoError(err)
+	assert.Empty(changed)
+}
+
+func TestServer_changed_appointments(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	err := athenaClient.Subscribe(context.Background(), "appointments", &athenahealth.SubscribeOptions{EventName: "CheckInAppointment"})
+	assert.NoError(err)
+
+	patientID := testCreatePatient(t, athenaClient, "Jane")
+	appointmentID := testCreateAppointmentSlots(t, athenaClient, "09:00")["09:00"]
+
+	_, err = athenaClient.BookAppointment(context.Background(), patientID, appointmentID, nil)
+	assert.NoError(err)
+
+	changed, err := athenaClient.ListChangedAppointments(context.Background(), nil)
+	assert.NoError(err)
+	assert.Empty(changed)
+
+	assert.NoError(athenaClient.AppointmentStartCheckIn(context.Background(), appointmentID))
+	assert.NoError(athenaClient.AppointmentCheckIn(context.Background(), appointmentID))
+
+	changed, err = athenaClient.ListChangedAppointments(context.Background(), &athenahealth.ListChangedAppointmentsOptions{PatientID: patientID})
+	assert.NoError(err)
+	if assert.Len(changed, 1) {
+		assert.Equal(athenahealth.AppointmentStatusCheckedIn, changed[0].AppointmentStatus)
+	}
+}
+
+func TestServer_changed_feeds(t *testing.T) {
+	tests := []struct {
+		feed   string
+		change func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string
+		read   func(athenaClient *athenahealth.HTTPClient) ([]string, error)
+	}{
+		{
+			feed: "patients",
+			change: func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string {
+				return testCreatePatient(t, athenaClient, "Jane")
+			},
+			read: func(athenaClient *athenahealth.HTTPClient) ([]string, error) {
+				changed, err := athenaClient.ListChangedPatients(context.Background(), nil)
+
+				ids := []string{}
+				for _, patient := range changed {
+					ids = append(ids, patient.PatientID)
+				}
+
+				return ids, err
+			},
+		},
+		{
+			feed: "appointments",
+			change: func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string {
+				patientID := testCreatePatient(t, athenaClient, "Jane")
+				appointmentID := testCreateAppointmentSlots(t, athenaClient, "09:00")["09:00"]
+
+				_, err := athenaClient.BookAppointment(context.Background(), patientID, appointmentID, nil)
+				assert.NoError(t, err)
+
+				return appointmentID
+			},
+			read: func(athenaClient *athenahealth.HTTPClient) ([]string, error) {
+				changed, err := athenaClient.ListChangedAppointments(context.Background(), nil)
+
+				ids := []string{}
+				for _, appointment := range changed {
+					ids = append(ids, appointment.AppointmentID)
+				}
+
+				return ids, err
+			},
+		},
+		{
+			feed: "providers",
+			change: func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string {
+				s.Seed(testPracticeID, &Fixtures{Providers: []*athenahealth.Provider{{ProviderID: 71}}})
+
+				return "71"
+			},
+			read: func(athenaClient *athenahealth.HTTPClient) ([]string, error) {
+				changed, err := athenaClient.ListChangedProviders(context.Background(), nil)
+
+				ids := []string{}
+				for _, provider := range changed {
+					ids = append(ids, strconv.Itoa(provider.ProviderID))
+				}
+
+				return ids, err
+			},
+		},
+		{
+			feed: "prescriptions",
+			change: func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string {
+				s.Seed(testPracticeID, &Fixtures{Prescriptions: []*athenahealth.ChangedPrescription{{DocumentID: 72}}})
+
+				return "72"
+			},
+			read: func(athenaClient *athenahealth.HTTPClient) ([]string, error) {
+				changed, err := athenaClient.ListChangedPrescriptions(context.Background(), nil)
+				if err != nil {
+					return nil, err
+				}
+
+				ids := []string{}
+				for _, prescription := range changed.ChangedPrescriptions {
+					ids = append(ids, strconv.Itoa(prescription.DocumentID))
+				}
+
+				return ids, nil
+			},
+		},
+		{
+			feed: "labresults",
+			change: func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string {
+				s.Seed(testPracticeID, &Fixtures{LabResults: []*athenahealth.ChangedLabResult{{LabResultID: 73}}})
+
+				return "73"
+			},
+			read: func(athenaClient *athenahealth.HTTPClient) ([]string, error) {
+				changed, err := athenaClient.ListChangedLabResults(context.Background(), nil)
+				if err != nil {
+					return nil, err
+				}
+
+				ids := []string{}
+				for _, labResult := range changed.ChangedLabResults {
+					ids = append(ids, strconv.Itoa(labResult.LabResultID))
+				}
+
+				return ids, nil
+			},
+		},
+		{
+			feed: "chart/healthhistory/problems",
+			change: func(t *testing.T, s *Server, athenaClient *athenahealth.HTTPClient) string {
+				s.Seed(testPracticeID, &Fixtures{Problems: []*athenahealth.ChangedProblem{{ProblemID: 74}}})
+
+				return "74"
+			},
+			read: func(athenaClient *athenahealth.HTTPClient) ([]string, error) {
+				changed, err := athenaClient.ListChangedProblems(context.Background(), nil)
+
+				ids := []string{}
+				for _, problem := range changed {
+					ids = append(ids, strconv.Itoa(problem.ProblemID))
+				}
+
+				return ids, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.feed, func(t *testing.T) {
+			assert := assert.New(t)
+
+			s, athenaClient := testClient(t)
+
+			_, err := tt.read(athenaClient)
+			assert.ErrorContains(err, "not subscribed")
+
+			events, err := athenaClient.ListSubscriptionEvents(context.Background(), tt.feed)
+			assert.NoError(err)
+			assert.NotEmpty(events)
+
+			err = athenaClient.Subscribe(context.Background(), tt.feed, nil)
+			assert.NoError(err)
+
+			id := tt.change(t, s, athenaClient)
+
+			ids, err := tt.read(athenaClient)
+			assert.NoError(err)
+			assert.Contains(ids, id)
+
+			ids, err = tt.read(athenaClient)
+			assert.NoError(err)
+			assert.Empty(ids)
+		})
+	}
+}
+
+func TestServer_subscriptions_unknown_feed(t *testing.T) {
+	assert := assert.New(t)
+
+	_, athenaClient := testClient(t)
+
+	err := athenaClient.Subscribe(context.Background(), "claims", nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	_, err = athenaClient.GetSubscription(context.Background(), "claims")
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	_, err = athenaClient.ListSubscriptionEvents(context.Background(), "claims")
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
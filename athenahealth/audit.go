@@ -0,0 +1,248 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/auditor"
+)
+
+// WithAuditor records every request made by a Client method, e.g. with auditor.NewFile or auditor.NewChannel.
+// The principal and purpose of requests are taken from their context (see auditor.WithPrincipal). Requests
+// are not failed when the auditor returns an error; the error is logged instead. Auditing is synchronous: a
+// request does not return until the auditor does, for at most the request timeout.
+func (h *HTTPClient) WithAuditor(auditor Auditor) *HTTPClient {
+	h.auditor = auditor
+
+	return h
+}
+
+// auditing reports whether requests are recorded by an auditor.
+func (h *HTTPClient) auditing() bool {
+	_, ok := h.auditor.(*auditor.Default)
+
+	return !ok
+}
+
+// audit records a request, once it has succeeded or failed after all attempts, with the auditor.
+// reqPatientIDs are the patient IDs in the request body (see formPatientIDs).
+func (h *HTTPClient) audit(ctx context.Context, method, path, xRequestID string, reqPatientIDs []string, res *http.Response, err error) {
+	if !h.auditing() {
+		return
+	}
+
+	auditPath := h.auditPath(path)
+	practiceID, _ := h.practice(ctx)
+
+	event := &auditor.Event{
+		Time:       time.Now(),
+		Principal:  auditor.PrincipalFromContext(ctx),
+		Purpose:    auditor.PurposeFromContext(ctx),
+		Operation:  operation(ctx, method, auditPath),
+		Method:     method,
+		Path:       auditPath,
+		PracticeID: practiceID,
+		PatientIDs: appendPatientIDs(pathPatientIDs(path), reqPatientIDs...),
+		Outcome:    auditor.OutcomeSuccess,
+		XRequestID: xRequestID,
+	}
+
+	if err != nil {
+		event.Outcome = auditor.OutcomeFailure
+	}
+
+	if res != nil {
+		event.StatusCode = res.StatusCode
+
+		if res.Body != nil {
+			resBody, readErr := io.ReadAll(res.Body)
+			if readErr == nil {
+				event.PatientIDs = appendPatientIDs(event.PatientIDs, bodyPatientIDs(resBody)...)
+			}
+
+			res.Body = io.NopCloser(bytes.NewReader(resBody))
+		}
+	}
+
+	// The request may have failed because its context was cancelled, which must not stop it from being audited.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.requestTimeout)
+	defer cancel()
+
+	auditErr := h.auditor.Audit(ctx, event)
+	if auditErr != nil {
+		h.logger.Error().
+			Str("method", method).
+			Str("operation", event.Operation).
+			Str("xRequestId", xRequestID).
+			Err(auditErr).
+			Msg("athenahealth API request audit failed")
+	}
+}
+
+type operationContextKey struct{}
+
+// withOperation returns a copy of ctx whose requests are audited as operation, the name of the Client method
+// making them. Methods implemented with other methods (e.g. UpdatePatientPhoto) keep the outermost name.
+func withOperation(ctx context.Context, operation string) context.Context {
+	if _, ok := ctx.Value(operationContextKey{}).(string); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, operationContextKey{}, operation)
+}
+
+// operation returns the name of the Client method that made a request with ctx. Requests made directly with
+// Get, Post, etc. are named by their method and path.
+func operation(ctx context.Context, method, path string) string {
+	operation, ok := ctx.Value(operationContextKey{}).(string)
+	if !ok {
+		operation = method + " " + path
+	}
+
+	return operation
+}
+
+// auditPath returns path without its query and with IDs and other PHI replaced.
+func (h *HTTPClient) auditPath(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+
+	return h.redactor.URL(path)
+}
+
+// pathPatientIDs returns the patient IDs in the path (e.g. /patients/123/insurances or /chart/123/problems)
+// and the patientid query parameter.
+func pathPatientIDs(path string) []string {
+	var ids []string
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return ids
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if (segments[i] == "patients" || segments[i] == "chart") && isNumeric(segments[i+1]) {
+			ids = appendPatientIDs(ids, segments[i+1])
+		}
+	}
+
+	return appendValuesPatientIDs(ids, u.Query())
+}
+
+// formPatientIDs returns the patientid fields of a form body (e.g. from PostForm) without consuming it. Streamed
+// bodies (e.g. from PostFormReader) are not read.
+func formPatientIDs(headers http.Header, body io.Reader) []string {
+	r, ok := body.(sizedReaderAt)
+	if !ok || headers.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		return nil
+	}
+
+	b, err := io.ReadAll(io.NewSectionReader(r, r.Size()-int64(r.Len()), int64(r.Len())))
+	if err != nil {
+		return nil
+	}
+
+	v, err := url.ParseQuery(string(b))
+	if err != nil {
+		return nil
+	}
+
+	return appendValuesPatientIDs(nil, v)
+}
+
+// appendValuesPatientIDs appends the values of the patientid keys of v.
+func appendValuesPatientIDs(ids []string, v url.Values) []string {
+	for key, values := range v {
+		if strings.EqualFold(key, "patientid") {
+			ids = appendPatientIDs(ids, values...)
+		}
+	}
+
+	return ids
+}
+
+// bodyPatientIDs returns the values of every patientid field in a JSON response body.
+func bodyPatientIDs(body []byte) []string {
+	var ids []string
+
+	if len(body) == 0 {
+		return ids
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return ids
+	}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if strings.EqualFold(key, "patientid") {
+					switch id := value.(type) {
+					case string:
+						ids = append(ids, id)
+					case json.Number:
+						ids = append(ids, id.String())
+					}
+
+					continue
+				}
+
+				walk(value)
+			}
+
+		case []interface{}:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+
+	walk(v)
+
+	return appendPatientIDs(nil, ids...)
+}
+
+// appendPatientIDs appends the non-empty IDs not already in ids.
+func appendPatientIDs(ids []string, newIDs ...string) []string {
+	seen := make(map[string]bool, len(ids)+len(newIDs))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	for _, id := range newIDs {
+		if len(id) == 0 || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func isNumeric(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,254 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/auditor"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuditor struct {
+	events []*auditor.Event
+	err    error
+}
+
+func (t *testAuditor) Audit(ctx context.Context, event *auditor.Event) error {
+	t.events = append(t.events, event)
+
+	return t.err
+}
+
+func TestHTTPClient_audit(t *testing.T) {
+	assert := assert.New(t)
+
+	var xRequestID string
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		xRequestID = r.Header.Get(XRequestIDHeaderKey)
+
+		w.Write([]byte(`[{"patientid": "1", "firstname": "Jane"}]`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	ctx := auditor.WithPrincipal(context.Background(), "user-1")
+	ctx = auditor.WithPurpose(ctx, "treatment")
+
+	_, err := athenaClient.GetPatient(ctx, "1", nil)
+	assert.NoError(err)
+
+	if assert.Len(testAuditor.events, 1) {
+		event := testAuditor.events[0]
+
+		assert.Equal("user-1", event.Principal)
+		assert.Equal("treatment", event.Purpose)
+		assert.Equal("GetPatient", event.Operation)
+		assert.Equal(http.MethodGet, event.Method)
+		assert.Equal("/patients/:id:", event.Path)
+		assert.Equal(athenaClient.practiceID, event.PracticeID)
+		assert.Equal([]string{"1"}, event.PatientIDs)
+		assert.Equal(auditor.OutcomeSuccess, event.Outcome)
+		assert.Equal(http.StatusOK, event.StatusCode)
+		assert.Equal(xRequestID, event.XRequestID)
+		assert.False(event.Time.IsZero())
+	}
+}
+
+func TestHTTPClient_audit_response_patient_ids(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"appointments": [{"appointmentid": "1", "patientid": "2"}, {"appointmentid": "3", "patientid": "4"}, {"appointmentid": "5", "patientid": "2"}], "totalcount": 3}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	_, err := athenaClient.ListBookedAppointments(context.Background(), &ListBookedAppointmentsOptions{})
+	assert.NoError(err)
+
+	if assert.Len(testAuditor.events, 1) {
+		assert.Equal("ListBookedAppointments", testAuditor.events[0].Operation)
+		assert.Equal([]string{"2", "4"}, testAuditor.events[0].PatientIDs)
+	}
+}
+
+func TestHTTPClient_audit_write(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	err := athenaClient.UpdatePatientPhoto(context.Background(), "1", []byte("photo"))
+	assert.NoError(err)
+
+	if assert.Len(testAuditor.events, 1) {
+		assert.Equal("UpdatePatientPhoto", testAuditor.events[0].Operation)
+		assert.Equal(http.MethodPost, testAuditor.events[0].Method)
+		assert.Equal("/patients/:id:/photo", testAuditor.events[0].Path)
+		assert.Equal([]string{"1"}, testAuditor.events[0].PatientIDs)
+	}
+}
+
+func TestHTTPClient_audit_form_patient_ids(t *testing.T) {
+	assert := assert.New(t)
+
+	var body string
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+
+		w.Write([]byte(`[{"appointmentid": "2"}]`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	_, err := athenaClient.BookAppointment(context.Background(), "1", "2", nil)
+	assert.NoError(err)
+
+	// Reading the patient IDs does not consume the body.
+	assert.Equal("patientid=1", body)
+
+	if assert.Len(testAuditor.events, 1) {
+		assert.Equal("BookAppointment", testAuditor.events[0].Operation)
+		assert.Equal([]string{"1"}, testAuditor.events[0].PatientIDs)
+	}
+}
+
+func TestHTTPClient_audit_paginated(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"patients": [{"patientid": "1"}], "totalcount": 1}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	_, err := athenaClient.ListAllPatients(context.Background(), nil)
+	assert.NoError(err)
+
+	// Pages are fetched by a closure calling ListPatients and are recorded by the outermost method.
+	if assert.Len(testAuditor.events, 1) {
+		assert.Equal("ListAllPatients", testAuditor.events[0].Operation)
+	}
+}
+
+func TestHTTPClient_audit_failure(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "The patient is not found."}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.ErrorIs(err, ErrNotFound)
+
+	if assert.Len(testAuditor.events, 1) {
+		assert.Equal(auditor.OutcomeFailure, testAuditor.events[0].Outcome)
+		assert.Equal(http.StatusNotFound, testAuditor.events[0].StatusCode)
+		assert.Equal([]string{"1"}, testAuditor.events[0].PatientIDs)
+	}
+}
+
+func TestHTTPClient_audit_request(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	_, err := athenaClient.Get(context.Background(), "/chart/1/problems", nil, nil)
+	assert.NoError(err)
+
+	if assert.Len(testAuditor.events, 1) {
+		assert.Equal("GET /chart/:id:/problems", testAuditor.events[0].Operation)
+		assert.Equal([]string{"1"}, testAuditor.events[0].PatientIDs)
+	}
+}
+
+func TestHTTPClient_audit_error_logged(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf)
+
+	athenaClient.WithLogger(&logger).WithAuditor(&testAuditor{err: errors.New("disk full")})
+
+	_, err := athenaClient.Get(context.Background(), "/patients/1", nil, nil)
+	assert.NoError(err)
+
+	assert.Contains(buf.String(), "athenahealth API request audit failed")
+	assert.Contains(buf.String(), "disk full")
+}
+
+func TestPathPatientIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"1"}, pathPatientIDs("/patients/1/insurances/2"))
+	assert.Equal([]string{"1"}, pathPatientIDs("/chart/1/labresults"))
+	assert.Equal([]string{"3"}, pathPatientIDs("/appointments/booked?patientid=3"))
+	assert.Empty(pathPatientIDs("/patients/customfields/1/2"))
+	assert.Empty(pathPatientIDs("/chart/encounters/1/summary"))
+}
+
+func TestBodyPatientIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.ElementsMatch([]string{"1", "2"}, bodyPatientIDs([]byte(`{"patientid": 1, "appointments": [{"patientid": "2"}, {"PatientID": "1"}]}`)))
+	assert.Empty(bodyPatientIDs([]byte(`not json`)))
+	assert.Empty(bodyPatientIDs(nil))
+}
+
+func TestFormPatientIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	assert.Equal([]string{"1"}, formPatientIDs(headers, strings.NewReader("patientid=1&departmentid=2")))
+	assert.Empty(formPatientIDs(headers, strings.NewReader("departmentid=2")))
+	assert.Empty(formPatientIDs(http.Header{}, strings.NewReader("patientid=1")))
+	assert.Empty(formPatientIDs(headers, io.MultiReader(strings.NewReader("patientid=1"))))
+}
@@ -0,0 +1,29 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+)
+
+// Channel sends events to a channel, e.g. to be written to a database by another goroutine.
+type Channel struct {
+	events chan<- *Event
+}
+
+func NewChannel(events chan<- *Event) *Channel {
+	return &Channel{
+		events: events,
+	}
+}
+
+// Audit blocks until the event is received or ctx is done, which holds up the request being audited. Use a
+// buffered channel so that a slow receiver does not slow down requests.
+func (c *Channel) Audit(ctx context.Context, event *Event) error {
+	select {
+	case c.events <- event:
+		return nil
+
+	case <-ctx.Done():
+		return fmt.Errorf("sending audit event: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,34 @@
+package auditor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannel_Audit(t *testing.T) {
+	assert := assert.New(t)
+
+	events := make(chan *Event, 1)
+
+	auditor := NewChannel(events)
+
+	event := &Event{Operation: "GetPatient"}
+
+	err := auditor.Audit(context.Background(), event)
+	assert.NoError(err)
+	assert.Equal(event, <-events)
+}
+
+func TestChannel_Audit_contextDone(t *testing.T) {
+	assert := assert.New(t)
+
+	auditor := NewChannel(make(chan *Event))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := auditor.Audit(ctx, &Event{Operation: "GetPatient"})
+	assert.ErrorIs(err, context.Canceled)
+}
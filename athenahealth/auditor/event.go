@@ -0,0 +1,68 @@
+package auditor
+
+import (
+	"context"
+	"time"
+)
+
+// Outcome is the outcome of a request to athena.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Event records a single request to athena made on behalf of a principal.
+type Event struct {
+	Time time.Time `json:"time"`
+
+	// Principal is the user or service the request was made on behalf of. See WithPrincipal.
+	Principal string `json:"principal,omitempty"`
+	// Purpose is why the request was made, e.g. treatment or billing. See WithPurpose.
+	Purpose string `json:"purpose,omitempty"`
+
+	// Operation is the name of the Client method that made the request, e.g. GetPatient.
+	Operation string `json:"operation"`
+	Method    string `json:"method"`
+	// Path has IDs replaced by :id:, e.g. /patients/:id:.
+	Path       string `json:"path"`
+	PracticeID string `json:"practiceId"`
+
+	// PatientIDs are the IDs of the patients whose data was read or written, taken from the
+	// request path and query and the response body.
+	PatientIDs []string `json:"patientIds"`
+
+	Outcome    Outcome `json:"outcome"`
+	StatusCode int     `json:"statusCode,omitempty"`
+	XRequestID string  `json:"xRequestId"`
+}
+
+type principalContextKey struct{}
+
+type purposeContextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the user or service that requests made with it are
+// made on behalf of.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, or an empty string if it has none.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalContextKey{}).(string)
+
+	return principal
+}
+
+// WithPurpose returns a copy of ctx carrying why requests made with it are made.
+func WithPurpose(ctx context.Context, purpose string) context.Context {
+	return context.WithValue(ctx, purposeContextKey{}, purpose)
+}
+
+// PurposeFromContext returns the purpose carried by ctx, or an empty string if it has none.
+func PurposeFromContext(ctx context.Context) string {
+	purpose, _ := ctx.Value(purposeContextKey{}).(string)
+
+	return purpose
+}
@@ -0,0 +1,22 @@
+package auditor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	assert.Empty(PrincipalFromContext(ctx))
+	assert.Empty(PurposeFromContext(ctx))
+
+	ctx = WithPrincipal(ctx, "user-1")
+	ctx = WithPurpose(ctx, "treatment")
+
+	assert.Equal("user-1", PrincipalFromContext(ctx))
+	assert.Equal("treatment", PurposeFromContext(ctx))
+}
@@ -0,0 +1,52 @@
+package auditor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// File appends events to a file as JSON lines.
+type File struct {
+	path string
+
+	lock sync.Mutex
+}
+
+func NewFile(path string) *File {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	return &File{
+		path: path,
+	}
+}
+
+func (f *File) Audit(ctx context.Context, event *Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	// The file is opened for every event so that it can be rotated without restarting the process.
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(line)
+	if err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,66 @@
+package auditor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile_Audit(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	auditor := NewFile(path)
+
+	err := auditor.Audit(context.Background(), &Event{
+		Principal:  "user-1",
+		Operation:  "GetPatient",
+		PatientIDs: []string{"1"},
+		Outcome:    OutcomeSuccess,
+	})
+	assert.NoError(err)
+
+	err = auditor.Audit(context.Background(), &Event{
+		Principal:  "user-2",
+		Operation:  "UpdatePatient",
+		PatientIDs: []string{"2"},
+		Outcome:    OutcomeFailure,
+	})
+	assert.NoError(err)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var events []*Event
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		event := &Event{}
+		assert.NoError(json.Unmarshal(scanner.Bytes(), event))
+
+		events = append(events, event)
+	}
+
+	if assert.Len(events, 2) {
+		assert.Equal("user-1", events[0].Principal)
+		assert.Equal("GetPatient", events[0].Operation)
+		assert.Equal([]string{"1"}, events[0].PatientIDs)
+		assert.Equal(OutcomeSuccess, events[0].Outcome)
+
+		assert.Equal("user-2", events[1].Principal)
+		assert.Equal(OutcomeFailure, events[1].Outcome)
+	}
+
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+}
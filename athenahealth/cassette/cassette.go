@@ -0,0 +1,99 @@
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"unicode/utf8"
+)
+
+// Cassette is the file format of recorded interactions.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response athena sent to it.
+type Interaction struct {
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	// URL has numeric IDs in its path replaced by :id: and scrubbed query values replaced by redact.Redacted.
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    *Body       `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       *Body       `json:"body,omitempty"`
+}
+
+// Body is a request or response body. Bodies that are not valid UTF-8 are base64 encoded.
+type Body struct {
+	Data     string `json:"data"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+const encodingBase64 = "base64"
+
+func newBody(b []byte) *Body {
+	if len(b) == 0 {
+		return nil
+	}
+
+	if !utf8.Valid(b) {
+		return &Body{
+			Data:     base64.StdEncoding.EncodeToString(b),
+			Encoding: encodingBase64,
+		}
+	}
+
+	return &Body{
+		Data: string(b),
+	}
+}
+
+// Bytes returns the decoded body.
+func (b *Body) Bytes() ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	if b.Encoding == encodingBase64 {
+		return base64.StdEncoding.DecodeString(b.Data)
+	}
+
+	return []byte(b.Data), nil
+}
+
+// Load reads a cassette from a file.
+func Load(path string) (*Cassette, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{}
+
+	err = json.Unmarshal(contents, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Save writes the cassette to a file, replacing it if it exists.
+func (c *Cassette) Save(path string) error {
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, contents, 0600)
+}
@@ -0,0 +1,273 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/redact"
+)
+
+var ErrInteractionNotFound = errors.New("cassette: no recorded interaction matches request")
+
+type Mode int
+
+const (
+	// ModeReplay serves recorded responses without sending requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests and records them and their responses, replacing the cassette.
+	ModeRecord
+)
+
+// Recorder is an http.RoundTripper that records interactions with athena to a cassette file, or replays
+// them offline. Requests are matched on their method, their path with numeric IDs replaced by :id: and
+// their query with scrubbed values replaced, and requests that match the same interactions are served
+// them in the order they were recorded.
+//
+// Tokens and other credentials are always scrubbed from recorded interactions, as are the query
+// parameters, form fields and JSON fields in DefaultScrubbed. Bodies that are neither JSON nor form
+// encoded are recorded unchanged.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	scrubbed map[string]bool
+	allowed  map[string]bool
+
+	cassette *Cassette
+	// played counts the interactions served for each request key.
+	played map[string]int
+
+	lock sync.Mutex
+}
+
+func New(path string, mode Mode) *Recorder {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+
+		scrubbed: make(map[string]bool),
+		allowed:  make(map[string]bool),
+
+		played: make(map[string]int),
+	}
+
+	r.WithScrubbed(DefaultScrubbed...)
+	r.WithScrubbed(secretKeys...)
+
+	return r
+}
+
+// WithTransport sets the transport used to send requests in ModeRecord. It defaults to http.DefaultTransport.
+func (r *Recorder) WithTransport(transport http.RoundTripper) *Recorder {
+	r.transport = transport
+
+	return r
+}
+
+// WithScrubbed scrubs additional query parameters, form fields and JSON fields.
+func (r *Recorder) WithScrubbed(keys ...string) *Recorder {
+	for _, key := range keys {
+		r.scrubbed[strings.ToLower(key)] = true
+	}
+
+	return r
+}
+
+// WithAllowed stops scrubbing query parameters, form fields and JSON fields, e.g. those in DefaultScrubbed.
+// Credentials are always scrubbed.
+func (r *Recorder) WithAllowed(keys ...string) *Recorder {
+	for _, key := range keys {
+		key = strings.ToLower(key)
+
+		isSecret := false
+		for _, secretKey := range secretKeys {
+			if key == secretKey {
+				isSecret = true
+				break
+			}
+		}
+
+		if !isSecret {
+			r.allowed[key] = true
+		}
+	}
+
+	return r
+}
+
+// Client returns an http.Client that sends requests through the recorder, e.g. to pass to athenahealth.NewHTTPClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{
+		Transport: r,
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+
+	return r.replay(req)
+}
+
+// key returns the scrubbed URL of req, with numeric IDs in its path replaced, and the key it is matched on.
+func (r *Recorder) key(method string, u *url.URL) (string, string) {
+	path := pathutil.Normalize(u.EscapedPath())
+
+	query := ""
+	if len(u.RawQuery) > 0 {
+		query = "?" + strings.ReplaceAll(r.scrubValues(u.Query()).Encode(), url.QueryEscape(redact.Redacted), redact.Redacted)
+	}
+
+	scrubbedURL := path + query
+	if len(u.Host) > 0 {
+		scrubbedURL = u.Scheme + "://" + u.Host + scrubbedURL
+	}
+
+	return scrubbedURL, method + " " + path + query
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	scrubbedURL, _ := r.key(req.Method, req.URL)
+
+	interaction := &Interaction{
+		Request: &Request{
+			Method:  req.Method,
+			URL:     scrubbedURL,
+			Headers: r.scrubHeaders(req.Header),
+			Body:    newBody(r.scrubBody(req.Header.Get("Content-Type"), reqBody)),
+		},
+		Response: &Response{
+			StatusCode: res.StatusCode,
+			Headers:    r.scrubHeaders(res.Header),
+			Body:       newBody(r.scrubBody(res.Header.Get("Content-Type"), resBody)),
+		},
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Recording replaces the existing cassette.
+	if r.cassette == nil {
+		r.cassette = &Cassette{}
+	}
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+
+	// The cassette is saved after every interaction so tests do not need to save it when they finish.
+	err = r.cassette.Save(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("saving cassette: %w", err)
+	}
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.cassette == nil {
+		cassette, err := Load(r.path)
+		if err != nil {
+			return nil, fmt.Errorf("loading cassette: %w", err)
+		}
+
+		r.cassette = cassette
+	}
+
+	_, key := r.key(req.Method, req.URL)
+
+	var interaction *Interaction
+
+	skip := r.played[key]
+	for _, i := range r.cassette.Interactions {
+		u, err := url.Parse(i.Request.URL)
+		if err != nil {
+			continue
+		}
+
+		if _, iKey := r.key(i.Request.Method, u); iKey != key {
+			continue
+		}
+
+		if skip == 0 {
+			interaction = i
+			break
+		}
+
+		skip--
+	}
+
+	if interaction == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInteractionNotFound, key)
+	}
+
+	r.played[key]++
+
+	body, err := interaction.Response.Body.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+
+	if res.Header == nil {
+		res.Header = http.Header{}
+	}
+
+	return res, nil
+}
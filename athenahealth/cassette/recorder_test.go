@@ -0,0 +1,188 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, client *http.Client, rawURL string) (*http.Response, string) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res, string(b)
+}
+
+func TestRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+
+		switch r.URL.Path {
+		case "/oauth2/v1/token":
+			w.Write([]byte(`{"access_token": "secret-token", "expires_in": "3600"}`))
+
+		case "/v1/195900/patients/1":
+			w.Write([]byte(`[{"patientid": "1", "firstname": "Jane", "lastname": "Doe", "dob": "01/01/1980", "balances": [{"balance": 10}], "name": {"first": "Jane"}}]`))
+
+		case "/v1/195900/patients":
+			w.Write([]byte(`{"patients": [{"patientid": "` + r.URL.Query().Get("departmentid") + `"}], "next": "` + strconv.Itoa(calls) + `"}`))
+		}
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(h))
+
+	recorder := New(path, ModeRecord).WithTransport(ts.Client().Transport)
+
+	tokenRes, err := recorder.Client().PostForm(ts.URL+"/oauth2/v1/token", url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_secret": {"secret-client-secret"},
+	})
+	assert.NoError(err)
+	tokenRes.Body.Close()
+
+	_, recordedPatient := get(t, recorder.Client(), ts.URL+"/v1/195900/patients/1")
+	assert.Contains(recordedPatient, "Jane")
+
+	_, recordedPatients1 := get(t, recorder.Client(), ts.URL+"/v1/195900/patients?departmentid=1&firstname=Jane")
+	_, recordedPatients2 := get(t, recorder.Client(), ts.URL+"/v1/195900/patients?departmentid=2&firstname=Jane")
+	_, recordedPatients3 := get(t, recorder.Client(), ts.URL+"/v1/195900/patients?departmentid=1&firstname=Jane")
+
+	ts.Close()
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"secret-token", "secret-client-secret", "secret-cookie", "Jane", "Doe", "1980"} {
+		assert.NotContains(string(contents), secret)
+	}
+
+	// Replay serves the same responses without a server, with credentials and PHI scrubbed.
+	replayer := New(path, ModeReplay)
+
+	res, patient := get(t, replayer.Client(), "https://api.preview.platform.athenahealth.com/v1/195900/patients/2")
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("application/json", res.Header.Get("Content-Type"))
+	assert.JSONEq(`[{"patientid": "1", "firstname": "[REDACTED]", "lastname": "[REDACTED]", "dob": "[REDACTED]", "balances": [{"balance": 10}], "name": {"first": "[REDACTED]"}}]`, patient)
+
+	// Requests with the same key are served in the order they were recorded.
+	_, patients := get(t, replayer.Client(), "https://api.preview.platform.athenahealth.com/v1/195900/patients?firstname=Bob&departmentid=1")
+	assert.JSONEq(recordedPatients1, patients)
+
+	_, patients = get(t, replayer.Client(), "https://api.preview.platform.athenahealth.com/v1/195900/patients?firstname=Bob&departmentid=1")
+	assert.JSONEq(recordedPatients3, patients)
+
+	_, patients = get(t, replayer.Client(), "https://api.preview.platform.athenahealth.com/v1/195900/patients?firstname=Bob&departmentid=2")
+	assert.JSONEq(recordedPatients2, patients)
+
+	_, err = replayer.Client().Get("https://api.preview.platform.athenahealth.com/v1/195900/patients?departmentid=1")
+	assert.True(errors.Is(err, ErrInteractionNotFound))
+
+	assert.Equal(5, calls)
+}
+
+func TestRecorder_WithAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sex": "F", "access_token": "secret-token", "mrn": "MRN-4242"}`))
+	}))
+	defer ts.Close()
+
+	recorder := New(path, ModeRecord).
+		WithTransport(ts.Client().Transport).
+		WithAllowed("sex", "access_token").
+		WithScrubbed("mrn")
+
+	get(t, recorder.Client(), ts.URL+"/v1/195900/patients/1")
+
+	_, body := get(t, New(path, ModeReplay).Client(), ts.URL+"/v1/195900/patients/1")
+	assert.JSONEq(`{"sex": "F", "access_token": "[REDACTED]", "mrn": "[REDACTED]"}`, body)
+}
+
+func TestRecorder_custom_field_value(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"patients": []}`))
+	}))
+	defer ts.Close()
+
+	get(t, New(path, ModeRecord).WithTransport(ts.Client().Transport).Client(), ts.URL+"/v1/195900/patients/customfields/1/secret-value")
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotContains(string(contents), "secret-value")
+	assert.Contains(string(contents), "/patients/customfields/:id:/:value:")
+
+	_, body := get(t, New(path, ModeReplay).Client(), ts.URL+"/v1/195900/patients/customfields/1/other-value")
+	assert.JSONEq(`{"patients": []}`, body)
+}
+
+func TestRecorder_binary_body(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte{0xff, 0xd8, 0xff, 0x00})
+	}))
+	defer ts.Close()
+
+	get(t, New(path, ModeRecord).WithTransport(ts.Client().Transport).Client(), ts.URL+"/v1/195900/patients/1/photo")
+
+	_, body := get(t, New(path, ModeReplay).Client(), ts.URL+"/v1/195900/patients/1/photo")
+	assert.Equal(string([]byte{0xff, 0xd8, 0xff, 0x00}), body)
+}
+
+func TestRecorder_replay_missing_cassette(t *testing.T) {
+	assert := assert.New(t)
+
+	replayer := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+
+	_, err := replayer.Client().Get("https://api.preview.platform.athenahealth.com/v1/195900/patients/1")
+	assert.ErrorContains(err, "loading cassette")
+}
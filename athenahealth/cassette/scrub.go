@@ -0,0 +1,160 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/redact"
+)
+
+// DefaultScrubbed are the query parameters, form fields and JSON fields scrubbed by default. They are
+// redact.DefaultDenied without patient and enterprise IDs, which replayed tests often need to chain requests.
+var DefaultScrubbed = func() []string {
+	var keys []string
+
+	for _, key := range redact.DefaultDenied {
+		if key != "patientid" && key != "enterpriseid" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}()
+
+// secretKeys are the form and JSON fields that are always scrubbed since they hold credentials.
+var secretKeys = []string{
+	"access_token",
+	"client_assertion",
+	"client_secret",
+	"id_token",
+	"refresh_token",
+}
+
+// secretHeaders are the headers that are always scrubbed since they hold credentials.
+var secretHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+func (r *Recorder) scrubs(key string) bool {
+	key = strings.ToLower(key)
+
+	return r.scrubbed[key] && !r.allowed[key]
+}
+
+func (r *Recorder) scrubHeaders(headers http.Header) http.Header {
+	headers = headers.Clone()
+
+	for _, header := range secretHeaders {
+		if len(headers.Values(header)) > 0 {
+			headers.Set(header, redact.Redacted)
+		}
+	}
+
+	return headers
+}
+
+func (r *Recorder) scrubValues(values url.Values) url.Values {
+	scrubbed := make(url.Values, len(values))
+
+	for k, v := range values {
+		if r.scrubs(k) {
+			scrubbed[k] = []string{redact.Redacted}
+		} else {
+			scrubbed[k] = v
+		}
+	}
+
+	return scrubbed
+}
+
+// scrubBody returns a copy of a JSON or form encoded body with scrubbed fields replaced. Other bodies are
+// returned unchanged.
+func (r *Recorder) scrubBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			break
+		}
+
+		return []byte(r.scrubValues(values).Encode())
+
+	case "application/json", "":
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+
+		var v interface{}
+
+		err := d.Decode(&v)
+		if err != nil {
+			break
+		}
+
+		b, err := json.Marshal(r.scrubJSON(v))
+		if err != nil {
+			break
+		}
+
+		return b
+	}
+
+	return body
+}
+
+// scrubJSON replaces the values of scrubbed fields, keeping their type so that replayed responses can
+// still be unmarshaled.
+func (r *Recorder) scrubJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fieldValue := range v {
+			if !r.scrubs(k) {
+				v[k] = r.scrubJSON(fieldValue)
+				continue
+			}
+
+			v[k] = scrubAll(fieldValue)
+		}
+
+	case []interface{}:
+		for i := range v {
+			v[i] = r.scrubJSON(v[i])
+		}
+	}
+
+	return v
+}
+
+// scrubAll replaces every string and number in v.
+func scrubAll(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return redact.Redacted
+
+	case json.Number:
+		return json.Number("0")
+
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = scrubAll(v[k])
+		}
+
+	case []interface{}:
+		for i := range v {
+			v[i] = scrubAll(v[i])
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,41 @@
+package athenahealth
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/cassette"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient_cassette(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"patientid": "1", "firstname": "Jane", "lastname": "Doe"}]`))
+	}
+
+	athenaClient, ts := testClient(h)
+
+	recorder := cassette.New(path, cassette.ModeRecord).WithTransport(ts.Client().Transport)
+	athenaClient.httpClient = recorder.Client()
+
+	recorded, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+	assert.Equal("Jane", recorded.FirstName)
+
+	ts.Close()
+
+	athenaClient.httpClient = cassette.New(path, cassette.ModeReplay).Client()
+
+	replayed, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+	assert.Equal("1", replayed.PatientID)
+	assert.Equal(redact.Redacted, replayed.FirstName)
+}
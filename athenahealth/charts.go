@@ -28,6 +28,8 @@ type SocialHistoryQuestion struct {
 //
 // https://docs.athenahealth.com/api/api-ref/social-history#Get-list-of-social-history-questions-and-templates-used-by-this-practice
 func (h *HTTPClient) ListSocialHistoryTemplates(ctx context.Context) ([]*SocialHistoryTemplate, error) {
+	ctx = withOperation(ctx, "ListSocialHistoryTemplates")
+
 	out := []*SocialHistoryTemplate{}
 
 	_, err := h.Get(ctx, "/chart/configuration/socialhistory", nil, &out)
@@ -72,6 +74,8 @@ type GetPatientSocialHistoryResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/social-history#Get-patient's-social-history-data
 func (h *HTTPClient) GetPatientSocialHistory(ctx context.Context, patientID string, opts *GetPatientSocialHistoryOptions) (*GetPatientSocialHistoryResponse, error) {
+	ctx = withOperation(ctx, "GetPatientSocialHistory")
+
 	out := &GetPatientSocialHistoryResponse{}
 
 	q := url.Values{}
@@ -122,6 +126,8 @@ type UpdatePatientSocialHistoryOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/social-history#Update-patient's-social-history-data
 func (h *HTTPClient) UpdatePatientSocialHistory(ctx context.Context, patientID string, opts *UpdatePatientSocialHistoryOptions) error {
+	ctx = withOperation(ctx, "UpdatePatientSocialHistory")
+
 	var form url.Values
 
 	if opts != nil {
@@ -52,6 +52,8 @@ type createClaimResponse struct {
 }
 
 func (h *HTTPClient) CreateFinancialClaim(ctx context.Context, opts *CreateClaimOptions) ([]string, error) {
+	ctx = withOperation(ctx, "CreateFinancialClaim")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -180,6 +182,8 @@ type listClaimsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/claim#Get-list-of-claim-details
 func (h *HTTPClient) ListClaims(ctx context.Context, opts *ListClaimsOptions) (*ListClaimsResult, error) {
+	ctx = withOperation(ctx, "ListClaims")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -231,3 +235,26 @@ func (h *HTTPClient) ListClaims(ctx context.Context, opts *ListClaimsOptions) (*
 		Pagination: makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// ListAllClaims - Get all claims
+func (h *HTTPClient) ListAllClaims(ctx context.Context, opts *ListClaimsOptions) ([]*Claim, error) {
+	ctx = withOperation(ctx, "ListAllClaims")
+
+	if opts == nil {
+		panic("opts is nil")
+	}
+
+	o := *opts
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*Claim, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListClaims(ctx, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Claims, res.Pagination, nil
+	})
+}
@@ -150,3 +150,21 @@ func TestHTTPClient_ListClaims(t *testing.T) {
 	assert.Equal(res.Pagination.TotalCount, 1)
 	assert.NoError(err)
 }
+
+func TestHTTPClient_ListAllClaims_nil_opts(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	// ListAllClaims requires options like ListClaims does.
+	assert.PanicsWithValue("opts is nil", func() {
+		athenaClient.ListClaims(context.Background(), nil)
+	})
+	assert.PanicsWithValue("opts is nil", func() {
+		athenaClient.ListAllClaims(context.Background(), nil)
+	})
+	assert.PanicsWithValue("opts is nil", func() {
+		athenaClient.ListAllPatientsMatchingCustomField(context.Background(), nil)
+	})
+}
@@ -3,7 +3,12 @@ package athenahealth
 import (
 	"context"
 	"io"
+	"net/http"
 	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/auditor"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
 )
 
 // Client describes a client for the athenahealth API.
@@ -139,17 +144,105 @@ type TokenProvider interface {
 	Provide(context.Context) (string, time.Time, error)
 }
 
-type TokenCacher interface {
-	Get(context.Context) (string, error)
-	Set(context.Context, string, time.Time) error
+type TokenCacher = tokencacher.TokenCacher
+
+// NamespacedTokenCacher is implemented by TokenCachers whose storage may be shared between
+// clients for different environments or client IDs. HTTPClient caches tokens in a copy of the
+// cacher scoped to its environment and client ID, so one cacher can be given to many clients.
+type NamespacedTokenCacher interface {
+	TokenCacher
+	WithNamespace(environment, clientID string) TokenCacher
+}
+
+// ExpiringTokenCacher is implemented by TokenCachers that can report when the cached token expires.
+// The token refresher uses it to leave alone a token that is not yet due to be refreshed, such as one
+// refreshed by another client sharing the cache.
+type ExpiringTokenCacher interface {
+	TokenCacher
+	ExpiresAt(context.Context) (time.Time, error)
+}
+
+// LockingTokenCacher is implemented by TokenCachers that make other processes wait for the token while
+// one fetches it. Unlock is called if the token could not be fetched, so they stop waiting.
+type LockingTokenCacher interface {
+	TokenCacher
+	Unlock(context.Context) error
 }
 
 type RateLimiter interface {
 	Allowed(ctx context.Context, preview bool) (retryAfter time.Duration, err error)
 }
 
+// RouteRateLimiter is implemented by RateLimiters that enforce per-endpoint limits or a per-practice
+// quota. HTTPClient calls AllowedRoute instead of Allowed with the request's method and path, with
+// numeric IDs replaced by :id:, e.g. /patients/:id:/documents. Returning ratelimiter.ErrQuotaExhausted
+// fails the request instead of waiting for retryAfter.
+type RouteRateLimiter interface {
+	RateLimiter
+	AllowedRoute(ctx context.Context, preview bool, practiceID, method, path string) (retryAfter time.Duration, err error)
+}
+
+// Tracer starts the spans traced for each request. See oteltracer for an OpenTelemetry Tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	AddEvent(name string, attrs ...Attribute)
+	SetAttributes(attrs ...Attribute)
+	// RecordError records err and marks the span as failed.
+	RecordError(err error)
+	End()
+}
+
+// Redactor removes PHI from URLs and bodies before they are logged.
+type Redactor interface {
+	URL(rawURL string) string
+	Body(contentType string, body []byte) string
+}
+
+// Auditor records every request to athena, e.g. to keep an audit trail of access to patient data. Audit is
+// called before the request returns, so it should not block for long.
+type Auditor interface {
+	Audit(ctx context.Context, event *auditor.Event) error
+}
+
+type ConcurrencyLimiter interface {
+	// Acquire blocks until a slot is available or ctx is done. The returned func releases the slot.
+	Acquire(ctx context.Context, preview bool) (release func(), err error)
+}
+
+type RetryPolicy interface {
+	// Retryable reports whether requests with the given method may be attempted more than once.
+	Retryable(method string) bool
+	// Retry is called after a failed attempt and reports whether the request should be
+	// attempted again and how long to wait before doing so.
+	Retry(attempt int, res *http.Response, err error) (retryAfter time.Duration, retry bool)
+}
+
+// RateLimitObserver is implemented by RateLimiters that adapt to athena's responses, e.g. by
+// slowing down when requests are throttled. HTTPClient reports every response it receives.
+type RateLimitObserver interface {
+	RateLimiter
+	ObserveResponse(preview bool, res *http.Response)
+}
+
 type Stats interface {
 	Request(method, path string) error
 	ResponseSuccess() error
 	ResponseError() error
 }
+
+// TokenRefreshStats is implemented by Stats that record the outcome of background token refreshes.
+type TokenRefreshStats interface {
+	Stats
+	TokenRefreshSuccess() error
+	TokenRefreshError() error
+}
+
+// ResponseStats is implemented by Stats that record the details of every response, such as its
+// status code, latency and size.
+type ResponseStats interface {
+	Stats
+	Response(res *stats.Response) error
+}
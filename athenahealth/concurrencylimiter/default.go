@@ -0,0 +1,14 @@
+package concurrencylimiter
+
+import "context"
+
+type Default struct {
+}
+
+func NewDefault() *Default {
+	return &Default{}
+}
+
+func (d *Default) Acquire(ctx context.Context, preview bool) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,48 @@
+package concurrencylimiter
+
+import (
+	"context"
+	"fmt"
+)
+
+const defaultLimitPreview = 5
+const defaultLimitProd = 50
+
+// Local limits the requests in flight at once within a single process.
+type Local struct {
+	preview chan struct{}
+	prod    chan struct{}
+}
+
+func NewLocal(limitPreview, limitProd int) *Local {
+	if limitPreview <= 0 {
+		limitPreview = defaultLimitPreview
+	}
+
+	if limitProd <= 0 {
+		limitProd = defaultLimitProd
+	}
+
+	return &Local{
+		preview: make(chan struct{}, limitPreview),
+		prod:    make(chan struct{}, limitProd),
+	}
+}
+
+func (l *Local) Acquire(ctx context.Context, preview bool) (func(), error) {
+	sem := l.prod
+	if preview {
+		sem = l.preview
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for concurrency slot: %w", ctx.Err())
+
+	case sem <- struct{}{}:
+	}
+
+	return func() {
+		<-sem
+	}, nil
+}
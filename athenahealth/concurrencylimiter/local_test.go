@@ -0,0 +1,72 @@
+package concurrencylimiter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal_Acquire(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewLocal(1, 3)
+
+	var inFlight, maxInFlight atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release, err := limiter.Acquire(context.Background(), false)
+			assert.NoError(err)
+			defer release()
+
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(int32(3), maxInFlight.Load())
+}
+
+func TestLocal_Acquire_deadline(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewLocal(1, 1)
+
+	release, err := limiter.Acquire(context.Background(), true)
+	assert.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.Acquire(ctx, true)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	// Environments are limited independently.
+	releaseProd, err := limiter.Acquire(context.Background(), false)
+	assert.NoError(err)
+	releaseProd()
+
+	release()
+
+	release, err = limiter.Acquire(context.Background(), true)
+	assert.NoError(err)
+	release()
+}
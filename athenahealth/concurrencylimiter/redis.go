@@ -0,0 +1,144 @@
+package concurrencylimiter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const redisKeyPreview = "athena_concurrency:preview"
+const redisKeyProd = "athena_concurrency:prod"
+
+const defaultRedisLease = 30 * time.Second
+const redisPollInterval = 50 * time.Millisecond
+
+// acquireScript adds ARGV[3] to the sorted set of slot holders in KEYS[1], scored by when its lease
+// expires, if fewer than ARGV[1] holders have unexpired leases at ARGV[2].
+var acquireScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[2])
+
+if redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[1]) then
+	return 0
+end
+
+redis.call("ZADD", KEYS[1], ARGV[4], ARGV[3])
+
+return 1
+`)
+
+// Redis limits the requests in flight at once across every process sharing the Redis instance.
+// Slots are leased so that a process that dies while holding one does not leak it; the lease is
+// renewed in the background for as long as the slot is held.
+type Redis struct {
+	client *redis.Client
+
+	limitPreview int
+	limitProd    int
+	lease        time.Duration
+}
+
+func NewRedis(client *redis.Client, limitPreview, limitProd int) *Redis {
+	if client == nil {
+		panic("client is nil")
+	}
+
+	if limitPreview <= 0 {
+		limitPreview = defaultLimitPreview
+	}
+
+	if limitProd <= 0 {
+		limitProd = defaultLimitProd
+	}
+
+	return &Redis{
+		client: client,
+
+		limitPreview: limitPreview,
+		limitProd:    limitProd,
+		lease:        defaultRedisLease,
+	}
+}
+
+// WithLease sets how long a slot is held by a process that stops renewing it. It defaults to 30 seconds.
+func (r *Redis) WithLease(lease time.Duration) *Redis {
+	if lease > 0 {
+		r.lease = lease
+	}
+
+	return r
+}
+
+func (r *Redis) Acquire(ctx context.Context, preview bool) (func(), error) {
+	key := redisKeyProd
+	limit := r.limitProd
+
+	if preview {
+		key = redisKeyPreview
+		limit = r.limitPreview
+	}
+
+	id := uuid.NewString()
+
+	for {
+		now := time.Now()
+
+		acquired, err := acquireScript.Run(ctx, r.client, []string{key}, limit, now.UnixMilli(), id, now.Add(r.lease).UnixMilli()).Bool()
+		if err != nil {
+			return nil, err
+		}
+
+		if acquired {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for concurrency slot: %w", ctx.Err())
+
+		case <-time.After(redisPollInterval):
+		}
+	}
+
+	renewCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+
+	go r.renew(renewCtx, key, id, done)
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+
+			// The slot expires with its lease if it cannot be removed.
+			//nolint
+			r.client.ZRem(context.WithoutCancel(ctx), key, id)
+		})
+	}, nil
+}
+
+func (r *Redis) renew(ctx context.Context, key, id string, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			//nolint
+			r.client.ZAddXX(ctx, key, &redis.Z{
+				Score:  float64(time.Now().Add(r.lease).UnixMilli()),
+				Member: id,
+			})
+		}
+	}
+}
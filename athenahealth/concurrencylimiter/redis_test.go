@@ -0,0 +1,121 @@
+package concurrencylimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedis_Acquire(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	// Separate instances share slots, as separate processes would.
+	limiter := NewRedis(client, 1, 2)
+	otherLimiter := NewRedis(client, 1, 2)
+
+	release1, err := limiter.Acquire(context.Background(), false)
+	assert.NoError(err)
+
+	release2, err := otherLimiter.Acquire(context.Background(), false)
+	assert.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.Acquire(ctx, false)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	releasePreview, err := limiter.Acquire(context.Background(), true)
+	assert.NoError(err)
+	releasePreview()
+
+	release1()
+	// Releasing twice does not free another holder's slot.
+	release1()
+
+	release3, err := limiter.Acquire(context.Background(), false)
+	assert.NoError(err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.Acquire(ctx, false)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	release2()
+	release3()
+
+	members, err := client.ZCard(context.Background(), redisKeyProd).Result()
+	assert.NoError(err)
+	assert.Zero(members)
+}
+
+func TestRedis_Acquire_expired_lease(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	// A slot left behind by a process that died without releasing it.
+	err = client.ZAdd(context.Background(), redisKeyProd, &redis.Z{
+		Score:  float64(time.Now().Add(-time.Second).UnixMilli()),
+		Member: "dead",
+	}).Err()
+	assert.NoError(err)
+
+	limiter := NewRedis(client, 1, 1)
+
+	release, err := limiter.Acquire(context.Background(), false)
+	assert.NoError(err)
+	release()
+}
+
+func TestRedis_Acquire_renews_lease(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	limiter := NewRedis(client, 1, 1).WithLease(150 * time.Millisecond)
+
+	release, err := limiter.Acquire(context.Background(), false)
+	assert.NoError(err)
+	defer release()
+
+	// The slot is still held after its original lease would have expired.
+	time.Sleep(300 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.Acquire(ctx, false)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
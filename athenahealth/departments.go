@@ -16,6 +16,8 @@ type GetRequiredCheckInFieldsResult struct {
 // GET /v1/{practiceid}/departments/{departmentid}/checkinrequired
 // https://docs.athenahealth.com/api/api-ref/required-fields-check#Get-list-of-required-fields-for-patient-check-in
 func (h *HTTPClient) DepartmentGetRequiredCheckInFields(ctx context.Context, deptID string) (*GetRequiredCheckInFieldsResult, error) {
+	ctx = withOperation(ctx, "DepartmentGetRequiredCheckInFields")
+
 	if deptID == "" {
 		return nil, fmt.Errorf("cannot DepartmentGetRequiredCheckInFields with empty deptID [%s]", deptID)
 	}
@@ -66,6 +68,8 @@ type Department struct {
 //
 // https://docs.athenahealth.com/api/api-ref/departments#Get-specific-department-information
 func (h *HTTPClient) GetDepartment(ctx context.Context, id string) (*Department, error) {
+	ctx = withOperation(ctx, "GetDepartment")
+
 	out := []*Department{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/departments/%s", id), nil, &out)
@@ -106,6 +110,8 @@ type listDepartmentsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/departments-reference#Get-list-of-all-departments
 func (h *HTTPClient) ListDepartments(ctx context.Context, opts *ListDepartmentsOptions) (*ListDepartmentsResult, error) {
+	ctx = withOperation(ctx, "ListDepartments")
+
 	out := &listDepartmentsResponse{}
 
 	q := url.Values{}
@@ -144,3 +150,25 @@ func (h *HTTPClient) ListDepartments(ctx context.Context, opts *ListDepartmentsO
 		Pagination:  makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// ListAllDepartments - Get all departments
+func (h *HTTPClient) ListAllDepartments(ctx context.Context, opts *ListDepartmentsOptions) ([]*Department, error) {
+	ctx = withOperation(ctx, "ListAllDepartments")
+
+	o := ListDepartmentsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*Department, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListDepartments(ctx, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Departments, res.Pagination, nil
+	})
+}
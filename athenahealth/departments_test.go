@@ -18,7 +18,7 @@ func TestHTTPClient_DepartmentGetRequiredCheckInFields(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.NoError(r.ParseForm())
 
-		assert.Equal(r.URL.Path, "/departments/45/checkinrequired")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/departments/45/checkinrequired")
 		b, _ := os.ReadFile("./resources/DepartmentGetRequiredCheckInFields.json")
 		w.Write(b)
 	}
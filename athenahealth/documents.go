@@ -56,6 +56,8 @@ type listAdminDocumentsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-admin-document#Get-list-of-patient's-admin-documents
 func (h *HTTPClient) ListAdminDocuments(ctx context.Context, patientID string, opts *ListAdminDocumentsOptions) (*ListAdminDocumentsResult, error) {
+	ctx = withOperation(ctx, "ListAdminDocuments")
+
 	out := &listAdminDocumentsResponse{}
 
 	q := url.Values{}
@@ -87,6 +89,28 @@ func (h *HTTPClient) ListAdminDocuments(ctx context.Context, patientID string, o
 	}, nil
 }
 
+// ListAllAdminDocuments - Get all of a patient's admin documents
+func (h *HTTPClient) ListAllAdminDocuments(ctx context.Context, patientID string, opts *ListAdminDocumentsOptions) ([]*AdminDocument, error) {
+	ctx = withOperation(ctx, "ListAllAdminDocuments")
+
+	o := ListAdminDocumentsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*AdminDocument, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListAdminDocuments(ctx, patientID, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.AdminDocuments, res.Pagination, nil
+	})
+}
+
 type AddDocumentOptions struct {
 	ActionNote         *string
 	AppointmentID      *int
@@ -135,6 +159,8 @@ type addDocumentResponse struct {
 // MEDICALRECORD_PATIENTDIARY
 // MEDICALRECORD_VACCINATION
 func (h *HTTPClient) AddDocument(ctx context.Context, patientID string, opts *AddDocumentOptions) (string, error) {
+	ctx = withOperation(ctx, "AddDocument")
+
 	var form url.Values
 
 	if opts != nil {
@@ -226,6 +252,8 @@ type AddDocumentReaderOptions struct {
 // MEDICALRECORD_PATIENTDIARY
 // MEDICALRECORD_VACCINATION
 func (h *HTTPClient) AddDocumentReader(ctx context.Context, patientID string, opts *AddDocumentReaderOptions) (string, error) {
+	ctx = withOperation(ctx, "AddDocumentReader")
+
 	var form *formURLEncoder
 
 	if opts != nil {
@@ -316,6 +344,8 @@ type AddClinicalDocumentResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-clinical-document#Add-clinical-document-to-patient's-chart
 func (h *HTTPClient) AddClinicalDocument(ctx context.Context, patientID string, opts *AddClinicalDocumentOptions) (*AddClinicalDocumentResponse, error) {
+	ctx = withOperation(ctx, "AddClinicalDocument")
+
 	var form url.Values
 
 	if opts != nil {
@@ -431,6 +461,8 @@ type AddClinicalDocumentReaderOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-clinical-document#Add-clinical-document-to-patient's-chart
 func (h *HTTPClient) AddClinicalDocumentReader(ctx context.Context, patientID string, opts *AddClinicalDocumentReaderOptions) (*AddClinicalDocumentResponse, error) {
+	ctx = withOperation(ctx, "AddClinicalDocumentReader")
+
 	var form *formURLEncoder
 
 	if opts != nil {
@@ -528,6 +560,8 @@ type addPatientCaseDocumentResponse struct {
 // POST /v1/{practiceid}/patients/{patientid}/documents/patientcase
 // https://docs.athenahealth.com/api/api-ref/document-type-patient-case#Add-patient-case-document-for-a-patient
 func (h *HTTPClient) AddPatientCaseDocument(ctx context.Context, patientID string, opts *AddPatientCaseDocumentOptions) (int, error) {
+	ctx = withOperation(ctx, "AddPatientCaseDocument")
+
 	var form url.Values
 
 	if opts != nil {
@@ -598,6 +632,7 @@ type DeleteClinicalDocumentResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-clinical-document#Mark-patient's-clinical-document-as-deleted
 func (h *HTTPClient) DeleteClinicalDocument(ctx context.Context, patientID string, clinicalDocumentID string) (*DeleteClinicalDocumentResponse, error) {
+	ctx = withOperation(ctx, "DeleteClinicalDocument")
 
 	res := &DeleteClinicalDocumentResponse{}
 
@@ -694,6 +729,8 @@ type listEncounterDocumentsResponse struct {
 }
 
 func (h *HTTPClient) ListEncounterDocuments(ctx context.Context, departmentID, patientID string, opts *ListEncounterDocumentsOptions) (*ListEncounterDocumentsResult, error) {
+	ctx = withOperation(ctx, "ListEncounterDocuments")
+
 	out := &listEncounterDocumentsResponse{}
 
 	if departmentID == "" || patientID == "" {
@@ -739,3 +776,25 @@ func (h *HTTPClient) ListEncounterDocuments(ctx context.Context, departmentID, p
 		Pagination:         makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// ListAllEncounterDocuments - Get all of a patient's encounter documents
+func (h *HTTPClient) ListAllEncounterDocuments(ctx context.Context, departmentID, patientID string, opts *ListEncounterDocumentsOptions) ([]*EncounterDocument, error) {
+	ctx = withOperation(ctx, "ListAllEncounterDocuments")
+
+	o := ListEncounterDocumentsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*EncounterDocument, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListEncounterDocuments(ctx, departmentID, patientID, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.EncounterDocuments, res.Pagination, nil
+	})
+}
@@ -147,7 +147,7 @@ func TestHTTPClient_AddClinicalDocument(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.NoError(r.ParseForm())
 
-		assert.Equal(r.URL.Path, "/patients/123/documents/clinicaldocument")
+		assert.Equal(r.URL.Path, "/"+testPracticeID+"/patients/123/documents/clinicaldocument")
 
 		assert.Equal(base64.StdEncoding.EncodeToString([]byte(attachmentContents)), r.FormValue("attachmentcontents"))
 		assert.Equal(autoclose, r.FormValue("autoclose"))
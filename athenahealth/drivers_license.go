@@ -27,6 +27,8 @@ type AddPatientDriversLicenseDocumentResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/drivers-license#Add-patient's-driver's-license-document
 func (h *HTTPClient) AddPatientDriversLicenseDocument(ctx context.Context, patientID string, opts *AddPatientDriversLicenseDocumentOptions) (*AddPatientDriversLicenseDocumentResult, error) {
+	ctx = withOperation(ctx, "AddPatientDriversLicenseDocument")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -63,6 +65,8 @@ type AddPatientDriversLicenseDocumentReaderOptions struct {
 // POST /v1/{practiceid}/patients/{patientid}/driverslicense
 // https://docs.athenahealth.com/api/api-ref/drivers-license#Add-patient's-driver's-license-document
 func (h *HTTPClient) AddPatientDriversLicenseDocumentReader(ctx context.Context, patientID string, opts *AddPatientDriversLicenseDocumentReaderOptions) (*AddPatientDriversLicenseDocumentResult, error) {
+	ctx = withOperation(ctx, "AddPatientDriversLicenseDocumentReader")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -17,6 +17,8 @@ type EncounterSummaryResponse struct {
 
 // https://docs.athenahealth.com/api/api-ref/encounter-chart#Get-encounter-specific-encounter-summary-content
 func (h *HTTPClient) EncounterSummary(ctx context.Context, encounterID string, opts *EncounterSummaryOptions) (*EncounterSummaryResponse, error) {
+	ctx = withOperation(ctx, "EncounterSummary")
+
 	out := &EncounterSummaryResponse{}
 
 	if encounterID == "" {
@@ -0,0 +1,93 @@
+package athenahealth
+
+import (
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokenprovider"
+)
+
+// Environment is a set of athena endpoints, e.g. a regional endpoint, an egress proxy or athenatest.Server.
+type Environment struct {
+	// Name namespaces cached tokens (see NamespacedTokenCacher) so that a token from one environment is not
+	// used in another.
+	Name string
+	// BaseURL is the root of the API, e.g. PreviewBaseURL. The practice ID is appended to it.
+	BaseURL string
+	// AuthURL is the token endpoint used by tokenprovider.Default and tokenprovider.JWT, e.g.
+	// tokenprovider.PreviewAuthURL. If it is empty, the token provider is left alone.
+	AuthURL string
+	// Preview applies the rate and concurrency limits of the preview environment rather than production.
+	Preview bool
+}
+
+var (
+	// PreviewEnvironment is athena's preview environment, used by default.
+	PreviewEnvironment = Environment{
+		Name:    "preview",
+		BaseURL: PreviewBaseURL,
+		AuthURL: tokenprovider.PreviewAuthURL,
+		Preview: true,
+	}
+
+	// ProdEnvironment is athena's production environment.
+	ProdEnvironment = Environment{
+		Name:    "prod",
+		BaseURL: ProdBaseURL,
+		AuthURL: tokenprovider.ProdAuthURL,
+		Preview: false,
+	}
+)
+
+// WithBaseURL sends API requests to baseURL, the root of the API the practice ID is appended to (e.g.
+// "https://athena-proxy.internal/v1/"), instead of the environment's. Tokens are still requested from the
+// environment's auth URL; use WithEnvironment to change both.
+func (h *HTTPClient) WithBaseURL(baseURL string) *HTTPClient {
+	h.environment.BaseURL = baseURL
+	h.customBaseURL = true
+	h.setBaseURL()
+
+	return h
+}
+
+// WithEnvironment sends API requests, and token requests made by tokenprovider.Default or tokenprovider.JWT,
+// to env.
+func (h *HTTPClient) WithEnvironment(env Environment) *HTTPClient {
+	h.customBaseURL = true
+	h.customEnvironment = true
+	h.setEnvironment(env)
+
+	return h
+}
+
+func (h *HTTPClient) setEnvironment(env Environment) {
+	h.environment = env
+	h.setBaseURL()
+	h.setTokenCacherNamespace()
+	h.setTokenProviderEnvironment()
+}
+
+// setTokenProviderEnvironment moves a tokenprovider.Default or tokenprovider.JWT to the auth URL of the
+// environment unless its auth URL was set to something other than athena's. The provider given to
+// WithTokenProvider is copied rather than changed, so it can be shared by clients for different environments.
+func (h *HTTPClient) setTokenProviderEnvironment() {
+	h.tokenProvider = h.baseTokenProvider
+
+	authURL := h.environment.AuthURL
+	if len(authURL) == 0 {
+		return
+	}
+
+	switch p := h.baseTokenProvider.(type) {
+	case *tokenprovider.Default:
+		if p.AuthURL() != authURL && isAthenaAuthURL(p.AuthURL()) {
+			h.tokenProvider = p.ForAuthURL(authURL)
+		}
+
+	case *tokenprovider.JWT:
+		if p.AuthURL() != authURL && isAthenaAuthURL(p.AuthURL()) {
+			h.tokenProvider = p.ForAuthURL(authURL)
+		}
+	}
+}
+
+func isAthenaAuthURL(authURL string) bool {
+	return authURL == tokenprovider.PreviewAuthURL || authURL == tokenprovider.ProdAuthURL
+}
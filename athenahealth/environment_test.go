@@ -0,0 +1,193 @@
+package athenahealth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokenprovider"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEnvironmentServer records the paths requested from it, answering token requests at /oauth2/v1/token.
+type testEnvironmentServer struct {
+	*httptest.Server
+
+	paths  []string
+	scopes []string
+	lock   sync.Mutex
+}
+
+func newTestEnvironmentServer() *testEnvironmentServer {
+	s := &testEnvironmentServer{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		s.paths = append(s.paths, r.URL.Path)
+		s.lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/oauth2/v1/token" {
+			r.ParseForm()
+
+			s.lock.Lock()
+			s.scopes = append(s.scopes, r.PostForm.Get("scope"))
+			s.lock.Unlock()
+
+			w.Write([]byte(`{"access_token": "token", "expires_in": "3600"}`))
+			return
+		}
+
+		w.Write([]byte(`[{"patientid": "1"}]`))
+	}))
+
+	return s
+}
+
+func (s *testEnvironmentServer) environment() Environment {
+	return Environment{
+		Name:    "regional",
+		BaseURL: s.URL + "/v1/",
+		AuthURL: s.URL + "/oauth2/v1/token",
+	}
+}
+
+func (s *testEnvironmentServer) requestedPaths() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.paths
+}
+
+func TestHTTPClient_WithEnvironment(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := newTestEnvironmentServer()
+	defer ts.Close()
+
+	athenaClient := NewHTTPClient(ts.Client(), "195900", "client-id", "secret").
+		WithTokenProvider(tokenprovider.NewDefault(ts.Client(), "client-id", "secret", true).WithScopes("scope")).
+		WithEnvironment(ts.environment())
+
+	assert.False(athenaClient.environment.Preview)
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"/oauth2/v1/token", "/v1/195900/patients/1"}, ts.requestedPaths())
+	assert.Equal([]string{"scope"}, ts.scopes)
+
+	athenaClient.WithTokenCacher(&testNamespacedTokenCacher{})
+
+	assert.Equal("regional", athenaClient.tokenCacher.(*testNamespacedTokenCacher).environment)
+
+	// WithPreview only changes the limits that apply.
+	athenaClient.WithPreview(true)
+
+	assert.True(athenaClient.environment.Preview)
+	assert.Equal("regional", athenaClient.tokenCacher.(*testNamespacedTokenCacher).environment)
+
+	_, err = athenaClient.GetPatient(context.Background(), "2", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"/oauth2/v1/token", "/v1/195900/patients/1", "/v1/195900/patients/2"}, ts.requestedPaths())
+}
+
+func TestHTTPClient_WithEnvironment_token_provider_set_after(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := newTestEnvironmentServer()
+	defer ts.Close()
+
+	athenaClient := NewHTTPClient(ts.Client(), "195900", "client-id", "secret").
+		WithEnvironment(ts.environment()).
+		WithTokenProvider(tokenprovider.NewDefault(ts.Client(), "client-id", "secret", true))
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"/oauth2/v1/token", "/v1/195900/patients/1"}, ts.requestedPaths())
+}
+
+func TestHTTPClient_WithEnvironment_jwt(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	ts := newTestEnvironmentServer()
+	defer ts.Close()
+
+	athenaClient := NewHTTPClient(ts.Client(), "195900", "client-id", "").
+		WithTokenProvider(tokenprovider.NewJWT(ts.Client(), "client-id", key, true)).
+		WithEnvironment(ts.environment())
+
+	_, err = athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+	assert.Equal([]string{"/oauth2/v1/token", "/v1/195900/patients/1"}, ts.requestedPaths())
+}
+
+func TestHTTPClient_WithEnvironment_shared_token_provider(t *testing.T) {
+	assert := assert.New(t)
+
+	tokenProvider := tokenprovider.NewDefault(&http.Client{}, "", "", true)
+
+	previewClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenProvider(tokenProvider)
+
+	NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenProvider(tokenProvider).
+		WithEnvironment(Environment{Name: "regional", AuthURL: "https://auth.internal/token"})
+
+	// The shared provider is copied, not moved, by the client in the other environment.
+	assert.Equal(tokenprovider.PreviewAuthURL, tokenProvider.AuthURL())
+	assert.Same(tokenProvider, previewClient.tokenProvider)
+}
+
+func TestHTTPClient_WithBaseURL(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := newTestEnvironmentServer()
+	defer ts.Close()
+
+	athenaClient := NewHTTPClient(ts.Client(), "195900", "", "").
+		WithTokenProvider(&testTokenProvider{}).
+		WithBaseURL(ts.URL + "/proxy/v1/")
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	athenaClient.WithPreview(false)
+
+	_, err = athenaClient.GetPatient(context.Background(), "2", nil)
+	assert.NoError(err)
+
+	assert.Equal([]string{"/proxy/v1/195900/patients/1", "/proxy/v1/195900/patients/2"}, ts.requestedPaths())
+	assert.Equal(ProdEnvironment.Name, athenaClient.environment.Name)
+}
+
+func TestHTTPClient_WithPreview_token_provider(t *testing.T) {
+	assert := assert.New(t)
+
+	tokenProvider := tokenprovider.NewDefault(&http.Client{}, "", "", true)
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenProvider(tokenProvider).
+		WithPreview(false)
+
+	assert.Equal(tokenprovider.ProdAuthURL, athenaClient.tokenProvider.(*tokenprovider.Default).AuthURL())
+	assert.Equal(tokenprovider.PreviewAuthURL, tokenProvider.AuthURL())
+
+	// A token provider with its own auth URL is left alone.
+	tokenProvider = tokenprovider.NewDefault(&http.Client{}, "", "", true).
+		WithAuthURL("https://auth.internal/token")
+
+	athenaClient.WithTokenProvider(tokenProvider).
+		WithPreview(true)
+
+	assert.Same(tokenProvider, athenaClient.tokenProvider)
+	assert.Equal("https://auth.internal/token", tokenProvider.AuthURL())
+}
@@ -19,7 +19,7 @@ func TestHTTPClient_GetHealthHistoryFormForAppointment(t *testing.T) {
 
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(http.MethodGet, r.Method)
-		assert.Equal(fmt.Sprintf("/appointments/%s/healthhistoryforms/%s", apptID, formID), r.URL.String())
+		assert.Equal(fmt.Sprintf("/%s/appointments/%s/healthhistoryforms/%s", testPracticeID, apptID, formID), r.URL.String())
 
 		b, _ := os.ReadFile("./resources/GetHealthHistoryFormForAppointment.json")
 		w.Write(b)
@@ -51,7 +51,7 @@ func TestHTTPClient_UpdateHealthHistoryFormForAppointment(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		assert.NoError(r.ParseForm())
 		assert.Equal(http.MethodPut, r.Method)
-		assert.Equal(fmt.Sprintf("/appointments/%s/healthhistoryforms/%s", apptID, formID), r.URL.String())
+		assert.Equal(fmt.Sprintf("/%s/appointments/%s/healthhistoryforms/%s", testPracticeID, apptID, formID), r.URL.String())
 		assert.Equal(string(hhfRequestBytes), r.FormValue("healthhistoryform"))
 
 		b, _ := os.ReadFile("./resources/UpdateHealthHistoryFormForAppointmentResponse.json")
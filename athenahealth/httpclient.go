@@ -12,14 +12,21 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/auditor"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/concurrencylimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/redact"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/retrypolicy"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokenprovider"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -45,24 +52,44 @@ type HTTPClient struct {
 	practiceID     string
 	clientID       string
 	secret         string
-	preview        bool
-	baseURL        string
 	requestTimeout time.Duration
 
-	tokenProvider TokenProvider
-	tokenCacher   TokenCacher
-	rateLimiter   RateLimiter
-	stats         Stats
-	logger        *zerolog.Logger
-
-	requestLock sync.Mutex
+	environment Environment
+	baseURL     string
+	// customBaseURL and customEnvironment are set by WithBaseURL and WithEnvironment so that WithPreview
+	// does not overwrite them.
+	customBaseURL     bool
+	customEnvironment bool
+
+	tokenProvider      TokenProvider
+	tokenCacher        TokenCacher
+	rateLimiter        RateLimiter
+	concurrencyLimiter ConcurrencyLimiter
+	retryPolicy        RetryPolicy
+	stats              Stats
+	logger             *zerolog.Logger
+	redactor           Redactor
+	logBodies          bool
+	auditor            Auditor
+
+	// baseTokenProvider is the provider given to WithTokenProvider. tokenProvider is the copy of it moved to
+	// the client's environment if it is a tokenprovider.Default or tokenprovider.JWT.
+	baseTokenProvider TokenProvider
+	// baseTokenCacher is the cacher given to WithTokenCacher. tokenCacher is the copy of it scoped to
+	// the client's environment and client ID if it is a NamespacedTokenCacher.
+	baseTokenCacher TokenCacher
+
+	tracer     Tracer
+	middleware []Middleware
+
+	tokenGroup *singleflight.Group
+
+	tokenRefresher *tokenRefresher
 }
 
 var _ Client = (*HTTPClient)(nil)
 
 func NewHTTPClient(httpClient *http.Client, practiceID, clientID, secret string) *HTTPClient {
-	preview := true
-
 	noplogger := zerolog.Nop()
 
 	c := &HTTPClient{
@@ -71,173 +98,260 @@ func NewHTTPClient(httpClient *http.Client, practiceID, clientID, secret string)
 		practiceID:     practiceID,
 		clientID:       clientID,
 		secret:         secret,
-		preview:        preview,
 		requestTimeout: defaultRequestTimeout,
 
-		tokenProvider: tokenprovider.NewDefault(httpClient, clientID, secret, preview),
-		tokenCacher:   tokencacher.NewDefault(),
-		rateLimiter:   ratelimiter.NewDefault(),
-		stats:         stats.NewDefault(),
-		logger:        &noplogger,
+		environment: PreviewEnvironment,
+
+		baseTokenProvider:  tokenprovider.NewDefault(httpClient, clientID, secret, PreviewEnvironment.Preview),
+		baseTokenCacher:    tokencacher.NewDefault(),
+		rateLimiter:        ratelimiter.NewDefault(),
+		concurrencyLimiter: concurrencylimiter.NewDefault(),
+		retryPolicy:        retrypolicy.NewDefault(),
+		stats:              stats.NewDefault(),
+		logger:             &noplogger,
+		redactor:           redact.New(),
+		auditor:            auditor.NewDefault(),
+		tracer:             noopTracer{},
+
+		tokenGroup:     &singleflight.Group{},
+		tokenRefresher: &tokenRefresher{},
 	}
 
 	c.setBaseURL()
+	c.setTokenCacherNamespace()
+	c.setTokenProviderEnvironment()
 
 	return c
 }
 
 func (h *HTTPClient) setBaseURL() {
-	if h.preview {
-		h.baseURL = fmt.Sprintf("%s%s", PreviewBaseURL, h.practiceID)
-	} else {
-		h.baseURL = fmt.Sprintf("%s%s", ProdBaseURL, h.practiceID)
+	h.baseURL = h.practiceBaseURL(h.practiceID)
+}
+
+func (h *HTTPClient) setTokenCacherNamespace() {
+	h.tokenCacher = h.baseTokenCacher
+
+	if cacher, ok := h.baseTokenCacher.(NamespacedTokenCacher); ok {
+		h.tokenCacher = cacher.WithNamespace(h.environment.Name, h.clientID)
 	}
 }
 
-func (h *HTTPClient) request(ctx context.Context, method, path string, body io.Reader, headers http.Header, out interface{}) (*http.Response, error) {
+func (h *HTTPClient) request(ctx context.Context, method, path string, body io.Reader, headers http.Header, out interface{}) (res *http.Response, err error) {
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, h.requestTimeout)
 		defer cancel()
 	}
 
-	var token string
-	var err error
-	var expiresAt time.Time
-
-	h.requestLock.Lock()
-
 	if !strings.HasPrefix(path, "/") {
 		path = fmt.Sprintf("/%s", path)
 	}
 
-	reqURL := fmt.Sprintf("%s%s", h.baseURL, path)
+	_, baseURL := h.practice(ctx)
+	reqURL := fmt.Sprintf("%s%s", baseURL, path)
 
-	retryAfter, err := h.rateLimiter.Allowed(ctx, h.preview)
-	if err != nil {
-		h.requestLock.Unlock()
+	// Reuse the same X-Request-Id for every attempt so retries can be correlated.
+	xRequestID := uuid.NewString()
 
-		if errors.Is(err, ratelimiter.ErrRateExceeded) {
-			h.logger.Info().
-				Str("method", method).
-				Str("url", reqURL).
-				Err(err).
-				Msg("athenahealth API request rate limited")
+	var reqPatientIDs []string
+	if h.auditing() {
+		// The body is read before it is sent, so the patients it refers to can be audited.
+		reqPatientIDs = formPatientIDs(headers, body)
+	}
 
-			select {
-			case <-ctx.Done():
-				return nil, fmt.Errorf("waiting for rate limit retry interval: %w", ctx.Err())
+	ctx, span := h.startRequestSpan(ctx, method, path, xRequestID)
+	defer func() {
+		h.endRequestSpan(span, res, err)
+		h.audit(ctx, method, path, xRequestID, reqPatientIDs, res, err)
+	}()
 
-			case <-time.After(retryAfter):
-				return h.request(ctx, method, path, body, headers, out)
-			}
+	retryable := h.retryPolicy.Retryable(method)
+
+	// The body is sent again if the request is retried or replayed after a 401.
+	newBody := replayableBodyFunc(body, retryable)
+	replayable := body == nil || newBody != nil
+
+	replayedUnauthorized := false
+	// replays counts attempts that replayed the request after a 401, which do not count
+	// towards the retry policy's attempts.
+	replays := 0
+
+	for attempt := 1; ; attempt++ {
+		attemptBody := body
+		if newBody != nil {
+			attemptBody = newBody()
 		}
 
-		return nil, err
-	}
+		res, err = h.attempt(ctx, method, path, reqURL, attemptBody, headers, xRequestID, attempt, attempt-1-replays, out)
+		if err == nil || ctx.Err() != nil || !replayable {
+			return res, err
+		}
 
-	token, err = h.tokenCacher.Get(ctx)
-	if err != nil {
-		if !errors.Is(err, tokencacher.ErrTokenNotExist) && !errors.Is(err, tokencacher.ErrTokenExpired) {
-			h.requestLock.Unlock()
-			return nil, err
+		// athena may reject a token before its advertised expiration (e.g. at token
+		// rollover). The token is invalidated by authMiddleware; replay the request
+		// once with a freshly provided one.
+		var apiErr *APIError
+		if !replayedUnauthorized && errors.As(err, &apiErr) && res.StatusCode == http.StatusUnauthorized {
+			replayedUnauthorized = true
+			replays++
+
+			h.logger.Info().
+				Str("method", method).
+				Str("url", h.redactor.URL(reqURL)).
+				Str("xRequestId", xRequestID).
+				Int("attempt", attempt).
+				Msg("athenahealth API request unauthorized, refreshing token")
+
+			span.AddEvent("unauthorized", Attribute{attributeAttempt, attempt})
+
+			continue
 		}
 
-		token, expiresAt, err = h.tokenProvider.Provide(ctx)
-		if err != nil {
-			h.requestLock.Unlock()
-			return nil, err
+		if !retryable {
+			return res, err
 		}
 
-		// Remove 1 minute from the expiration time to create a buffer to see
-		// if it resolves intermittent 401s.
-		err = h.tokenCacher.Set(context.Background(), token, expiresAt.Add(-1*time.Minute))
-		if err != nil {
-			h.requestLock.Unlock()
-			return nil, err
+		retryAfter, retry := h.retryPolicy.Retry(attempt-replays, res, err)
+		if !retry {
+			return res, err
 		}
-	}
 
-	h.requestLock.Unlock()
+		h.logger.Info().
+			Str("method", method).
+			Str("url", h.redactor.URL(reqURL)).
+			Str("xRequestId", xRequestID).
+			Int("attempt", attempt).
+			Str("retryAfter", retryAfter.String()).
+			Err(h.redactError(err)).
+			Msg("athenahealth API request retrying")
+
+		span.AddEvent("retry",
+			Attribute{attributeAttempt, attempt},
+			Attribute{attributeRetryAfter, retryAfter.String()},
+		)
+
+		select {
+		case <-ctx.Done():
+			return res, err
 
-	if body != nil {
-		body = newSizeRecordingReader(body)
-	}
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
-	if err != nil {
-		return nil, err
+		case <-time.After(retryAfter):
+		}
 	}
+}
 
-	if headers != nil {
-		req.Header = headers
+// attempt sends a single attempt of a request, closing its body once the attempt is done.
+// retries is the number of earlier attempts retried by the retry policy, which excludes replays after a 401.
+func (h *HTTPClient) attempt(ctx context.Context, method, path, reqURL string, body io.Reader, headers http.Header, xRequestID string, attempt, retries int, out interface{}) (*http.Response, error) {
+	if closer, ok := body.(*replayReader); ok {
+		defer closer.Close()
 	}
 
-	xRequestID := uuid.NewString()
+	return h.do(ctx, method, path, reqURL, body, headers, xRequestID, attempt, retries, out)
+}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-	req.Header.Add("User-Agent", userAgent)
-	req.Header.Set(XRequestIDHeaderKey, xRequestID)
+func (h *HTTPClient) allowed(ctx context.Context, method, path string) (time.Duration, error) {
+	route := pathutil.Normalize(path)
+
+	if rateLimiter, ok := h.rateLimiter.(RouteRateLimiter); ok {
+		practiceID, _ := h.practice(ctx)
 
-	var requestBodyLength int64
-	if srBody, ok := body.(*sizeRecordingReader); ok {
-		requestBodyLength = srBody.size
+		return rateLimiter.AllowedRoute(ctx, h.environment.Preview, practiceID, method, route)
 	}
 
-	h.logger.Info().
-		Str("method", method).
-		Str("url", reqURL).
-		Str("xRequestId", xRequestID).
-		Msg("athenahealth API request")
+	return h.rateLimiter.Allowed(ctx, h.environment.Preview)
+}
 
-	requestStart := time.Now()
+// waitForRateLimit blocks until the rate limiter allows the request and returns how long it waited.
+func (h *HTTPClient) waitForRateLimit(ctx context.Context, method, path, reqURL string) (wait time.Duration, err error) {
+	start := time.Now()
+	checks := 0
 
-	res, err := h.httpClient.Do(req)
-	if err != nil {
-		return res, err
+	// The whole wait is a single span, however many times the rate limiter is checked.
+	ctx, span := h.startRateLimitSpan(ctx, method, pathutil.Normalize(path))
+	defer func() {
+		endRateLimitSpan(span, checks, wait, err)
+	}()
+
+	for {
+		checks++
+
+		retryAfter, err := h.allowed(ctx, method, path)
+		if err == nil {
+			return time.Since(start), nil
+		}
+
+		if errors.Is(err, ratelimiter.ErrQuotaExhausted) {
+			h.logger.Warn().
+				Str("method", method).
+				Str("url", h.redactor.URL(reqURL)).
+				Dur("retryAfter", retryAfter).
+				Err(err).
+				Msg("athenahealth API daily quota exhausted")
+		}
+
+		if !errors.Is(err, ratelimiter.ErrRateExceeded) {
+			return time.Since(start), err
+		}
+
+		h.logger.Info().
+			Str("method", method).
+			Str("url", h.redactor.URL(reqURL)).
+			Err(err).
+			Msg("athenahealth API request rate limited")
+
+		select {
+		case <-ctx.Done():
+			return time.Since(start), fmt.Errorf("waiting for rate limit retry interval: %w", ctx.Err())
+
+		case <-time.After(retryAfter):
+		}
 	}
-	defer res.Body.Close()
+}
 
-	requestDuration := time.Since(requestStart)
+func (h *HTTPClient) do(ctx context.Context, method, path, reqURL string, body io.Reader, headers http.Header, xRequestID string, attempt, retries int, out interface{}) (*http.Response, error) {
+	ctx = withRequestInfo(ctx, &requestInfo{
+		path:    path,
+		attempt: attempt,
+		retries: retries,
+	})
 
-	err = h.stats.Request(method, path)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
-		return res, err
+		return nil, err
 	}
 
-	responseError := res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices
-	if responseError {
-		err = h.stats.ResponseError()
-		if err != nil {
-			return res, err
+	// http.NewRequestWithContext only knows the length of a few types of bodies.
+	if sr, ok := body.(*io.SectionReader); ok {
+		req.ContentLength = sr.Size()
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(io.NewSectionReader(sr, 0, sr.Size())), nil
 		}
-	} else {
-		err = h.stats.ResponseSuccess()
-		if err != nil {
-			return res, err
+
+		if req.ContentLength == 0 {
+			req.Body = http.NoBody
 		}
 	}
 
+	if headers != nil {
+		req.Header = headers.Clone()
+	}
+
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Set(XRequestIDHeaderKey, xRequestID)
+
+	res, err := h.roundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
 	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
 		return res, err
 	}
-	// close original req.Body before before overwriting
-	res.Body.Close()
 
 	res.Body = io.NopCloser(bytes.NewBuffer(resBody))
 
-	h.logger.Info().
-		Str("method", method).
-		Str("url", reqURL).
-		Int("statusCode", res.StatusCode).
-		Int("responseBodyLength", len(resBody)).
-		Int64("requestBodyLength", requestBodyLength).
-		Int64("requestContentLength", req.ContentLength).
-		Str("xRequestId", xRequestID).
-		Str("duration", requestDuration.String()).
-		Msg("athenahealth API response")
-
-	if responseError {
+	if isErrorStatus(res.StatusCode) {
 		err := &APIError{}
 		if res.StatusCode == http.StatusNotFound {
 			err.Err = ErrNotFound
@@ -248,9 +362,9 @@ func (h *HTTPClient) request(ctx context.Context, method, path string, body io.R
 
 		err.HTTPResponse = res
 
+		// athena's detailed messages often repeat the values that were submitted, so they are not logged.
 		h.logger.Info().
 			Str("athenaError", err.AthenaError).
-			Str("athenaDetailedMessage", err.AthenaDetailedMessage).
 			Msg("athenahealth API error")
 
 		return res, err
@@ -266,49 +380,369 @@ func (h *HTTPClient) request(ctx context.Context, method, path string, body io.R
 	return res, nil
 }
 
+// token returns the cached token, fetching a new one from the token provider if
+// it does not exist or has expired. Concurrent fetches are coalesced so that a
+// burst of requests on a cold cache results in a single call to the provider.
+func (h *HTTPClient) token(ctx context.Context) (string, error) {
+	token, err := h.tokenCacher.Get(ctx)
+	if err == nil {
+		return token, nil
+	}
+
+	if !errors.Is(err, tokencacher.ErrTokenNotExist) && !errors.Is(err, tokencacher.ErrTokenExpired) {
+		return "", err
+	}
+
+	fetched, err := h.fetchTokenOnce(ctx, true)
+	if err != nil {
+		return "", err
+	}
+
+	return fetched.token, nil
+}
+
+// fetchedToken is the result of a coalesced token fetch. expiresAt is zero if the token was found in
+// the cache instead of being fetched.
+type fetchedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// fetchTokenOnce fetches a new token with fetchToken, coalescing concurrent fetches by requests and the
+// token refresher. If checkCache is set, a token cached by another caller in the meantime is returned
+// instead.
+func (h *HTTPClient) fetchTokenOnce(ctx context.Context, checkCache bool) (*fetchedToken, error) {
+	// The fetch is shared by every waiting caller, so it must not be cancelled
+	// when the caller that happened to start it gives up.
+	fetchCtx := context.WithoutCancel(ctx)
+
+	ch := h.tokenGroup.DoChan("token", func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(fetchCtx, h.requestTimeout)
+		defer cancel()
+
+		if checkCache {
+			// Another caller may have cached a new token between our Get and
+			// joining the group.
+			token, err := h.tokenCacher.Get(fetchCtx)
+			if err == nil {
+				return &fetchedToken{token: token}, nil
+			}
+		}
+
+		token, expiresAt, err := h.fetchToken(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &fetchedToken{token: token, expiresAt: expiresAt}, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for token: %w", ctx.Err())
+
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		return res.Val.(*fetchedToken), nil
+	}
+}
+
+// invalidateToken deletes a token athena rejected from the cache, unless another request has
+// already replaced it with a new one.
+func (h *HTTPClient) invalidateToken(ctx context.Context, token string) error {
+	cached, err := h.tokenCacher.Get(ctx)
+	if err != nil || cached != token {
+		return nil
+	}
+
+	return h.tokenCacher.Delete(ctx)
+}
+
+// fetchToken gets a new token from the token provider and caches it. It returns
+// the token and the time at which the cached token expires.
+func (h *HTTPClient) fetchToken(ctx context.Context) (string, time.Time, error) {
+	ctx, span := h.tracer.Start(ctx, "athenahealth.token", SpanKindInternal)
+	defer span.End()
+
+	token, expiresAt, err := h.tokenProvider.Provide(ctx)
+	if err != nil {
+		span.RecordError(err)
+		h.unlockTokenCacher(ctx)
+
+		return "", time.Time{}, err
+	}
+
+	// Remove 1 minute from the expiration time so the token is refreshed
+	// before athena starts rejecting it. Any 401s that still slip through
+	// are handled by invalidating the token and replaying the request.
+	expiresAt = expiresAt.Add(-1 * time.Minute)
+
+	err = h.tokenCacher.Set(ctx, token, expiresAt)
+	if err != nil {
+		h.unlockTokenCacher(ctx)
+
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// unlockTokenCacher lets other processes waiting for a token fetch it themselves.
+func (h *HTTPClient) unlockTokenCacher(ctx context.Context) {
+	tokenCacher, ok := h.tokenCacher.(LockingTokenCacher)
+	if !ok {
+		return
+	}
+
+	// The fetch may have failed because ctx is done.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.requestTimeout)
+	defer cancel()
+
+	err := tokenCacher.Unlock(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("athenahealth token cache unlock failed")
+	}
+}
+
 type sizeRecordingReader struct {
-	r    io.Reader
-	size int64
+	r io.ReadCloser
+	// size is read after the response is received, which may be before the transport has stopped reading the body.
+	size atomic.Int64
+	// capture, if not nil, records the bytes read.
+	capture io.Writer
 }
 
-func newSizeRecordingReader(r io.Reader) *sizeRecordingReader {
+func newSizeRecordingReader(r io.ReadCloser, capture io.Writer) *sizeRecordingReader {
 	return &sizeRecordingReader{
-		r:    r,
-		size: 0,
+		r:       r,
+		capture: capture,
 	}
 }
 
 func (srr *sizeRecordingReader) Read(p []byte) (int, error) {
 	n, err := srr.r.Read(p)
-	srr.size += int64(n)
+	srr.size.Add(int64(n))
+	if srr.capture != nil {
+		//nolint
+		srr.capture.Write(p[:n])
+	}
+	return n, err
+}
+
+func (srr *sizeRecordingReader) Close() error {
+	return srr.r.Close()
+}
+
+// sizedReaderAt is implemented by bytes.Reader and strings.Reader, which can be read again
+// from any offset without buffering them.
+type sizedReaderAt interface {
+	io.ReaderAt
+	Len() int
+	Size() int64
+}
+
+// replayableBodyFunc returns a function that returns the body of each attempt of a request, or nil if
+// the body can only be sent once. Bodies that can be read again from the start, such as forms, are not
+// buffered. Other bodies, such as streamed uploads, are only recorded as they are sent if record is set.
+func replayableBodyFunc(body io.Reader, record bool) func() io.Reader {
+	switch body := body.(type) {
+	case nil:
+		return nil
+
+	case sizedReaderAt:
+		offset := body.Size() - int64(body.Len())
+		length := int64(body.Len())
+
+		return func() io.Reader {
+			return io.NewSectionReader(body, offset, length)
+		}
+
+	case *bytes.Buffer:
+		b := body.Bytes()
+
+		return func() io.Reader {
+			return bytes.NewReader(b)
+		}
+	}
+
+	if !record {
+		return nil
+	}
+
+	rb := newReplayableBody(body)
+
+	return func() io.Reader {
+		return rb.newReader()
+	}
+}
+
+// replayableBody records the bytes read from a request body so they can be sent
+// again if the request is retried. Readers returned by newReader replay the
+// recorded bytes before continuing to read from the underlying body, so
+// streaming bodies (e.g. PostFormReader pipes) are only buffered as far as
+// they have actually been sent.
+type replayableBody struct {
+	r   io.Reader
+	buf []byte
+	err error
+
+	lock sync.Mutex
+}
+
+func newReplayableBody(r io.Reader) *replayableBody {
+	return &replayableBody{
+		r: r,
+	}
+}
+
+func (rb *replayableBody) newReader() *replayReader {
+	return &replayReader{
+		body: rb,
+	}
+}
+
+type replayReader struct {
+	body   *replayableBody
+	pos    int
+	closed atomic.Bool
+}
+
+func (rr *replayReader) Read(p []byte) (int, error) {
+	if rr.closed.Load() {
+		return 0, io.ErrClosedPipe
+	}
+
+	rb := rr.body
+
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	if rr.pos < len(rb.buf) {
+		n := copy(p, rb.buf[rr.pos:])
+		rr.pos += n
+
+		return n, nil
+	}
+
+	if rb.err != nil {
+		return 0, rb.err
+	}
+
+	n, err := rb.r.Read(p)
+	rb.buf = append(rb.buf, p[:n]...)
+	rb.err = err
+	rr.pos += n
+
 	return n, err
 }
 
+// Close detaches the reader from the underlying body so a transport that is still
+// reading a previous attempt's body cannot consume bytes meant for the next one.
+func (rr *replayReader) Close() error {
+	rr.closed.Store(true)
+
+	return nil
+}
+
 func (h *HTTPClient) WithLogger(logger *zerolog.Logger) *HTTPClient {
 	h.logger = logger
 
 	return h
 }
 
+// WithRedactor sets how PHI is removed from the URLs and bodies that are logged. It defaults to redact.New().
+func (h *HTTPClient) WithRedactor(redactor Redactor) *HTTPClient {
+	h.redactor = redactor
+
+	return h
+}
+
+// redactError removes PHI from the URL of a *url.Error in err's chain, which carries the query string
+// of the request, and from the detailed message of an *APIError, which often repeats submitted values,
+// e.g. before err is logged or traced.
+func (h *HTTPClient) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		msg = strings.ReplaceAll(msg, urlErr.URL, h.redactor.URL(urlErr.URL))
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && len(apiErr.AthenaDetailedMessage) > 0 {
+		msg = strings.ReplaceAll(msg, apiErr.AthenaDetailedMessage, redact.Redacted)
+	}
+
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{
+		msg: msg,
+		err: err,
+	}
+}
+
+// redactedError is an error whose message has had PHI removed.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// WithBodyLogging logs request and response bodies, redacted by the Redactor, at debug level.
+func (h *HTTPClient) WithBodyLogging(enabled bool) *HTTPClient {
+	h.logBodies = enabled
+
+	return h
+}
+
+// WithPreview switches between athena's preview and production environments. A base URL or environment
+// set by WithBaseURL or WithEnvironment is kept; only the rate and concurrency limits that apply change.
 func (h *HTTPClient) WithPreview(preview bool) *HTTPClient {
-	h.preview = preview
-	h.setBaseURL()
+	env := ProdEnvironment
+	if preview {
+		env = PreviewEnvironment
+	}
 
-	if _, ok := h.tokenProvider.(*tokenprovider.Default); ok {
-		h.tokenProvider = tokenprovider.NewDefault(h.httpClient, h.clientID, h.secret, preview)
+	if h.customEnvironment {
+		env = h.environment
+		env.Preview = preview
+	} else if h.customBaseURL {
+		env.BaseURL = h.environment.BaseURL
 	}
 
+	h.setEnvironment(env)
+
 	return h
 }
 
+// WithTokenProvider sets the provider of tokens. A tokenprovider.Default or tokenprovider.JWT requests tokens
+// from the auth URL of the client's environment, whether it is set before or after WithEnvironment.
 func (h *HTTPClient) WithTokenProvider(provider TokenProvider) *HTTPClient {
-	h.tokenProvider = provider
+	h.baseTokenProvider = provider
+	h.setTokenProviderEnvironment()
 
 	return h
 }
 
 func (h *HTTPClient) WithTokenCacher(cacher TokenCacher) *HTTPClient {
-	h.tokenCacher = cacher
+	h.baseTokenCacher = cacher
+	h.setTokenCacherNamespace()
 
 	return h
 }
@@ -319,6 +753,20 @@ func (h *HTTPClient) WithRateLimiter(rateLimiter RateLimiter) *HTTPClient {
 	return h
 }
 
+// WithConcurrencyLimiter caps the number of requests in flight at once, e.g. with concurrencylimiter.NewLocal
+// for a single process or concurrencylimiter.NewRedis across a fleet. A slot is held for each attempt.
+func (h *HTTPClient) WithConcurrencyLimiter(concurrencyLimiter ConcurrencyLimiter) *HTTPClient {
+	h.concurrencyLimiter = concurrencyLimiter
+
+	return h
+}
+
+func (h *HTTPClient) WithRetryPolicy(retryPolicy RetryPolicy) *HTTPClient {
+	h.retryPolicy = retryPolicy
+
+	return h
+}
+
 func (h *HTTPClient) WithStats(stats Stats) *HTTPClient {
 	h.stats = stats
 
@@ -361,6 +809,8 @@ func (h *HTTPClient) PostFormReader(ctx context.Context, path string, fue *formU
 
 	if fue != nil {
 		pr, pw := io.Pipe()
+		// Unblock the encoder if the request ends without reading the whole body.
+		defer pr.Close()
 
 		go func() {
 			err := fue.Encode(ctx, pw)
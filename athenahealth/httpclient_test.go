@@ -13,10 +13,18 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/concurrencylimiter"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/retrypolicy"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,17 +49,21 @@ func testClient(h http.HandlerFunc) (*HTTPClient, *httptest.Server) {
 
 	athenaClient := NewHTTPClient(ts.Client(), testPracticeID, testAPIKey, testAPISecret).
 		WithTokenProvider(&testTokenProvider{}).
-		WithTokenCacher(&testTokenCacher{})
-
-	athenaClient.baseURL = ts.URL
+		WithTokenCacher(&testTokenCacher{}).
+		WithBaseURL(ts.URL + "/")
 
 	return athenaClient, ts
 }
 
 type testTokenProvider struct {
+	ProvideFunc func() (string, time.Time, error)
 }
 
 func (t *testTokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	if t.ProvideFunc != nil {
+		return t.ProvideFunc()
+	}
+
 	return testToken, time.Now().Add(time.Minute * 1), nil
 }
 
@@ -66,6 +78,10 @@ func (t *testTokenCacher) Set(context.Context, string, time.Time) error {
 	return nil
 }
 
+func (t *testTokenCacher) Delete(context.Context) error {
+	return nil
+}
+
 type testRateLimiter struct {
 	AllowedFunc func(preview bool) (time.Duration, error)
 }
@@ -78,10 +94,22 @@ func (t *testRateLimiter) Allowed(ctx context.Context, preview bool) (time.Durat
 	return 0, nil
 }
 
+type testRouteRateLimiter struct {
+	testRateLimiter
+
+	AllowedRouteFunc func(preview bool, practiceID, method, path string) (time.Duration, error)
+}
+
+func (t *testRouteRateLimiter) AllowedRoute(ctx context.Context, preview bool, practiceID, method, path string) (time.Duration, error) {
+	return t.AllowedRouteFunc(preview, practiceID, method, path)
+}
+
 type testStats struct {
-	RequestFunc         func(method, path string) error
-	ResponseSuccessFunc func() error
-	ResponseErrorFunc   func() error
+	RequestFunc             func(method, path string) error
+	ResponseSuccessFunc     func() error
+	ResponseErrorFunc       func() error
+	TokenRefreshSuccessFunc func() error
+	TokenRefreshErrorFunc   func() error
 }
 
 func (t *testStats) Request(method, path string) error {
@@ -108,6 +136,22 @@ func (t *testStats) ResponseError() error {
 	return nil
 }
 
+func (t *testStats) TokenRefreshSuccess() error {
+	if t.TokenRefreshSuccessFunc != nil {
+		return t.TokenRefreshSuccessFunc()
+	}
+
+	return nil
+}
+
+func (t *testStats) TokenRefreshError() error {
+	if t.TokenRefreshErrorFunc != nil {
+		return t.TokenRefreshErrorFunc()
+	}
+
+	return nil
+}
+
 func TestNewHTTPClient(t *testing.T) {
 	assert := assert.New(t)
 
@@ -122,7 +166,7 @@ func TestNewHTTPClient(t *testing.T) {
 	assert.Equal(key, athenaClient.clientID)
 
 	// Preview mode should default to true.
-	assert.True(athenaClient.preview)
+	assert.True(athenaClient.environment.Preview)
 
 	assert.NotNil(athenaClient.tokenProvider)
 	assert.NotNil(athenaClient.tokenCacher)
@@ -157,8 +201,7 @@ func TestAPIError_setBaseURL(t *testing.T) {
 	assert.Equal(expectedBaseURL, athenaClient.baseURL)
 
 	// Production base URL
-	athenaClient.preview = false
-	athenaClient.setBaseURL()
+	athenaClient.WithPreview(false)
 	expectedBaseURL = fmt.Sprintf("%s%s", ProdBaseURL, practiceID)
 	assert.Equal(expectedBaseURL, athenaClient.baseURL)
 }
@@ -280,6 +323,658 @@ func TestHTTPClient_rate_limit(t *testing.T) {
 	assert.True(called)
 }
 
+func TestHTTPClient_rate_limit_route(t *testing.T) {
+	assert := assert.New(t)
+
+	var practiceID, method, path string
+
+	rateLimiter := &testRouteRateLimiter{}
+	rateLimiter.AllowedFunc = func(preview bool) (time.Duration, error) {
+		assert.Fail("Allowed should not be called")
+		return 0, nil
+	}
+	rateLimiter.AllowedRouteFunc = func(preview bool, p, m, pth string) (time.Duration, error) {
+		practiceID, method, path = p, m, pth
+		return 0, nil
+	}
+
+	athenaClient, ts := testClient(nil)
+	athenaClient.WithRateLimiter(rateLimiter)
+
+	defer ts.Close()
+
+	var out map[string]string
+	_, err := athenaClient.request(context.Background(), "GET", "/patients/123/documents?limit=10", nil, nil, &out)
+
+	assert.NoError(err)
+	assert.Equal(athenaClient.practiceID, practiceID)
+	assert.Equal("GET", method)
+	assert.Equal("/patients/:id:/documents", path)
+}
+
+func TestHTTPClient_rate_limit_quota_exhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := &testRouteRateLimiter{}
+	rateLimiter.AllowedRouteFunc = func(preview bool, practiceID, method, path string) (time.Duration, error) {
+		return time.Hour, ratelimiter.ErrQuotaExhausted
+	}
+
+	called := false
+	h := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithRateLimiter(rateLimiter)
+
+	defer ts.Close()
+
+	start := time.Now()
+
+	_, err := athenaClient.request(context.Background(), "GET", "/patients", nil, nil, nil)
+
+	assert.ErrorIs(err, ratelimiter.ErrQuotaExhausted)
+	assert.Less(time.Since(start), time.Second)
+	assert.False(called)
+}
+
+func TestHTTPClient_request_retry(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	var xRequestIDs []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		xRequestIDs = append(xRequestIDs, r.Header.Get(XRequestIDHeaderKey))
+
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"msg":"Hello World!"}`))
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond))
+
+	var out map[string]string
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, &out)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal("Hello World!", out["msg"])
+	assert.Equal(3, calls)
+	assert.Len(xRequestIDs, 3)
+	assert.NotEmpty(xRequestIDs[0])
+	assert.Equal(xRequestIDs[0], xRequestIDs[1])
+	assert.Equal(xRequestIDs[0], xRequestIDs[2])
+}
+
+func TestHTTPClient_request_retry_exhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond))
+
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.IsType(&APIError{}, err)
+	assert.Equal(http.StatusBadGateway, res.StatusCode)
+	assert.Equal(3, calls)
+}
+
+func TestHTTPClient_request_retry_connection_reset(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if calls == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.NoError(err)
+			conn.Close()
+
+			return
+		}
+
+		w.Write([]byte(`{}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond))
+
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal(2, calls)
+}
+
+func TestHTTPClient_request_retry_method_not_retryable(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond))
+
+	res, err := athenaClient.Put(context.Background(), "/", strings.NewReader("foo"), nil)
+
+	assert.NotNil(res)
+	assert.Error(err)
+	assert.Equal(1, calls)
+}
+
+func TestHTTPClient_request_retry_replays_body(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	var bodies []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		// Fail the first attempt before reading the body.
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+
+		if calls == 2 {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond).WithMethods(http.MethodPut))
+
+	var values = url.Values{}
+	values.Add("foo", "bar")
+
+	res, err := athenaClient.PutForm(context.Background(), "/", values, nil)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal(3, calls)
+	assert.Equal([]string{"foo=bar", "foo=bar"}, bodies)
+}
+
+func TestHTTPClient_request_retry_replays_PostFormReader_body(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		r.ParseForm()
+		fileBytes, err := base64.StdEncoding.DecodeString(r.Form.Get("file"))
+		assert.NoError(err)
+		assert.Equal(athenaTestImgBytes, fileBytes)
+		assert.Equal("bar", r.Form.Get("foo"))
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond).WithMethods(http.MethodPost))
+
+	fue := NewFormURLEncoder()
+	fue.AddString("foo", "bar")
+	fue.AddReader("file", bytes.NewReader(athenaTestImgBytes))
+
+	res, err := athenaClient.PostFormReader(context.Background(), "/", fue, nil)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal(2, calls)
+}
+
+func TestHTTPClient_request_unauthorized_refreshes_token(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	var bodies []string
+	var xRequestIDs []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		xRequestIDs = append(xRequestIDs, r.Header.Get(XRequestIDHeaderKey))
+
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Invalid Token"}`))
+			return
+		}
+
+		w.Write([]byte(`{"msg":"Hello World!"}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var provided int
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			provided++
+
+			return fmt.Sprintf("token-%d", provided), time.Now().Add(time.Hour), nil
+		},
+	})
+
+	var out map[string]string
+	res, err := athenaClient.Post(context.Background(), "/", strings.NewReader("foo"), &out)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal("Hello World!", out["msg"])
+	assert.Equal(2, calls)
+	assert.Equal(2, provided)
+	assert.Equal([]string{"foo", "foo"}, bodies)
+	assert.Equal(xRequestIDs[0], xRequestIDs[1])
+}
+
+func TestHTTPClient_request_unauthorized_replays_once(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var provided int
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			provided++
+
+			return testToken, time.Now().Add(time.Hour), nil
+		},
+	})
+
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.IsType(&APIError{}, err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(2, calls)
+	assert.Equal(2, provided)
+}
+
+func TestHTTPClient_request_unauthorized_stale_token(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := tokencacher.NewDefault()
+
+	var authorizations []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			// Another request refreshed the token while this one was in flight.
+			cacher.Set(r.Context(), "token-2", time.Now().Add(time.Hour))
+
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var provided int
+	athenaClient.WithTokenCacher(cacher)
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			provided++
+
+			return fmt.Sprintf("token-%d", provided), time.Now().Add(time.Hour), nil
+		},
+	})
+
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal([]string{"Bearer token-1", "Bearer token-2"}, authorizations)
+	assert.Equal(1, provided)
+
+	token, err := cacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("token-2", token)
+}
+
+func TestHTTPClient_request_unauthorized_not_retry_attempt(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusUnauthorized)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(2, time.Millisecond, time.Millisecond))
+
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal(3, calls)
+}
+
+func TestHTTPClient_request_concurrent_token_fetch(t *testing.T) {
+	assert := assert.New(t)
+
+	var tokenCalls atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls.Add(1)
+
+		// Hold the token request open long enough for every goroutine to pile up behind it.
+		time.Sleep(50 * time.Millisecond)
+
+		w.Write([]byte(testToken))
+	}))
+	defer tokenServer.Close()
+
+	var requests atomic.Int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		assert.Equal(fmt.Sprintf("Bearer %s", testToken), r.Header.Get("Authorization"))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			res, err := tokenServer.Client().Get(tokenServer.URL)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			defer res.Body.Close()
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+
+			return string(b), time.Now().Add(time.Hour), nil
+		},
+	})
+
+	var wg sync.WaitGroup
+
+	for range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := athenaClient.Get(context.Background(), "/", nil, nil)
+			assert.NoError(err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(int32(1), tokenCalls.Load())
+	assert.Equal(int32(200), requests.Load())
+}
+
+func TestHTTPClient_request_redis_lock_cold_cache(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	tokenCacher := tokencacher.NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "").WithLock(time.Second*10, time.Second*5)
+	athenaClient.WithTokenCacher(tokenCacher)
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			return testToken, time.Now().Add(time.Hour), nil
+		},
+	})
+
+	// The process taking the lock fetches the token without waiting on its own lock.
+	start := time.Now()
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Less(time.Since(start), time.Second)
+
+	token, err := athenaClient.tokenCacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal(testToken, token)
+}
+
+func TestHTTPClient_request_redis_lock_provider_error(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	failingClient, ts := testClient(nil)
+	defer ts.Close()
+
+	failingClient.WithTokenCacher(tokencacher.NewRedis(redisClient, "").WithLock(time.Second*10, time.Second*5))
+	failingClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			return "", time.Time{}, errors.New("provider unavailable")
+		},
+	})
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	athenaClient.WithTokenCacher(tokencacher.NewRedis(redisClient, "").WithLock(time.Second*10, time.Second*5))
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			return testToken, time.Now().Add(time.Hour), nil
+		},
+	})
+
+	_, err = failingClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+	assert.Error(err)
+
+	// The failed fetch released the lock, so the other process does not wait for its lease.
+	start := time.Now()
+	_, err = athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NoError(err)
+	assert.Less(time.Since(start), time.Second)
+}
+
+func TestHTTPClient_request_token_fetch_caller_cancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	release := make(chan struct{})
+	var provided atomic.Int32
+
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+	athenaClient.WithTokenProvider(&testTokenProvider{
+		ProvideFunc: func() (string, time.Time, error) {
+			provided.Add(1)
+			<-release
+
+			return testToken, time.Now().Add(time.Hour), nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errCh := make(chan error)
+	go func() {
+		_, err := athenaClient.Get(ctx, "/", nil, nil)
+		errCh <- err
+	}()
+
+	// Wait for the first caller to start the token fetch before cancelling it.
+	for provided.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	resCh := make(chan error)
+	go func() {
+		_, err := athenaClient.Get(context.Background(), "/", nil, nil)
+		resCh <- err
+	}()
+
+	cancel()
+	assert.ErrorIs(<-errCh, context.Canceled)
+
+	close(release)
+	assert.NoError(<-resCh)
+	assert.Equal(int32(1), provided.Load())
+}
+
+// testClock is a fake clock shared by a test server and a rate limiter.
+type testClock struct {
+	now  time.Time
+	lock sync.Mutex
+}
+
+func (c *testClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// testClockRateLimiter advances the clock instead of making the client wait for the rate limiter.
+type testClockRateLimiter struct {
+	*ratelimiter.Adaptive
+
+	clock *testClock
+}
+
+func (t *testClockRateLimiter) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	for {
+		retryAfter, err := t.Adaptive.Allowed(ctx, preview)
+		if !errors.Is(err, ratelimiter.ErrRateExceeded) {
+			return retryAfter, err
+		}
+
+		t.clock.Advance(retryAfter)
+	}
+}
+
+func TestHTTPClient_adaptive_rate_limit(t *testing.T) {
+	assert := assert.New(t)
+
+	clock := &testClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Throttle requests that arrive less than 20ms after the previous one (i.e. above 50/s).
+	var lastRequest time.Time
+	var throttledCount int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		now := clock.Now()
+		defer func() {
+			lastRequest = now
+		}()
+
+		if now.Sub(lastRequest) < 20*time.Millisecond {
+			throttledCount++
+
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	rateLimiter := ratelimiter.NewAdaptive(400, 400).WithClock(clock.Now)
+	athenaClient.WithRateLimiter(&testClockRateLimiter{
+		Adaptive: rateLimiter,
+		clock:    clock,
+	})
+
+	var failures []int
+	for i := range 40 {
+		_, err := athenaClient.Get(context.Background(), "/", nil, nil)
+		if err != nil {
+			failures = append(failures, i)
+		}
+	}
+
+	// The limiter halves its rate on each 429 until it is below the server's threshold.
+	assert.Equal([]int{1, 2, 3, 4}, failures)
+	assert.Equal(len(failures), throttledCount)
+	assert.LessOrEqual(rateLimiter.Rate(true), float64(55))
+}
+
 func TestHTTPClient_WithPreview(t *testing.T) {
 	assert := assert.New(t)
 
@@ -287,7 +982,7 @@ func TestHTTPClient_WithPreview(t *testing.T) {
 
 	athenaClient.WithPreview(false)
 
-	assert.False(athenaClient.preview)
+	assert.False(athenaClient.environment.Preview)
 }
 
 func TestHTTPClient_WithTokenProvider(t *testing.T) {
@@ -312,6 +1007,44 @@ func TestHTTPClient_WithTokenCacher(t *testing.T) {
 	assert.Equal(tokenCacher, athenaClient.tokenCacher)
 }
 
+type testNamespacedTokenCacher struct {
+	testTokenCacher
+
+	environment string
+	clientID    string
+}
+
+func (t *testNamespacedTokenCacher) WithNamespace(environment, clientID string) TokenCacher {
+	return &testNamespacedTokenCacher{
+		environment: environment,
+		clientID:    clientID,
+	}
+}
+
+func TestHTTPClient_WithTokenCacher_namespace(t *testing.T) {
+	assert := assert.New(t)
+
+	tokenCacher := &testNamespacedTokenCacher{}
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "client-id", "").
+		WithTokenCacher(tokenCacher)
+
+	assert.Equal(&testNamespacedTokenCacher{environment: "preview", clientID: "client-id"}, athenaClient.tokenCacher)
+
+	// The cacher can be shared by clients for other environments.
+	prodClient := NewHTTPClient(&http.Client{}, "", "client-id", "").
+		WithPreview(false).
+		WithTokenCacher(tokenCacher)
+
+	assert.Equal(&testNamespacedTokenCacher{environment: "prod", clientID: "client-id"}, prodClient.tokenCacher)
+	assert.Equal(&testNamespacedTokenCacher{environment: "preview", clientID: "client-id"}, athenaClient.tokenCacher)
+	assert.Empty(tokenCacher.environment)
+
+	athenaClient.WithPreview(false)
+
+	assert.Equal(&testNamespacedTokenCacher{environment: "prod", clientID: "client-id"}, athenaClient.tokenCacher)
+}
+
 func TestHTTPClient_WithRateLimiter(t *testing.T) {
 	assert := assert.New(t)
 
@@ -323,6 +1056,228 @@ func TestHTTPClient_WithRateLimiter(t *testing.T) {
 	assert.Equal(rateLimiter, athenaClient.rateLimiter)
 }
 
+func TestHTTPClient_WithConcurrencyLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "")
+
+	concurrencyLimiter := concurrencylimiter.NewLocal(1, 1)
+	athenaClient.WithConcurrencyLimiter(concurrencyLimiter)
+
+	assert.Equal(concurrencyLimiter, athenaClient.concurrencyLimiter)
+}
+
+func TestHTTPClient_concurrency_limit(t *testing.T) {
+	assert := assert.New(t)
+
+	var inFlight, maxInFlight atomic.Int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithConcurrencyLimiter(concurrencylimiter.NewLocal(2, 2))
+
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := athenaClient.request(context.Background(), "GET", "/", nil, nil, nil)
+			assert.NoError(err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(int32(2), maxInFlight.Load())
+}
+
+func TestHTTPClient_concurrency_limit_PostFormReader_released(t *testing.T) {
+	assert := assert.New(t)
+
+	// Respond without reading the streamed body.
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithConcurrencyLimiter(concurrencylimiter.NewLocal(1, 1))
+
+	defer ts.Close()
+
+	fue := NewFormURLEncoder()
+
+	// A body that never finishes streaming.
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write(athenaTestImgBytes)
+
+	fue.AddReader("file", pr)
+
+	_, err := athenaClient.PostFormReader(context.Background(), "/", fue, nil)
+	assert.Error(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = athenaClient.request(ctx, "GET", "/", nil, nil, nil)
+	assert.False(errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestHTTPClient_concurrency_limit_deadline(t *testing.T) {
+	assert := assert.New(t)
+
+	concurrencyLimiter := concurrencylimiter.NewLocal(1, 1)
+
+	athenaClient, ts := testClient(nil)
+	athenaClient.WithConcurrencyLimiter(concurrencyLimiter)
+
+	defer ts.Close()
+
+	release, err := concurrencyLimiter.Acquire(context.Background(), athenaClient.environment.Preview)
+	assert.NoError(err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = athenaClient.request(ctx, "GET", "/", nil, nil, nil)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
+
+type testResponseStats struct {
+	testStats
+
+	responses []*stats.Response
+	lock      sync.Mutex
+}
+
+func (t *testResponseStats) Response(res *stats.Response) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.responses = append(t.responses, res)
+
+	return nil
+}
+
+func TestHTTPClient_ResponseStats(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		io.ReadAll(r.Body)
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`{"foo":"bar"}`))
+	}
+
+	rateLimited := false
+	rateLimiter := &testRateLimiter{}
+	rateLimiter.AllowedFunc = func(preview bool) (time.Duration, error) {
+		if rateLimited {
+			return 0, nil
+		}
+
+		rateLimited = true
+
+		return 20 * time.Millisecond, ratelimiter.ErrRateExceeded
+	}
+
+	responseStats := &testResponseStats{}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithStats(responseStats).
+		WithRateLimiter(rateLimiter).
+		WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond).WithMethods(http.MethodPost))
+
+	defer ts.Close()
+
+	_, err := athenaClient.PostForm(context.Background(), "/patients/123/documents", url.Values{"foo": {"bar"}}, nil)
+	assert.NoError(err)
+
+	assert.Len(responseStats.responses, 2)
+
+	first := responseStats.responses[0]
+	assert.Equal(http.MethodPost, first.Method)
+	assert.Equal("/patients/:id:/documents", first.Path)
+	assert.Equal(http.StatusServiceUnavailable, first.StatusCode)
+	assert.Equal(int64(len("foo=bar")), first.RequestBytes)
+	assert.Zero(first.Retries)
+	assert.GreaterOrEqual(first.RateLimitWait, 20*time.Millisecond)
+	assert.NotZero(first.Duration)
+
+	second := responseStats.responses[1]
+	assert.Equal(http.StatusOK, second.StatusCode)
+	assert.Equal(int64(len(`{"foo":"bar"}`)), second.ResponseBytes)
+	assert.Equal(1, second.Retries)
+	assert.Less(second.RateLimitWait, 20*time.Millisecond)
+}
+
+func TestHTTPClient_ResponseStats_unauthorized_not_retry(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"foo":"bar"}`))
+	}
+
+	responseStats := &testResponseStats{}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	athenaClient.WithStats(responseStats)
+
+	_, err := athenaClient.Get(context.Background(), "/patients/123", nil, nil)
+	assert.NoError(err)
+
+	// Replaying the request with a new token is not a retry.
+	if assert.Len(responseStats.responses, 2) {
+		assert.Equal(http.StatusUnauthorized, responseStats.responses[0].StatusCode)
+		assert.Zero(responseStats.responses[1].Retries)
+	}
+}
+
+func TestHTTPClient_WithRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "")
+
+	retryPolicy := retrypolicy.NewExponential(0, 0, 0)
+	athenaClient.WithRetryPolicy(retryPolicy)
+
+	assert.Equal(retryPolicy, athenaClient.retryPolicy)
+}
+
 func TestHTTPClient_WithStats(t *testing.T) {
 	assert := assert.New(t)
 
@@ -404,6 +1359,8 @@ func TestHTTPClient_PostForm(t *testing.T) {
 		assert.True(len(b) > 0)
 
 		assert.Equal("application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.Equal(int64(len("foo=bar")), r.ContentLength)
+		assert.Empty(r.TransferEncoding)
 
 		called = true
 	}
@@ -554,8 +1511,6 @@ func TestHTTPClient_Delete(t *testing.T) {
 	athenaClient, ts := testClient(h)
 	defer ts.Close()
 
-	athenaClient.baseURL = ts.URL
-
 	res, err := athenaClient.Delete(context.Background(), "/", strings.NewReader("foo"), nil)
 
 	assert.NotNil(res)
@@ -56,6 +56,8 @@ type InsurancePackage struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient-insurance#Create-patient's-insurance-package
 func (h *HTTPClient) CreatePatientInsurancePackage(ctx context.Context, opts *CreatePatientInsurancePackageOptions) (*InsurancePackage, error) {
+	ctx = withOperation(ctx, "CreatePatientInsurancePackage")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -88,6 +90,8 @@ func (h *HTTPClient) CreatePatientInsurancePackage(ctx context.Context, opts *Cr
 // POST /v1/{practiceid}/patients/{patientid}/insurances/{insuranceid}/reactivate
 // https://docs.athenahealth.com/api/api-ref/patient-insurance#Reactivate-patient's-specific-insurance-package
 func (h *HTTPClient) ReactivatePatientInsurancePackage(ctx context.Context, patientID, insuranceID string, expirationDate *time.Time) error {
+	ctx = withOperation(ctx, "ReactivatePatientInsurancePackage")
+
 	out := &MessageResponse{}
 
 	form := url.Values{}
@@ -127,6 +131,8 @@ type UpdatePatientInsurancePackageOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient-insurance#Update-patient's-specific-insurance-package
 func (h *HTTPClient) UpdatePatientInsurancePackage(ctx context.Context, opts *UpdatePatientInsurancePackageOptions) error {
+	ctx = withOperation(ctx, "UpdatePatientInsurancePackage")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -179,6 +185,8 @@ func (h *HTTPClient) UpdatePatientInsurancePackage(ctx context.Context, opts *Up
 // DELETE /v1/{practiceid}/patients/{patientid}/insurances/{insuranceid}
 // https://docs.athenahealth.com/api/api-ref/patient-insurance#Delete-patient's-specific-insurance-package
 func (h *HTTPClient) DeletePatientInsurancePackage(ctx context.Context, patientID, insuranceID, cancellationNote string) error {
+	ctx = withOperation(ctx, "DeletePatientInsurancePackage")
+
 	out := &MessageResponse{}
 
 	form := url.Values{}
@@ -222,6 +230,8 @@ type ListPatientInsurancePackagesResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient-insurance#Get-patient's-insurance-packages
 func (h *HTTPClient) ListPatientInsurancePackages(ctx context.Context, opts *ListPatientInsurancePackagesOptions) (*ListPatientInsurancePackagesResult, error) {
+	ctx = withOperation(ctx, "ListPatientInsurancePackages")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -255,6 +265,28 @@ func (h *HTTPClient) ListPatientInsurancePackages(ctx context.Context, opts *Lis
 	}, nil
 }
 
+// ListAllPatientInsurancePackages - Get all of a patient's insurance packages
+func (h *HTTPClient) ListAllPatientInsurancePackages(ctx context.Context, opts *ListPatientInsurancePackagesOptions) ([]*InsurancePackage, error) {
+	ctx = withOperation(ctx, "ListAllPatientInsurancePackages")
+
+	o := ListPatientInsurancePackagesOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*InsurancePackage, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListPatientInsurancePackages(ctx, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.InsurancePackages, res.Pagination, nil
+	})
+}
+
 type UploadPatientInsuranceCardImageOptions struct {
 	DepartmentID string
 	Image        []byte
@@ -274,6 +306,8 @@ type UploadPatientInsuranceCardImageResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/insurance-card-image#Upload-patient's-insurance-card-image
 func (h *HTTPClient) UploadPatientInsuranceCardImage(ctx context.Context, patientID, insuranceID string, opts *UploadPatientInsuranceCardImageOptions) (*UploadPatientInsuranceCardImageResult, error) {
+	ctx = withOperation(ctx, "UploadPatientInsuranceCardImage")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -309,6 +343,8 @@ type UploadPatientInsuranceCardImageReaderOptions struct {
 // POST /v1/{practiceid}/patients/{patientid}/insurances/{insuranceid}/image
 // https://docs.athenahealth.com/api/api-ref/insurance-card-image#Upload-patient's-insurance-card-image
 func (h *HTTPClient) UploadPatientInsuranceCardImageReader(ctx context.Context, patientID, insuranceID string, opts *UploadPatientInsuranceCardImageReaderOptions) (*UploadPatientInsuranceCardImageResult, error) {
+	ctx = withOperation(ctx, "UploadPatientInsuranceCardImageReader")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -349,6 +385,8 @@ type GetPatientInsuranceCardImageResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/insurance-card-image#Get-patient's-insurance-card-image
 func (h *HTTPClient) GetPatientInsuranceCardImage(ctx context.Context, patientID, insuranceID string) (*GetPatientInsuranceCardImageResult, error) {
+	ctx = withOperation(ctx, "GetPatientInsuranceCardImage")
+
 	out := &getPatientInsuranceCardImageResponse{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/patients/%s/insurances/%s/image", patientID, insuranceID), nil, &out)
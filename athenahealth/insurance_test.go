@@ -126,7 +126,7 @@ func TestHTTPClient_ReactivatePatientInsurancePackage(t *testing.T) {
 	expDate := time.Date(2022, time.January, 20, 0, 0, 0, 0, time.UTC)
 
 	h := func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal("/patients/1/insurances/2/reactivate", r.URL.Path)
+		assert.Equal("/"+testPracticeID+"/patients/1/insurances/2/reactivate", r.URL.Path)
 		assert.Equal(http.MethodPost, r.Method)
 
 		assert.NoError(r.ParseForm())
@@ -210,7 +210,7 @@ func TestHTTPClient_GetPatientInsurancePackage(t *testing.T) {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		assert.Equal(http.MethodGet, r.Method)
-		assert.Equal("/patients/1/insurances/2/image", r.URL.String())
+		assert.Equal("/"+testPracticeID+"/patients/1/insurances/2/image", r.URL.String())
 
 		b, _ := os.ReadFile("./resources/GetPatientInsuranceCardImage.json")
 		w.Write(b)
@@ -0,0 +1,331 @@
+// Command mockgen generates athenahealthmock.Client from the Client interface in client.go.
+//
+// It is run by go generate in the athenahealthmock package:
+//
+//	go run ../internal/mockgen -in ../client.go -out client.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	interfaceName = "Client"
+	// pkgName is the package declaring the interface, which qualifies its identifiers in the mock.
+	pkgName = "athenahealth"
+	pkgPath = "github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+func main() {
+	in := flag.String("in", "", "path to client.go")
+	out := flag.String("out", "", "path to write the mock to")
+	flag.Parse()
+
+	if len(*in) == 0 || len(*out) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := generate(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*out, b, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// method is a method of the interface with its parameters and results formatted as Go source.
+type method struct {
+	name       string
+	params     []string
+	paramTypes []string
+	results    []string
+}
+
+func (m *method) signature() string {
+	params := make([]string, len(m.params))
+	for i := range m.params {
+		params[i] = m.params[i] + " " + m.paramTypes[i]
+	}
+
+	results := strings.Join(m.results, ", ")
+	if len(m.results) > 1 {
+		results = "(" + results + ")"
+	}
+
+	return "(" + strings.Join(params, ", ") + ") " + results
+}
+
+// generate returns the formatted source of athenahealthmock.Client for the Client interface in src.
+func generate(src []byte) ([]byte, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "client.go", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		imports[name] = path
+	}
+
+	iface, err := findInterface(f)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		imports: imports,
+		used:    make(map[string]bool),
+	}
+
+	var methods []*method
+
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 {
+			return nil, fmt.Errorf("%s: embedded interfaces are not supported", interfaceName)
+		}
+
+		m, err := g.method(field.Names[0].Name, funcType)
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, m)
+	}
+
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "// Code generated by mockgen from client.go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package athenahealthmock\n\n")
+
+	paths := []string{"sync"}
+	for name := range g.used {
+		if path := g.imports[name]; path != "sync" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(buf, "import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(buf, "%q\n", path)
+	}
+	fmt.Fprintf(buf, "\n%q\n", pkgPath)
+	fmt.Fprintf(buf, ")\n\n")
+
+	fmt.Fprintf(buf, "var _ %s.%s = (*Client)(nil)\n\n", pkgName, interfaceName)
+
+	fmt.Fprintf(buf, "// Client is a mock %s.%s. Each method calls the function field of the same name with a Func suffix,\n", pkgName, interfaceName)
+	fmt.Fprintf(buf, "// and panics if it is nil. Calls are recorded and returned by Calls.\n")
+	fmt.Fprintf(buf, "type Client struct {\n")
+	for _, m := range methods {
+		fmt.Fprintf(buf, "%sFunc func%s\n", m.name, m.signature())
+	}
+	fmt.Fprintf(buf, "\ncalls []*Call\n")
+	fmt.Fprintf(buf, "lock  sync.Mutex\n")
+	fmt.Fprintf(buf, "}\n")
+
+	for _, m := range methods {
+		args := strings.Join(m.params, ", ")
+
+		callArgs := args
+		if n := len(m.paramTypes); n > 0 && strings.HasPrefix(m.paramTypes[n-1], "...") {
+			callArgs += "..."
+		}
+
+		fmt.Fprintf(buf, "\nfunc (c *Client) %s%s {\n", m.name, m.signature())
+		fmt.Fprintf(buf, "c.record(%q", m.name)
+		if len(args) > 0 {
+			fmt.Fprintf(buf, ", %s", args)
+		}
+		fmt.Fprintf(buf, ")\n\n")
+		fmt.Fprintf(buf, "if c.%sFunc == nil {\n", m.name)
+		fmt.Fprintf(buf, "panic(%q)\n", fmt.Sprintf("athenahealthmock: Client.%s called but %sFunc is not set", m.name, m.name))
+		fmt.Fprintf(buf, "}\n\n")
+
+		if len(m.results) > 0 {
+			fmt.Fprintf(buf, "return ")
+		}
+		fmt.Fprintf(buf, "c.%sFunc(%s)\n", m.name, callArgs)
+		fmt.Fprintf(buf, "}\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func findInterface(f *ast.File) (*ast.InterfaceType, error) {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Name.Name != interfaceName {
+				continue
+			}
+
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				return nil, fmt.Errorf("%s is not an interface", interfaceName)
+			}
+
+			return iface, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s interface not found", interfaceName)
+}
+
+type generator struct {
+	// imports maps the names of the packages imported by client.go to their paths.
+	imports map[string]string
+	// used are the names of the packages used by the mock.
+	used map[string]bool
+}
+
+func (g *generator) method(name string, funcType *ast.FuncType) (*method, error) {
+	m := &method{
+		name: name,
+	}
+
+	params := funcType.Params.List
+	for i, param := range params {
+		typ, err := g.expr(param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if len(param.Names) == 0 {
+			m.params = append(m.params, paramName(typ, i, len(params)))
+			m.paramTypes = append(m.paramTypes, typ)
+
+			continue
+		}
+
+		for _, paramName := range param.Names {
+			m.params = append(m.params, paramName.Name)
+			m.paramTypes = append(m.paramTypes, typ)
+		}
+	}
+
+	if funcType.Results != nil {
+		for _, result := range funcType.Results.List {
+			typ, err := g.expr(result.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			n := max(len(result.Names), 1)
+			for i := 0; i < n; i++ {
+				m.results = append(m.results, typ)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// paramName names an unnamed parameter.
+func paramName(typ string, i, n int) string {
+	if typ == "context.Context" {
+		return "ctx"
+	}
+
+	if i == n-1 {
+		return "opts"
+	}
+
+	return fmt.Sprintf("arg%d", i)
+}
+
+// expr formats a type, qualifying identifiers declared in the interface's package.
+func (g *generator) expr(expr ast.Expr) (string, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(expr.Name) {
+			return pkgName + "." + expr.Name, nil
+		}
+
+		return expr.Name, nil
+
+	case *ast.SelectorExpr:
+		pkg, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return "", fmt.Errorf("unsupported selector %T", expr.X)
+		}
+
+		if _, ok := g.imports[pkg.Name]; !ok {
+			return "", fmt.Errorf("unknown package %s", pkg.Name)
+		}
+
+		g.used[pkg.Name] = true
+
+		return pkg.Name + "." + expr.Sel.Name, nil
+
+	case *ast.StarExpr:
+		x, err := g.expr(expr.X)
+
+		return "*" + x, err
+
+	case *ast.ArrayType:
+		elt, err := g.expr(expr.Elt)
+		if err != nil || expr.Len != nil {
+			return "", fmt.Errorf("unsupported array type")
+		}
+
+		return "[]" + elt, nil
+
+	case *ast.MapType:
+		key, err := g.expr(expr.Key)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := g.expr(expr.Value)
+
+		return "map[" + key + "]" + value, err
+
+	case *ast.Ellipsis:
+		elt, err := g.expr(expr.Elt)
+
+		return "..." + elt, err
+
+	case *ast.InterfaceType:
+		if len(expr.Methods.List) > 0 {
+			return "", fmt.Errorf("unsupported interface type")
+		}
+
+		return "interface{}", nil
+	}
+
+	return "", fmt.Errorf("unsupported type %T", expr)
+}
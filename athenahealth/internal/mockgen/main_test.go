@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	assert := assert.New(t)
+
+	src, err := os.ReadFile("../../client.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := os.ReadFile("../../athenahealthmock/client.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := generate(src)
+	assert.NoError(err)
+	assert.Equal(string(b), string(generated), "athenahealthmock is out of date, run go generate ./athenahealth/athenahealthmock")
+}
+
+func TestGenerate_variadic(t *testing.T) {
+	assert := assert.New(t)
+
+	src := []byte(`package athenahealth
+
+import "context"
+
+type Client interface {
+	Search(context.Context, string, ...*Option) ([]*Result, error)
+}
+`)
+
+	b, err := generate(src)
+	assert.NoError(err)
+	assert.Contains(string(b), "SearchFunc func(ctx context.Context, arg1 string, opts ...*athenahealth.Option) ([]*athenahealth.Result, error)")
+	assert.Contains(string(b), "return c.SearchFunc(ctx, arg1, opts...)")
+}
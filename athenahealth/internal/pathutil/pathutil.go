@@ -0,0 +1,25 @@
+package pathutil
+
+import (
+	"net/url"
+	"regexp"
+)
+
+var idRegex = regexp.MustCompile(`(/)(\d+)(/?)`)
+
+// customFieldValueRegex matches the custom field value in /patients/customfields/{customfieldid}/{customfieldvalue}.
+var customFieldValueRegex = regexp.MustCompile(`(/patients/customfields/[^/]+/)[^/]+`)
+
+// Normalize strips the query string from path and replaces numeric IDs with :id: and custom field values
+// with :value:, so that requests to the same endpoint share a single name, e.g. /patients/123 becomes
+// /patients/:id:.
+func Normalize(path string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return ""
+	}
+
+	p := customFieldValueRegex.ReplaceAllString(u.EscapedPath(), "$1:value:")
+
+	return idRegex.ReplaceAllString(p, "$1:id:$3")
+}
@@ -58,6 +58,8 @@ type ListLabResultsResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/lab-result#Get-patient's-lab-results
 func (h *HTTPClient) ListLabResults(ctx context.Context, patientID string, departmentID string, opts *ListLabResultsOptions) (*ListLabResultsResult, error) {
+	ctx = withOperation(ctx, "ListLabResults")
+
 	var requiredParamErrors []error
 	if len(patientID) == 0 {
 		requiredParamErrors = append(requiredParamErrors, errors.New("patientID is required"))
@@ -116,6 +118,28 @@ func (h *HTTPClient) ListLabResults(ctx context.Context, patientID string, depar
 	}, nil
 }
 
+// ListAllLabResults - Get all of a patient's lab results
+func (h *HTTPClient) ListAllLabResults(ctx context.Context, patientID string, departmentID string, opts *ListLabResultsOptions) ([]*LabResult, error) {
+	ctx = withOperation(ctx, "ListAllLabResults")
+
+	o := ListLabResultsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*LabResult, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListLabResults(ctx, patientID, departmentID, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.LabResults, res.Pagination, nil
+	})
+}
+
 type LabResultAttachmentType string
 
 const (
@@ -172,6 +196,8 @@ type addLabResultDocumentResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-lab-result#Add-lab-result-document-to-patient's-chart
 func (h *HTTPClient) AddLabResultDocumentReader(ctx context.Context, patientID string, departmentID string, opts *AddLabResultDocumentOptions) (int, error) {
+	ctx = withOperation(ctx, "AddLabResultDocumentReader")
+
 	var requiredParamErrors []error
 	if len(patientID) == 0 {
 		requiredParamErrors = append(requiredParamErrors, errors.New("patientID is required"))
@@ -306,6 +332,8 @@ type ListChangedLabResultsResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-lab-result#Get-list-of-changes-in-lab-results-based-on-subscription
 func (h *HTTPClient) ListChangedLabResults(ctx context.Context, opts *ListChangedLabResultsOptions) (*ListChangedLabResultsResult, error) {
+	ctx = withOperation(ctx, "ListChangedLabResults")
+
 	q := url.Values{}
 
 	if opts != nil {
@@ -0,0 +1,337 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/rs/zerolog"
+)
+
+// RoundTrip sends a request to athena. The body of the returned response has already been read into
+// memory; middleware that reads it should replace it with a new reader over the same bytes.
+type RoundTrip func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTrip, e.g. to inspect or modify requests and responses, or to fail requests
+// without sending them.
+type Middleware func(next RoundTrip) RoundTrip
+
+// WithMiddleware adds middleware that is run on every attempt of every request. Middleware added first
+// runs first. Middleware runs after the client has waited for the concurrency and rate limiters and
+// added the Authorization header, so it sees requests exactly as they are sent.
+func (h *HTTPClient) WithMiddleware(middleware ...Middleware) *HTTPClient {
+	h.middleware = append(h.middleware, middleware...)
+
+	return h
+}
+
+type requestInfoContextKey struct{}
+
+// requestInfo describes an attempt of a request as it passes through the middleware chain.
+type requestInfo struct {
+	// path is the path of the request relative to the practice, e.g. /patients/123.
+	path    string
+	attempt int
+	// retries is the number of earlier attempts retried by the retry policy. Unlike attempt, it does
+	// not count replays with a new token after a 401.
+	retries int
+
+	rateLimitWait time.Duration
+	duration      time.Duration
+	requestBytes  int64
+
+	// requestBody, if not nil, records the start of the request body for logging.
+	requestBody *cappedBuffer
+}
+
+// maxLoggedBodyBytes is the most of a request body recorded for logging.
+const maxLoggedBodyBytes = 64 * 1024
+
+// cappedBuffer records up to max bytes written to it and discards the rest.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+
+	lock sync.Mutex
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	remaining := c.max - c.buf.Len()
+	if len(p) > remaining {
+		c.truncated = true
+		c.buf.Write(p[:remaining])
+	} else {
+		c.buf.Write(p)
+	}
+
+	return len(p), nil
+}
+
+// Bytes returns the recorded bytes, or nil if the body was truncated.
+func (c *cappedBuffer) Bytes() []byte {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.truncated {
+		return nil
+	}
+
+	return bytes.Clone(c.buf.Bytes())
+}
+
+func withRequestInfo(ctx context.Context, info *requestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) *requestInfo {
+	info, ok := ctx.Value(requestInfoContextKey{}).(*requestInfo)
+	if !ok {
+		return &requestInfo{}
+	}
+
+	return info
+}
+
+// roundTrip sends req through the built-in middleware, then the middleware added with WithMiddleware.
+func (h *HTTPClient) roundTrip(req *http.Request) (*http.Response, error) {
+	middleware := append([]Middleware{
+		h.concurrencyMiddleware,
+		h.rateLimitMiddleware,
+		h.authMiddleware,
+		h.loggingMiddleware,
+		h.statsMiddleware,
+		h.rateLimitObserverMiddleware,
+	}, h.middleware...)
+
+	next := RoundTrip(h.send)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+
+	return next(req)
+}
+
+// send sends req with the underlying http.Client and reads the response body into memory.
+func (h *HTTPClient) send(req *http.Request) (*http.Response, error) {
+	info := requestInfoFromContext(req.Context())
+
+	var body *sizeRecordingReader
+	if req.Body != nil && req.Body != http.NoBody {
+		var capture io.Writer
+		if info.requestBody != nil {
+			capture = info.requestBody
+		}
+
+		body = newSizeRecordingReader(req.Body, capture)
+		req.Body = body
+	}
+
+	requestStart := time.Now()
+
+	res, err := h.httpClient.Do(req)
+
+	info.duration = time.Since(requestStart)
+	if body != nil {
+		info.requestBytes = body.size.Load()
+	}
+
+	if err != nil {
+		return res, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	res.ContentLength = int64(len(resBody))
+
+	return res, nil
+}
+
+// concurrencyMiddleware holds a slot from the concurrency limiter while the request is in flight.
+func (h *HTTPClient) concurrencyMiddleware(next RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		release, err := h.concurrencyLimiter.Acquire(req.Context(), h.environment.Preview)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return next(req)
+	}
+}
+
+// rateLimitMiddleware waits until the rate limiter allows the request.
+func (h *HTTPClient) rateLimitMiddleware(next RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		info := requestInfoFromContext(req.Context())
+
+		rateLimitWait, err := h.waitForRateLimit(req.Context(), req.Method, info.path, req.URL.String())
+		info.rateLimitWait = rateLimitWait
+		if err != nil {
+			return nil, err
+		}
+
+		return next(req)
+	}
+}
+
+// authMiddleware adds the Authorization header with a cached or newly fetched token.
+func (h *HTTPClient) authMiddleware(next RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		token, err := h.token(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+		res, err := next(req)
+		if res != nil && res.StatusCode == http.StatusUnauthorized {
+			invalidateErr := h.invalidateToken(req.Context(), token)
+			if invalidateErr != nil {
+				return res, invalidateErr
+			}
+		}
+
+		return res, err
+	}
+}
+
+func (h *HTTPClient) loggingMiddleware(next RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		info := requestInfoFromContext(req.Context())
+		xRequestID := req.Header.Get(XRequestIDHeaderKey)
+
+		logBodies := h.logBodies && h.logger.GetLevel() <= zerolog.DebugLevel && zerolog.GlobalLevel() <= zerolog.DebugLevel
+		if logBodies {
+			info.requestBody = &cappedBuffer{max: maxLoggedBodyBytes}
+		}
+
+		h.logger.Info().
+			Str("method", req.Method).
+			Str("url", h.redactor.URL(req.URL.String())).
+			Str("xRequestId", xRequestID).
+			Int("attempt", info.attempt).
+			Msg("athenahealth API request")
+
+		res, err := next(req)
+		if err != nil {
+			return res, err
+		}
+
+		h.logger.Info().
+			Str("method", req.Method).
+			Str("url", h.redactor.URL(req.URL.String())).
+			Int("statusCode", res.StatusCode).
+			Int64("responseBodyLength", res.ContentLength).
+			Int64("requestBodyLength", info.requestBytes).
+			Int64("requestContentLength", req.ContentLength).
+			Str("xRequestId", xRequestID).
+			Int("attempt", info.attempt).
+			Str("duration", info.duration.String()).
+			Msg("athenahealth API response")
+
+		if logBodies {
+			h.logRedactedBodies(req, res, info)
+		}
+
+		return res, nil
+	}
+}
+
+func (h *HTTPClient) logRedactedBodies(req *http.Request, res *http.Response, info *requestInfo) {
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	requestBody := "[truncated]"
+	if b := info.requestBody.Bytes(); b != nil {
+		requestBody = h.redactor.Body(req.Header.Get("Content-Type"), b)
+	}
+
+	h.logger.Debug().
+		Str("method", req.Method).
+		Str("url", h.redactor.URL(req.URL.String())).
+		Str("xRequestId", req.Header.Get(XRequestIDHeaderKey)).
+		Int("attempt", info.attempt).
+		Str("requestBody", requestBody).
+		Str("responseBody", h.redactor.Body(res.Header.Get("Content-Type"), resBody)).
+		Msg("athenahealth API bodies")
+}
+
+func (h *HTTPClient) statsMiddleware(next RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		info := requestInfoFromContext(req.Context())
+
+		res, err := next(req)
+		if err != nil {
+			return res, err
+		}
+
+		err = h.stats.Request(req.Method, info.path)
+		if err != nil {
+			return res, err
+		}
+
+		if isErrorStatus(res.StatusCode) {
+			err = h.stats.ResponseError()
+		} else {
+			err = h.stats.ResponseSuccess()
+		}
+		if err != nil {
+			return res, err
+		}
+
+		if responseStats, ok := h.stats.(ResponseStats); ok {
+			err = responseStats.Response(&stats.Response{
+				Method:        req.Method,
+				Path:          pathutil.Normalize(info.path),
+				StatusCode:    res.StatusCode,
+				Duration:      info.duration,
+				RequestBytes:  info.requestBytes,
+				ResponseBytes: res.ContentLength,
+				Retries:       info.retries,
+				RateLimitWait: info.rateLimitWait,
+			})
+			if err != nil {
+				return res, err
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// rateLimitObserverMiddleware reports responses to rate limiters that adapt to them.
+func (h *HTTPClient) rateLimitObserverMiddleware(next RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		res, err := next(req)
+
+		if observer, ok := h.rateLimiter.(RateLimitObserver); ok && err == nil {
+			observer.ObserveResponse(h.environment.Preview, res)
+		}
+
+		return res, err
+	}
+}
+
+func isErrorStatus(statusCode int) bool {
+	return statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices
+}
@@ -0,0 +1,127 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/retrypolicy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient_WithMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("bar", r.Header.Get("X-Foo"))
+
+		w.Write([]byte(`{"foo":"bar"}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var order []string
+
+	athenaClient.WithMiddleware(func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "first")
+
+			// The request is fully built.
+			assert.Equal("Bearer "+testToken, req.Header.Get("Authorization"))
+			assert.Equal(userAgent, req.Header.Get("User-Agent"))
+			assert.NotEmpty(req.Header.Get(XRequestIDHeaderKey))
+			assert.Equal(http.MethodGet, req.Method)
+
+			req.Header.Set("X-Foo", "bar")
+
+			res, err := next(req)
+
+			// The response body is buffered.
+			b, readErr := io.ReadAll(res.Body)
+			assert.NoError(readErr)
+			assert.Equal(`{"foo":"bar"}`, string(b))
+			assert.Equal(int64(len(b)), res.ContentLength)
+
+			res.Body = io.NopCloser(bytes.NewReader(b))
+
+			return res, err
+		}
+	}, func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "second")
+
+			return next(req)
+		}
+	})
+
+	var out map[string]string
+	_, err := athenaClient.Get(context.Background(), "/patients", nil, &out)
+
+	assert.NoError(err)
+	assert.Equal(map[string]string{"foo": "bar"}, out)
+	assert.Equal([]string{"first", "second"}, order)
+}
+
+func TestHTTPClient_WithMiddleware_short_circuit(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond))
+
+	defer ts.Close()
+
+	// Fail the first attempt with a connection reset without sending it.
+	attempts := 0
+	athenaClient.WithMiddleware(func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, syscall.ECONNRESET
+			}
+
+			return next(req)
+		}
+	})
+
+	_, err := athenaClient.Get(context.Background(), "/", nil, nil)
+
+	assert.NoError(err)
+	assert.Equal(2, attempts)
+	assert.Equal(1, calls)
+}
+
+func TestHTTPClient_WithMiddleware_error(t *testing.T) {
+	assert := assert.New(t)
+
+	called := false
+	h := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	errBlocked := errors.New("blocked")
+
+	athenaClient.WithMiddleware(func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, errBlocked
+		}
+	})
+
+	_, err := athenaClient.Get(context.Background(), "/", nil, nil)
+
+	assert.ErrorIs(err, errBlocked)
+	assert.False(called)
+}
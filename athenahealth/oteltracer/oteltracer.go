@@ -0,0 +1,100 @@
+// Package oteltracer traces athenahealth client requests with OpenTelemetry.
+//
+//	client := oteltracer.WithTracerProvider(athenahealth.NewHTTPClient(&http.Client{}, practiceID, key, secret), otel.GetTracerProvider())
+//
+// The athenahealth package does not import OpenTelemetry, so that programs not tracing with it do not link
+// it; WithTracerProvider lives here rather than as an HTTPClient method for that reason.
+package oteltracer
+
+import (
+	"context"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/eleanorhealth/go-athenahealth/athenahealth"
+
+var _ athenahealth.Tracer = (*Tracer)(nil)
+
+// Tracer creates OpenTelemetry spans. Request spans are client spans, parented by the span in the
+// request's context.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// WithTracerProvider traces the requests of client with tracerProvider. It is shorthand for
+// client.WithTracer(New(tracerProvider)).
+func WithTracerProvider(client *athenahealth.HTTPClient, tracerProvider trace.TracerProvider) *athenahealth.HTTPClient {
+	return client.WithTracer(New(tracerProvider))
+}
+
+func New(tracerProvider trace.TracerProvider) *Tracer {
+	if tracerProvider == nil {
+		panic("tracerProvider is nil")
+	}
+
+	return &Tracer{
+		tracer: tracerProvider.Tracer(tracerName),
+	}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, kind athenahealth.SpanKind, attrs ...athenahealth.Attribute) (context.Context, athenahealth.Span) {
+	spanKind := trace.SpanKindInternal
+	if kind == athenahealth.SpanKindClient {
+		spanKind = trace.SpanKindClient
+	}
+
+	ctx, s := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(spanKind),
+		trace.WithAttributes(attributes(attrs)...),
+	)
+
+	return ctx, &span{
+		span: s,
+	}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s *span) AddEvent(name string, attrs ...athenahealth.Attribute) {
+	s.span.AddEvent(name, trace.WithAttributes(attributes(attrs)...))
+}
+
+func (s *span) SetAttributes(attrs ...athenahealth.Attribute) {
+	s.span.SetAttributes(attributes(attrs)...)
+}
+
+func (s *span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *span) End() {
+	s.span.End()
+}
+
+func attributes(attrs []athenahealth.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+
+	for _, attr := range attrs {
+		key := attribute.Key(attr.Key)
+
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, key.String(v))
+		case []string:
+			kvs = append(kvs, key.StringSlice(v))
+		case bool:
+			kvs = append(kvs, key.Bool(v))
+		case int:
+			kvs = append(kvs, key.Int(v))
+		}
+	}
+
+	return kvs
+}
@@ -0,0 +1,105 @@
+package oteltracer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/oteltracer"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testTokenProvider struct{}
+
+func (t *testTokenProvider) Provide(context.Context) (string, time.Time, error) {
+	return "token", time.Now().Add(time.Hour), nil
+}
+
+func findSpan(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+
+	return nil
+}
+
+func TestTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"The patient is not found."}`))
+	}))
+	defer ts.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client := athenahealth.NewHTTPClient(&http.Client{}, "1", "", "").
+		WithBaseURL(ts.URL).
+		WithTokenProvider(&testTokenProvider{}).
+		WithTracer(oteltracer.New(tracerProvider))
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+
+	_, err := client.GetPatient(ctx, "1", nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	parent.End()
+
+	spans := exporter.GetSpans()
+
+	requestSpan := findSpan(spans, "GET /patients/:id:")
+	if !assert.NotNil(requestSpan) {
+		return
+	}
+
+	assert.Equal(trace.SpanKindClient, requestSpan.SpanKind)
+	assert.Equal(parent.SpanContext().SpanID(), requestSpan.Parent.SpanID())
+	assert.Equal(codes.Error, requestSpan.Status.Code)
+	assert.Contains(requestSpan.Attributes, attribute.String("http.request.method", "GET"))
+	assert.Contains(requestSpan.Attributes, attribute.Int("http.response.status_code", http.StatusNotFound))
+	assert.Contains(requestSpan.Attributes, attribute.String("athenahealth.practice_id", "1"))
+	assert.Contains(requestSpan.Attributes, attribute.Bool("athenahealth.preview", true))
+
+	tokenSpan := findSpan(spans, "athenahealth.token")
+	if !assert.NotNil(tokenSpan) {
+		return
+	}
+
+	assert.Equal(trace.SpanKindInternal, tokenSpan.SpanKind)
+	assert.Equal(requestSpan.SpanContext.SpanID(), tokenSpan.Parent.SpanID())
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"patientid":"1"}]`))
+	}))
+	defer ts.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client := oteltracer.WithTracerProvider(athenahealth.NewHTTPClient(&http.Client{}, "1", "", "").
+		WithBaseURL(ts.URL).
+		WithTokenProvider(&testTokenProvider{}), tracerProvider)
+
+	_, err := client.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	assert.NotNil(findSpan(exporter.GetSpans(), "GET /patients/:id:"))
+}
@@ -0,0 +1,134 @@
+package athenahealth
+
+import (
+	"context"
+)
+
+// PageFunc fetches the page of results described by pagination, e.g. by calling a List method with it.
+type PageFunc[T any] func(ctx context.Context, pagination *PaginationOptions) ([]T, *PaginationResult, error)
+
+type PaginateOptions struct {
+	// PageSize is the number of results requested in each page. athena's default is used if it is 0.
+	PageSize int
+	// Offset is the offset of the first page.
+	Offset int
+	// Prefetch fetches the next page while the results of the current page are yielded. The next page is
+	// requested even if iteration stops early, so only set it if every result is likely to be used.
+	Prefetch bool
+}
+
+// Paginate returns an iterator over the results of every page fetched by fetch, in the shape of
+// iter.Seq2[T, error]. Iteration stops after the last page, when there is no next page, or after the first
+// error, which is yielded with the zero value of T. Unless opts.Prefetch is set, no page is fetched after
+// iteration is stopped early.
+func Paginate[T any](ctx context.Context, fetch PageFunc[T], opts *PaginateOptions) func(yield func(T, error) bool) {
+	if opts == nil {
+		opts = &PaginateOptions{}
+	}
+
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		offset := opts.Offset
+		next := fetchPage(ctx, fetch, opts.PageSize, offset)
+
+		for {
+			p := <-next
+			if p.err != nil {
+				var zero T
+				yield(zero, p.err)
+
+				return
+			}
+
+			// athena's next offset is always past the current page, so anything else is the last page.
+			hasNext := p.pagination != nil && p.pagination.NextOffset > offset && len(p.results) > 0
+			if hasNext {
+				offset = p.pagination.NextOffset
+
+				if opts.Prefetch {
+					next = fetchPage(ctx, fetch, opts.PageSize, offset)
+				}
+			}
+
+			for _, result := range p.results {
+				if !yield(result, nil) {
+					return
+				}
+			}
+
+			if !hasNext {
+				return
+			}
+
+			if ctx.Err() != nil {
+				var zero T
+				yield(zero, ctx.Err())
+
+				return
+			}
+
+			if !opts.Prefetch {
+				next = fetchPage(ctx, fetch, opts.PageSize, offset)
+			}
+		}
+	}
+}
+
+type page[T any] struct {
+	results    []T
+	pagination *PaginationResult
+	err        error
+}
+
+// fetchPage fetches a page in a goroutine and sends it on the returned channel.
+func fetchPage[T any](ctx context.Context, fetch PageFunc[T], limit, offset int) <-chan *page[T] {
+	ch := make(chan *page[T], 1)
+
+	go func() {
+		results, pagination, err := fetch(ctx, &PaginationOptions{
+			Limit:  limit,
+			Offset: offset,
+		})
+
+		ch <- &page[T]{
+			results:    results,
+			pagination: pagination,
+			err:        err,
+		}
+	}()
+
+	return ch
+}
+
+// listAll collects the results of every page, starting at the page described by pagination.
+func listAll[T any](ctx context.Context, pagination *PaginationOptions, fetch PageFunc[T]) ([]T, error) {
+	opts := &PaginateOptions{}
+
+	if pagination != nil {
+		opts.PageSize = pagination.Limit
+		opts.Offset = pagination.Offset
+	}
+
+	var all []T
+
+	var err error
+	Paginate(ctx, fetch, opts)(func(result T, fetchErr error) bool {
+		if fetchErr != nil {
+			err = fetchErr
+
+			return false
+		}
+
+		all = append(all, result)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
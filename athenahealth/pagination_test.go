@@ -0,0 +1,141 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPages returns a PageFunc over results that records the offsets it is called with.
+func testPages(results []int, offsets *[]int) PageFunc[int] {
+	return func(ctx context.Context, pagination *PaginationOptions) ([]int, *PaginationResult, error) {
+		*offsets = append(*offsets, pagination.Offset)
+
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		end := min(pagination.Offset+pagination.Limit, len(results))
+		page := results[pagination.Offset:end]
+
+		var next int
+		if end < len(results) {
+			next = end
+		}
+
+		return page, &PaginationResult{NextOffset: next, PreviousOffset: pagination.Offset, TotalCount: len(results)}, nil
+	}
+}
+
+func collectPages[T any](seq func(yield func(T, error) bool)) ([]T, error) {
+	var all []T
+
+	var err error
+	seq(func(result T, resultErr error) bool {
+		if resultErr != nil {
+			err = resultErr
+
+			return false
+		}
+
+		all = append(all, result)
+
+		return true
+	})
+
+	return all, err
+}
+
+func TestPaginate(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		assert := assert.New(t)
+
+		var offsets []int
+
+		results, err := collectPages(Paginate(context.Background(), testPages([]int{1, 2, 3, 4, 5}, &offsets), &PaginateOptions{
+			PageSize: 2,
+			Prefetch: prefetch,
+		}))
+		assert.NoError(err)
+		assert.Equal([]int{1, 2, 3, 4, 5}, results)
+		assert.Equal([]int{0, 2, 4}, offsets)
+	}
+}
+
+func TestPaginate_offset(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []int
+
+	results, err := collectPages(Paginate(context.Background(), testPages([]int{1, 2, 3, 4, 5}, &offsets), &PaginateOptions{
+		PageSize: 2,
+		Offset:   3,
+	}))
+	assert.NoError(err)
+	assert.Equal([]int{4, 5}, results)
+	assert.Equal([]int{3}, offsets)
+}
+
+func TestPaginate_stop(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []int
+	var results []int
+
+	Paginate(context.Background(), testPages([]int{1, 2, 3, 4, 5}, &offsets), &PaginateOptions{PageSize: 2})(func(result int, err error) bool {
+		results = append(results, result)
+
+		return result < 3
+	})
+
+	assert.Equal([]int{1, 2, 3}, results)
+	assert.Equal([]int{0, 2}, offsets)
+}
+
+func TestPaginate_error(t *testing.T) {
+	assert := assert.New(t)
+
+	errFailed := errors.New("failed")
+
+	var offsets []int
+	pages := testPages([]int{1, 2, 3}, &offsets)
+
+	results, err := collectPages(Paginate(context.Background(), func(ctx context.Context, pagination *PaginationOptions) ([]int, *PaginationResult, error) {
+		if pagination.Offset > 0 {
+			return nil, nil, errFailed
+		}
+
+		return pages(ctx, pagination)
+	}, &PaginateOptions{PageSize: 2, Prefetch: true}))
+	assert.ErrorIs(err, errFailed)
+	assert.Equal([]int{1, 2}, results)
+}
+
+func TestPaginate_context_cancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var offsets []int
+	var results []int
+
+	var err error
+	Paginate(ctx, testPages([]int{1, 2, 3, 4, 5}, &offsets), &PaginateOptions{PageSize: 2})(func(result int, resultErr error) bool {
+		if resultErr != nil {
+			err = resultErr
+
+			return false
+		}
+
+		results = append(results, result)
+		cancel()
+
+		return true
+	})
+
+	assert.ErrorIs(err, context.Canceled)
+	assert.Equal([]int{1, 2}, results)
+	assert.Equal([]int{0}, offsets)
+}
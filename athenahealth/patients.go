@@ -248,6 +248,8 @@ type GetPatientOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Get-specific-patient-record
 func (h *HTTPClient) GetPatient(ctx context.Context, id string, opts *GetPatientOptions) (*Patient, error) {
+	ctx = withOperation(ctx, "GetPatient")
+
 	out, q := []*Patient{}, url.Values{}
 
 	if opts != nil {
@@ -298,6 +300,8 @@ func (h *HTTPClient) GetPatient(ctx context.Context, id string, opts *GetPatient
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Get-specific-patient-record
 func (h *HTTPClient) GetPatients(ctx context.Context, id string, opts *GetPatientOptions) ([]*Patient, error) {
+	ctx = withOperation(ctx, "GetPatients")
+
 	out, q := []*Patient{}, url.Values{}
 
 	if opts != nil {
@@ -365,6 +369,8 @@ type listPatientsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Get-list-of-patients-for-a-practice
 func (h *HTTPClient) ListPatients(ctx context.Context, opts *ListPatientsOptions) (*ListPatientsResult, error) {
+	ctx = withOperation(ctx, "ListPatients")
+
 	out := &listPatientsResponse{}
 
 	q := url.Values{}
@@ -408,6 +414,28 @@ func (h *HTTPClient) ListPatients(ctx context.Context, opts *ListPatientsOptions
 	}, nil
 }
 
+// ListAllPatients - Get all patients matching the search criteria
+func (h *HTTPClient) ListAllPatients(ctx context.Context, opts *ListPatientsOptions) ([]*Patient, error) {
+	ctx = withOperation(ctx, "ListAllPatients")
+
+	o := ListPatientsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*Patient, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListPatients(ctx, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Patients, res.Pagination, nil
+	})
+}
+
 type (
 	UpdatePatientOptions struct {
 		Address1            *string
@@ -467,6 +495,8 @@ var (
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Update-specific-patient-record
 func (h *HTTPClient) UpdatePatient(ctx context.Context, patientID string, opts *UpdatePatientOptions) (*UpdatePatientResult, error) {
+	ctx = withOperation(ctx, "UpdatePatient")
+
 	out := []*updatePatientResponse{}
 
 	form := url.Values{}
@@ -637,6 +667,8 @@ type patientPhoto struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient-photo#Get-patient's-photo
 func (h *HTTPClient) GetPatientPhoto(ctx context.Context, patientID string, opts *GetPatientPhotoOptions) (string, error) {
+	ctx = withOperation(ctx, "GetPatientPhoto")
+
 	out := &patientPhoto{}
 
 	q := url.Values{}
@@ -661,6 +693,8 @@ func (h *HTTPClient) GetPatientPhoto(ctx context.Context, patientID string, opts
 //
 // https://docs.athenahealth.com/api/api-ref/patient-photo#Update-patient's-photo
 func (h *HTTPClient) UpdatePatientPhoto(ctx context.Context, patientID string, data []byte) error {
+	ctx = withOperation(ctx, "UpdatePatientPhoto")
+
 	form := url.Values{}
 	form.Add("image", base64.StdEncoding.EncodeToString(data))
 
@@ -674,6 +708,8 @@ func (h *HTTPClient) UpdatePatientPhoto(ctx context.Context, patientID string, d
 // POST /v1/{practiceid}/patients/{patientid}/photo
 // https://developer.athenahealth.com/docs/read/forms_and_documents/Patient_Photo#section-1
 func (h *HTTPClient) UpdatePatientPhotoReader(ctx context.Context, patientID string, r io.Reader) error {
+	ctx = withOperation(ctx, "UpdatePatientPhotoReader")
+
 	form := NewFormURLEncoder()
 	form.AddReader("image", r)
 
@@ -701,6 +737,8 @@ type listChangedPatientsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Get-list-of-changes-in-patient-records
 func (h *HTTPClient) ListChangedPatients(ctx context.Context, opts *ListChangedPatientOptions) ([]*Patient, error) {
+	ctx = withOperation(ctx, "ListChangedPatients")
+
 	out := &listChangedPatientsResponse{}
 
 	q := url.Values{}
@@ -765,6 +803,8 @@ type updatePatientInformationVerificationDetailsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/privacy-information-verification#Update-patient's-privacy-information-verification-details
 func (h *HTTPClient) UpdatePatientInformationVerificationDetails(ctx context.Context, patientID string, opts *UpdatePatientInformationVerificationDetailsOptions) error {
+	ctx = withOperation(ctx, "UpdatePatientInformationVerificationDetails")
+
 	out := []*updatePatientInformationVerificationDetailsResponse{}
 	var form url.Values
 
@@ -829,6 +869,8 @@ type updatePatientMedicationHistoryConsentResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/medication-history-consent
 func (h *HTTPClient) UpdatePatientMedicationHistoryConsent(ctx context.Context, patientID string, opts *UpdatePatientMedicationHistoryConsentOptions) error {
+	ctx = withOperation(ctx, "UpdatePatientMedicationHistoryConsent")
+
 	out := []*updatePatientMedicationHistoryConsentResponse{}
 	var form url.Values
 
@@ -859,6 +901,8 @@ func (h *HTTPClient) UpdatePatientMedicationHistoryConsent(ctx context.Context,
 //
 // https://docs.athenahealth.com/api/api-ref/patient-custom-fields#Get-custom-field-information-from-patient's-records
 func (h *HTTPClient) GetPatientCustomFields(ctx context.Context, patientID, departmentID string) ([]*CustomFieldValue, error) {
+	ctx = withOperation(ctx, "GetPatientCustomFields")
+
 	out := []*CustomFieldValue{}
 
 	query := url.Values{}
@@ -884,6 +928,8 @@ type updatePatientCustomFieldsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient-custom-fields#Update-custom-field-information-from-patient's-records
 func (h *HTTPClient) UpdatePatientCustomFields(ctx context.Context, patientID, departmentID string, customFields []*CustomFieldValue) error {
+	ctx = withOperation(ctx, "UpdatePatientCustomFields")
+
 	out := &updatePatientCustomFieldsResponse{}
 
 	customFieldsJSON, err := json.Marshal(customFields)
@@ -932,6 +978,8 @@ type listPatientsMatchingCustomFieldResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Get-list-of-patients---matching-custom-field-criteria
 func (h *HTTPClient) ListPatientsMatchingCustomField(ctx context.Context, opts *ListPatientsMatchingCustomFieldOptions) (*ListPatientsMatchingCustomFieldResult, error) {
+	ctx = withOperation(ctx, "ListPatientsMatchingCustomField")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -960,6 +1008,29 @@ func (h *HTTPClient) ListPatientsMatchingCustomField(ctx context.Context, opts *
 	}, nil
 }
 
+// ListAllPatientsMatchingCustomField - Get all patients with a custom field value
+func (h *HTTPClient) ListAllPatientsMatchingCustomField(ctx context.Context, opts *ListPatientsMatchingCustomFieldOptions) ([]*Patient, error) {
+	ctx = withOperation(ctx, "ListAllPatientsMatchingCustomField")
+
+	if opts == nil {
+		panic("opts is nil")
+	}
+
+	o := *opts
+
+	return listAll(ctx, o.Pagination, func(ctx context.Context, pagination *PaginationOptions) ([]*Patient, *PaginationResult, error) {
+		o := o
+		o.Pagination = pagination
+
+		res, err := h.ListPatientsMatchingCustomField(ctx, &o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Patients, res.Pagination, nil
+	})
+}
+
 type CreatePatientOptions struct {
 	Address1              string
 	Address2              string
@@ -992,6 +1063,8 @@ type createPatientResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/patient#Create-new-patient-record
 func (h *HTTPClient) CreatePatient(ctx context.Context, opts *CreatePatientOptions) (string, error) {
+	ctx = withOperation(ctx, "CreatePatient")
+
 	if opts == nil {
 		panic("opts is nil")
 	}
@@ -102,6 +102,44 @@ func TestHTTPClient_ListPatients(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestHTTPClient_ListAllPatients(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("Smith", r.URL.Query().Get("lastname"))
+		assert.Equal("1", r.URL.Query().Get("limit"))
+
+		switch r.URL.Query().Get("offset") {
+		case "":
+			w.Write([]byte(`{"patients": [{"patientid": "1"}], "next": "/v1/195900/patients?limit=1&offset=1", "totalcount": 2}`))
+		case "1":
+			w.Write([]byte(`{"patients": [{"patientid": "2"}], "previous": "/v1/195900/patients?limit=1&offset=0", "totalcount": 2}`))
+		default:
+			t.Errorf("unexpected offset %s", r.URL.Query().Get("offset"))
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	opts := &ListPatientsOptions{
+		LastName: "Smith",
+		Pagination: &PaginationOptions{
+			Limit: 1,
+		},
+	}
+
+	patients, err := athenaClient.ListAllPatients(context.Background(), opts)
+	assert.NoError(err)
+	if assert.Len(patients, 2) {
+		assert.Equal("1", patients[0].PatientID)
+		assert.Equal("2", patients[1].PatientID)
+	}
+
+	// The caller's options are not modified.
+	assert.Equal(&PaginationOptions{Limit: 1}, opts.Pagination)
+}
+
 func TestHTTPClient_GetPatientPhoto_JPEGOutputNotSupported(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,164 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type practiceIDContextKey struct{}
+
+// WithPracticeID returns a copy of ctx that sends requests made with it to practiceID instead of the client's
+// practice. The client's transport, token cache and limiters are shared by every practice.
+func WithPracticeID(ctx context.Context, practiceID string) context.Context {
+	return context.WithValue(ctx, practiceIDContextKey{}, practiceID)
+}
+
+// PracticeIDFromContext returns the practice ID carried by ctx, or an empty string if it has none.
+func PracticeIDFromContext(ctx context.Context) string {
+	practiceID, _ := ctx.Value(practiceIDContextKey{}).(string)
+
+	return practiceID
+}
+
+// ForPractice returns a Client for practiceID. It is a shallow copy of h, so the transport, token provider,
+// token cache, limiters, stats, auditor, logger and other options are shared with h. A practice ID in the
+// request context takes precedence over the practice of the client.
+func (h *HTTPClient) ForPractice(practiceID string) Client {
+	c := *h
+
+	c.practiceID = practiceID
+	c.setBaseURL()
+
+	// If the returned client is configured as an *HTTPClient, it can not append to h's middleware, and
+	// closing it does not stop h's token refresher.
+	c.middleware = slices.Clip(c.middleware)
+	c.tokenRefresher = &tokenRefresher{}
+
+	return &c
+}
+
+// practice returns the practice ID and base URL of a request made with ctx.
+func (h *HTTPClient) practice(ctx context.Context) (string, string) {
+	practiceID := PracticeIDFromContext(ctx)
+	if len(practiceID) == 0 || practiceID == h.practiceID {
+		return h.practiceID, h.baseURL
+	}
+
+	return practiceID, h.practiceBaseURL(practiceID)
+}
+
+func (h *HTTPClient) practiceBaseURL(practiceID string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(h.environment.BaseURL, "/"), practiceID)
+}
+
+// PracticeError is returned by FanOut when a call fails for a practice.
+type PracticeError struct {
+	PracticeID string
+	Err        error
+}
+
+func (p *PracticeError) Error() string {
+	return fmt.Sprintf("practice %s: %s", p.PracticeID, p.Err)
+}
+
+func (p *PracticeError) Unwrap() error {
+	return p.Err
+}
+
+// defaultFanOutConcurrency is the number of practices FanOut calls fn for at once by default.
+const defaultFanOutConcurrency = 10
+
+type FanOutOptions struct {
+	// Concurrency is the number of practices fn is called for at once, 10 by default. It bounds the
+	// goroutines FanOut starts so a long list of practices is not queued on the client's concurrency
+	// limiter (see WithConcurrencyLimiter) all at once. Requests made by fn are still subject to the
+	// limiter, which bounds requests across everything sharing the client.
+	Concurrency int
+}
+
+// FanOut calls fn concurrently for each practice with a context from WithPracticeID, so a single client
+// can be used for every practice, and returns the results keyed by practice ID. Duplicate practice IDs
+// are called once. The calls are bounded by opts.Concurrency and by the client's rate and concurrency
+// limiters. If any call fails, the results of the practices that succeeded are returned with the errors
+// joined as *PracticeError.
+func FanOut[T any](ctx context.Context, practiceIDs []string, opts *FanOutOptions, fn func(ctx context.Context, practiceID string) (T, error)) (map[string]T, error) {
+	practiceIDs = uniquePracticeIDs(practiceIDs)
+
+	concurrency := defaultFanOutConcurrency
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	results := make(map[string]T, len(practiceIDs))
+	errs := make([]error, len(practiceIDs))
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, concurrency)
+
+	for i, practiceID := range practiceIDs {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(i int, practiceID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			result, err := fn(WithPracticeID(ctx, practiceID), practiceID)
+			if err != nil {
+				errs[i] = &PracticeError{
+					PracticeID: practiceID,
+					Err:        err,
+				}
+
+				return
+			}
+
+			lock.Lock()
+			results[practiceID] = result
+			lock.Unlock()
+		}(i, practiceID)
+	}
+
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// FanOutMerge calls fn for each practice like FanOut and merges the results in the order of practiceIDs.
+func FanOutMerge[T any](ctx context.Context, practiceIDs []string, opts *FanOutOptions, fn func(ctx context.Context, practiceID string) ([]T, error)) ([]T, error) {
+	practiceIDs = uniquePracticeIDs(practiceIDs)
+
+	results, err := FanOut(ctx, practiceIDs, opts, fn)
+
+	var merged []T
+	for _, practiceID := range practiceIDs {
+		merged = append(merged, results[practiceID]...)
+	}
+
+	return merged, err
+}
+
+// uniquePracticeIDs returns practiceIDs without duplicates, in the order they first appear.
+func uniquePracticeIDs(practiceIDs []string) []string {
+	seen := make(map[string]bool, len(practiceIDs))
+	unique := make([]string, 0, len(practiceIDs))
+
+	for _, practiceID := range practiceIDs {
+		if seen[practiceID] {
+			continue
+		}
+
+		seen[practiceID] = true
+		unique = append(unique, practiceID)
+	}
+
+	return unique
+}
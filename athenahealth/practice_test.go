@@ -0,0 +1,196 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient_WithPracticeID(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var paths []string
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		paths = append(paths, r.URL.Path)
+		lock.Unlock()
+
+		w.Write([]byte(`[{"patientid": "1"}]`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	testAuditor := &testAuditor{}
+	athenaClient.WithAuditor(testAuditor)
+
+	var practiceIDs []string
+
+	athenaClient.WithRateLimiter(&testRouteRateLimiter{
+		AllowedRouteFunc: func(preview bool, practiceID, method, path string) (time.Duration, error) {
+			practiceIDs = append(practiceIDs, practiceID)
+
+			return 0, nil
+		},
+	})
+
+	_, err := athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	_, err = athenaClient.GetPatient(WithPracticeID(context.Background(), "2"), "1", nil)
+	assert.NoError(err)
+
+	_, err = athenaClient.ForPractice("3").GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	// The practice in the context takes precedence.
+	_, err = athenaClient.ForPractice("3").GetPatient(WithPracticeID(context.Background(), "4"), "1", nil)
+	assert.NoError(err)
+
+	assert.Equal([]string{"/" + testPracticeID + "/patients/1", "/2/patients/1", "/3/patients/1", "/4/patients/1"}, paths)
+	assert.Equal([]string{testPracticeID, "2", "3", "4"}, practiceIDs)
+
+	if assert.Len(testAuditor.events, 4) {
+		assert.Equal(testPracticeID, testAuditor.events[0].PracticeID)
+		assert.Equal("2", testAuditor.events[1].PracticeID)
+		assert.Equal("3", testAuditor.events[2].PracticeID)
+		assert.Equal("4", testAuditor.events[3].PracticeID)
+	}
+}
+
+func TestHTTPClient_ForPractice(t *testing.T) {
+	assert := assert.New(t)
+
+	var paths []string
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		w.Write([]byte(`[{"patientid": "1"}]`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	athenaClient.WithMiddleware(func(next RoundTrip) RoundTrip {
+		return next
+	})
+
+	practiceClient := athenaClient.ForPractice("2").(*HTTPClient)
+
+	_, err := practiceClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	_, err = athenaClient.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	assert.Equal([]string{"/2/patients/1", "/" + testPracticeID + "/patients/1"}, paths)
+	assert.Same(athenaClient.httpClient, practiceClient.httpClient)
+	assert.Same(athenaClient.tokenGroup, practiceClient.tokenGroup)
+	assert.Equal(athenaClient.tokenCacher, practiceClient.tokenCacher)
+
+	practiceClient.WithMiddleware(func(next RoundTrip) RoundTrip {
+		return next
+	})
+
+	assert.Len(athenaClient.middleware, 1)
+	assert.Len(practiceClient.middleware, 2)
+}
+
+func TestFanOut(t *testing.T) {
+	assert := assert.New(t)
+
+	errFailed := errors.New("failed")
+
+	results, err := FanOut(context.Background(), []string{"1", "2", "3"}, nil, func(ctx context.Context, practiceID string) (string, error) {
+		assert.Equal(practiceID, PracticeIDFromContext(ctx))
+
+		if practiceID == "2" {
+			return "", errFailed
+		}
+
+		return "practice " + practiceID, nil
+	})
+
+	assert.Equal(map[string]string{"1": "practice 1", "3": "practice 3"}, results)
+	assert.ErrorIs(err, errFailed)
+
+	var practiceErr *PracticeError
+	if assert.True(errors.As(err, &practiceErr)) {
+		assert.Equal("2", practiceErr.PracticeID)
+	}
+}
+
+func TestFanOut_concurrency(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var running, maxRunning int
+
+	practiceIDs := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+
+	results, err := FanOut(context.Background(), practiceIDs, &FanOutOptions{Concurrency: 3}, func(ctx context.Context, practiceID string) (string, error) {
+		lock.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+
+		return practiceID, nil
+	})
+	assert.NoError(err)
+	assert.Len(results, len(practiceIDs))
+	assert.Equal(3, maxRunning)
+}
+
+func TestFanOutMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"patients": [{"patientid": "1"}, {"patientid": "2"}]}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	patients, err := FanOutMerge(context.Background(), []string{"1", "2"}, nil, func(ctx context.Context, practiceID string) ([]*Patient, error) {
+		res, err := athenaClient.ListPatients(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return res.Patients, nil
+	})
+	assert.NoError(err)
+	assert.Len(patients, 4)
+}
+
+func TestFanOutMerge_duplicate_practice_ids(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	calls := make(map[string]int)
+
+	merged, err := FanOutMerge(context.Background(), []string{"1", "2", "1"}, nil, func(ctx context.Context, practiceID string) ([]string, error) {
+		lock.Lock()
+		calls[practiceID]++
+		lock.Unlock()
+
+		return []string{"record from " + practiceID}, nil
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"record from 1", "record from 2"}, merged)
+	assert.Equal(map[string]int{"1": 1, "2": 1}, calls)
+}
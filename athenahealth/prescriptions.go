@@ -59,6 +59,8 @@ type ListChangedPrescriptionsResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-prescription#Get-list-of-changes-in-prescriptions
 func (h *HTTPClient) ListChangedPrescriptions(ctx context.Context, opts *ListChangedPrescriptionsOptions) (*ListChangedPrescriptionsResult, error) {
+	ctx = withOperation(ctx, "ListChangedPrescriptions")
+
 	q := url.Values{}
 
 	if opts != nil {
@@ -116,6 +118,8 @@ type UpdatePrescriptionResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/document-type-prescription#Update-specific-prescription-document-for-given-patient
 func (h *HTTPClient) UpdatePrescription(ctx context.Context, departmentID int, patientID int, documentID int, opts *UpdatePrescriptionOptions) (*UpdatePrescriptionResult, error) {
+	ctx = withOperation(ctx, "UpdatePrescription")
+
 	out := &UpdatePrescriptionResult{}
 
 	form := url.Values{}
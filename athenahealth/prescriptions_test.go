@@ -56,7 +56,7 @@ func TestHTTPClient_UpdatePrescriptionActionNote(t *testing.T) {
 		// Extract patientID and documentID from the URL path
 		// Path: /patients/{patientid}/documents/prescriptions/{documentid}
 		var gotPatientID, gotDocumentID int
-		_, err := fmt.Sscanf(r.URL.Path, "/patients/%d/documents/prescriptions/%d", &gotPatientID, &gotDocumentID)
+		_, err := fmt.Sscanf(r.URL.Path, "/"+testPracticeID+"/patients/%d/documents/prescriptions/%d", &gotPatientID, &gotDocumentID)
 		assert.NoError(err)
 		assert.Equal(patientID, gotPatientID)
 		assert.Equal(documentID, gotDocumentID)
@@ -118,7 +118,7 @@ func TestHTTPClient_UpdatePrescription_OtherFields_Success(t *testing.T) {
 		assert.Equal(strconv.FormatBool(pin), r.Form.Get("pintotop"))
 
 		var gotPatientID, gotDocumentID int
-		_, err := fmt.Sscanf(r.URL.Path, "/patients/%d/documents/prescriptions/%d", &gotPatientID, &gotDocumentID)
+		_, err := fmt.Sscanf(r.URL.Path, "/"+testPracticeID+"/patients/%d/documents/prescriptions/%d", &gotPatientID, &gotDocumentID)
 		assert.NoError(err)
 		assert.Equal(patientID, gotPatientID)
 		assert.Equal(documentID, gotDocumentID)
@@ -154,7 +154,7 @@ func TestHTTPClient_UpdatePrescription_PinToTopFalse_Success(t *testing.T) {
 		assert.Equal("false", r.Form.Get("pintotop"))
 
 		var gotPatientID, gotDocumentID int
-		_, err := fmt.Sscanf(r.URL.Path, "/patients/%d/documents/prescriptions/%d", &gotPatientID, &gotDocumentID)
+		_, err := fmt.Sscanf(r.URL.Path, "/"+testPracticeID+"/patients/%d/documents/prescriptions/%d", &gotPatientID, &gotDocumentID)
 		assert.NoError(err)
 		assert.Equal(patientID, gotPatientID)
 		assert.Equal(documentID, gotDocumentID)
@@ -78,6 +78,8 @@ type listProblemsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/problems#Get-patient's-problem-list
 func (h *HTTPClient) ListProblems(ctx context.Context, patientID string, opts *ListProblemsOptions) ([]*Problem, error) {
+	ctx = withOperation(ctx, "ListProblems")
+
 	out := &listProblemsResponse{}
 
 	q := url.Values{}
@@ -121,6 +123,8 @@ type listChangedProblemsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/problems#Get-list-of-changes-in-problems-based-on-subscribed-events
 func (h *HTTPClient) ListChangedProblems(ctx context.Context, opts *ListChangedProblemsOptions) ([]*ChangedProblem, error) {
+	ctx = withOperation(ctx, "ListChangedProblems")
+
 	out := &listChangedProblemsResponse{}
 
 	q := url.Values{}
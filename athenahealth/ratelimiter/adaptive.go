@@ -0,0 +1,196 @@
+package ratelimiter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultAdaptiveMinRate = 1
+const defaultAdaptiveRecoveryPeriod = time.Minute
+
+// adaptiveDecreaseFactor is applied to the current rate whenever athena throttles a request.
+const adaptiveDecreaseFactor = 0.5
+
+// masheryErrorCodeHeader carries the reason athena's API gateway rejected a request,
+// e.g. ERR_403_DEVELOPER_OVER_QPS when the per-second limit is exceeded.
+const masheryErrorCodeHeader = "X-Mashery-Error-Code"
+
+// Adaptive is an in-process rate limiter that starts at the configured per-second rate and
+// halves it whenever athena throttles a request (a 429, an over-QPS/over-rate gateway error,
+// or a Retry-After header), then linearly recovers to the configured rate over the recovery
+// period. HTTPClient reports responses to it through ObserveResponse.
+type Adaptive struct {
+	preview *adaptiveState
+	prod    *adaptiveState
+
+	minRate        float64
+	recoveryPeriod time.Duration
+	now            func() time.Time
+
+	lock sync.Mutex
+}
+
+type adaptiveState struct {
+	maxRate float64
+	rate    float64
+
+	// next is the earliest time the next request is allowed.
+	next         time.Time
+	blockedUntil time.Time
+	decreasedAt  time.Time
+	recoveredAt  time.Time
+}
+
+func NewAdaptive(ratePreview, rateProd int) *Adaptive {
+	if ratePreview <= 0 {
+		ratePreview = defaultRatePerSecPreview
+	}
+
+	if rateProd <= 0 {
+		rateProd = defaultRatePerSecProd
+	}
+
+	return &Adaptive{
+		preview: newAdaptiveState(float64(ratePreview)),
+		prod:    newAdaptiveState(float64(rateProd)),
+
+		minRate:        defaultAdaptiveMinRate,
+		recoveryPeriod: defaultAdaptiveRecoveryPeriod,
+		now:            time.Now,
+	}
+}
+
+func newAdaptiveState(rate float64) *adaptiveState {
+	return &adaptiveState{
+		maxRate: rate,
+		rate:    rate,
+	}
+}
+
+// WithMinRate sets the per-second rate the limiter never drops below.
+func (a *Adaptive) WithMinRate(minRate float64) *Adaptive {
+	a.minRate = minRate
+
+	return a
+}
+
+// WithRecoveryPeriod sets how long it takes to recover from the minimum rate to the configured rate.
+func (a *Adaptive) WithRecoveryPeriod(recoveryPeriod time.Duration) *Adaptive {
+	a.recoveryPeriod = recoveryPeriod
+
+	return a
+}
+
+// WithClock sets the function the limiter tells the time with, e.g. a fake clock in tests.
+func (a *Adaptive) WithClock(now func() time.Time) *Adaptive {
+	a.now = now
+
+	return a
+}
+
+// Rate returns the current per-second rate.
+func (a *Adaptive) Rate(preview bool) float64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	s := a.state(preview)
+	a.recover(s, a.now())
+
+	return s.rate
+}
+
+func (a *Adaptive) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.now()
+
+	s := a.state(preview)
+	a.recover(s, now)
+
+	if now.Before(s.blockedUntil) {
+		return s.blockedUntil.Sub(now), ErrRateExceeded
+	}
+
+	if now.Before(s.next) {
+		return s.next.Sub(now), ErrRateExceeded
+	}
+
+	s.next = now.Add(s.interval())
+
+	return 0, nil
+}
+
+func (a *Adaptive) ObserveResponse(preview bool, res *http.Response) {
+	if res == nil {
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.now()
+
+	s := a.state(preview)
+
+	if !throttled(res) {
+		a.recover(s, now)
+		return
+	}
+
+	if retryAfter, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && retryAfter > 0 {
+		s.blockedUntil = now.Add(time.Duration(retryAfter) * time.Second)
+	}
+
+	// Responses to requests that were already in flight when the rate was last
+	// decreased would otherwise collapse the rate to the minimum at once.
+	if now.Sub(s.decreasedAt) < s.interval() {
+		return
+	}
+
+	s.rate = max(s.rate*adaptiveDecreaseFactor, a.minRate)
+	s.decreasedAt = now
+	s.recoveredAt = now
+	s.next = now.Add(s.interval())
+}
+
+func (a *Adaptive) state(preview bool) *adaptiveState {
+	if preview {
+		return a.preview
+	}
+
+	return a.prod
+}
+
+// recover linearly increases the rate back towards the configured rate.
+func (a *Adaptive) recover(s *adaptiveState, now time.Time) {
+	if s.rate >= s.maxRate {
+		return
+	}
+
+	elapsed := now.Sub(s.recoveredAt)
+	s.recoveredAt = now
+
+	s.rate = min(s.rate+(s.maxRate-a.minRate)*elapsed.Seconds()/a.recoveryPeriod.Seconds(), s.maxRate)
+}
+
+func (s *adaptiveState) interval() time.Duration {
+	return time.Duration(float64(time.Second) / s.rate)
+}
+
+func throttled(res *http.Response) bool {
+	if res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	errorCode := res.Header.Get(masheryErrorCodeHeader)
+	if strings.Contains(errorCode, "OVER_QPS") || strings.Contains(errorCode, "OVER_RATE") {
+		return true
+	}
+
+	return len(res.Header.Get("Retry-After")) > 0
+}
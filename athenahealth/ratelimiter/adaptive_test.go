@@ -0,0 +1,137 @@
+package ratelimiter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAdaptive(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(0, 0)
+
+	assert.Equal(float64(defaultRatePerSecPreview), rateLimiter.Rate(true))
+	assert.Equal(float64(defaultRatePerSecProd), rateLimiter.Rate(false))
+}
+
+func TestAdaptive_Allowed(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(10, 10)
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.NotZero(retryAfter)
+	assert.LessOrEqual(retryAfter, 100*time.Millisecond)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	// Environments are limited independently.
+	retryAfter, err = rateLimiter.Allowed(context.Background(), false)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+}
+
+func TestAdaptive_ObserveResponse_throttled(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, res := range []*http.Response{
+		{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+		{StatusCode: http.StatusForbidden, Header: http.Header{masheryErrorCodeHeader: {"ERR_403_DEVELOPER_OVER_QPS"}}},
+		{StatusCode: http.StatusForbidden, Header: http.Header{masheryErrorCodeHeader: {"ERR_403_DEVELOPER_OVER_RATE"}}},
+	} {
+		rateLimiter := NewAdaptive(100, 100)
+
+		rateLimiter.ObserveResponse(false, res)
+
+		assert.InDelta(50, rateLimiter.Rate(false), 0.1)
+		assert.Equal(float64(100), rateLimiter.Rate(true))
+	}
+}
+
+func TestAdaptive_ObserveResponse_not_throttled(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(100, 100)
+
+	for _, res := range []*http.Response{
+		{StatusCode: http.StatusOK, Header: http.Header{}},
+		{StatusCode: http.StatusForbidden, Header: http.Header{}},
+		{StatusCode: http.StatusInternalServerError, Header: http.Header{}},
+	} {
+		rateLimiter.ObserveResponse(false, res)
+	}
+
+	rateLimiter.ObserveResponse(false, nil)
+
+	assert.Equal(float64(100), rateLimiter.Rate(false))
+}
+
+func TestAdaptive_ObserveResponse_in_flight(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(100, 100)
+
+	// A burst of throttled responses to requests sent at the old rate only decreases the rate once.
+	for range 10 {
+		rateLimiter.ObserveResponse(false, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	}
+
+	assert.InDelta(50, rateLimiter.Rate(false), 0.1)
+}
+
+func TestAdaptive_ObserveResponse_min_rate(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(100, 100).WithMinRate(40)
+
+	rateLimiter.ObserveResponse(false, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	time.Sleep(30 * time.Millisecond)
+	rateLimiter.ObserveResponse(false, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+
+	assert.InDelta(40, rateLimiter.Rate(false), 0.1)
+}
+
+func TestAdaptive_ObserveResponse_RetryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(100, 100)
+
+	rateLimiter.ObserveResponse(false, &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"2"}}})
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), false)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Greater(retryAfter, time.Second)
+	assert.LessOrEqual(retryAfter, 2*time.Second)
+}
+
+func TestAdaptive_recovery(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewAdaptive(100, 100).WithRecoveryPeriod(400 * time.Millisecond)
+
+	rateLimiter.ObserveResponse(false, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	assert.Less(rateLimiter.Rate(false), float64(100))
+
+	time.Sleep(100 * time.Millisecond)
+
+	rate := rateLimiter.Rate(false)
+	assert.Greater(rate, float64(50))
+	assert.Less(rate, float64(100))
+
+	time.Sleep(300 * time.Millisecond)
+
+	assert.Equal(float64(100), rateLimiter.Rate(false))
+}
@@ -3,3 +3,7 @@ package ratelimiter
 import "errors"
 
 var ErrRateExceeded = errors.New("rate limit exceeded")
+
+// ErrQuotaExhausted is returned once the daily call quota is used up. Unlike ErrRateExceeded
+// it is not worth waiting for, so callers such as sync jobs should stop instead of retrying.
+var ErrQuotaExhausted = errors.New("daily quota exhausted")
@@ -0,0 +1,31 @@
+package ratelimiter
+
+import "context"
+
+// Priority orders requests competing for the same rate limit. Limiters that support priorities
+// keep part of their capacity in reserve for higher priority requests, so interactive requests
+// (e.g. booking an appointment) are not starved by background work (e.g. syncing changed patients).
+type Priority int
+
+const (
+	PriorityLow Priority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+type priorityContextKey struct{}
+
+// WithPriority returns a copy of ctx carrying the priority of requests made with it.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// PriorityFromContext returns the priority carried by ctx, or PriorityNormal if it has none.
+func PriorityFromContext(ctx context.Context) Priority {
+	priority, ok := ctx.Value(priorityContextKey{}).(Priority)
+	if !ok {
+		return PriorityNormal
+	}
+
+	return min(max(priority, PriorityLow), PriorityHigh)
+}
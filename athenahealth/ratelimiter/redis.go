@@ -2,6 +2,8 @@ package ratelimiter
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -11,15 +13,76 @@ import (
 const redisKeyPreview = "athena_rate_limit:preview"
 const redisKeyProd = "athena_rate_limit:prod"
 
+const redisQuotaKeyPrefix = "athena_quota"
+
 const defaultRatePerSecPreview = 5
 const defaultRatePerSecProd = 100
 
+// quotaScript counts a call against the daily quota unless the hourly buckets in KEYS, newest
+// first, already add up to the quota. It returns 1 and -1 if the call was counted, or 0 and the
+// index of the oldest non-empty bucket if the quota is exhausted.
+var quotaScript = redis.NewScript(`
+local used = 0
+local oldest = 0
+for i, key in ipairs(KEYS) do
+	local count = tonumber(redis.call("GET", key) or "0")
+	if count > 0 then
+		used = used + count
+		oldest = i - 1
+	end
+end
+
+if used >= tonumber(ARGV[1]) then
+	return {0, oldest}
+end
+
+redis.call("INCR", KEYS[1])
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+
+return {1, -1}
+`)
+
+// redisRateKeyPrefix is the prefix redis_rate adds to the keys of its buckets.
+const redisRateKeyPrefix = "rate:"
+
+// refundScript gives back a token taken by redis_rate from the bucket in KEYS[1] by moving its
+// theoretical arrival time back by the emission interval in ARGV[1], using redis_rate's clock. It
+// depends on how redis_rate v9.1 stores buckets: under redisRateKeyPrefix, with the theoretical
+// arrival time as a float of seconds since 2017-01-01. TestRedis_refund_redis_rate_state fails if
+// an upgrade changes that.
+var refundScript = redis.NewScript(`
+redis.replicate_commands()
+
+local tat = redis.call("GET", KEYS[1])
+if not tat then
+	return 0
+end
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local new_tat = tonumber(tat) - tonumber(ARGV[1])
+if new_tat <= now then
+	redis.call("DEL", KEYS[1])
+else
+	redis.call("SET", KEYS[1], new_tat, "EX", math.ceil(new_tat - now))
+end
+
+return 1
+`)
+
 type Redis struct {
 	client  *redis.Client
 	limiter *redis_rate.Limiter
 
 	ratePreivew int
 	rateProd    int
+
+	routes []*routeLimit
+
+	quotaPreview int
+	quotaProd    int
 }
 
 func NewRedis(client *redis.Client, ratePreview, rateProd int) *Redis {
@@ -46,18 +109,90 @@ func NewRedis(client *redis.Client, ratePreview, rateProd int) *Redis {
 	return r
 }
 
+// WithRouteLimit limits requests to the endpoints under path, e.g. /patients, to rate per second
+// in each environment, on top of the environment's overall rate. An empty method matches any method.
+// When several routes match a request, the one with the longest path is used. A rate of zero or less
+// is unlimited, e.g. to exempt endpoints nested under a limited path.
+func (r *Redis) WithRouteLimit(method, path string, rate int) *Redis {
+	r.routes = append(r.routes, newRouteLimit(method, path, rate))
+
+	return r
+}
+
+// WithDailyQuota limits the calls made for each practice over a rolling 24 hour window. A quota of zero is unlimited.
+func (r *Redis) WithDailyQuota(quotaPreview, quotaProd int) *Redis {
+	r.quotaPreview = quotaPreview
+	r.quotaProd = quotaProd
+
+	return r
+}
+
 func (r *Redis) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
-	var key string
-	var limit redis_rate.Limit
+	key, limit := r.limit(preview)
+
+	return r.allow(ctx, key, limit)
+}
 
+func (r *Redis) AllowedRoute(ctx context.Context, preview bool, practiceID, method, path string) (time.Duration, error) {
+	key, limit := r.limit(preview)
+
+	retryAfter, err := r.allow(ctx, key, limit)
+	if err != nil {
+		return retryAfter, err
+	}
+
+	// A request refused by the route limit or the quota is not sent, so it gives back the tokens it
+	// already took rather than slowing down other requests.
+	route := matchRoute(r.routes, method, path)
+	if route != nil && !route.limited() {
+		route = nil
+	}
+
+	var routeKey string
+	var routeLimit redis_rate.Limit
+
+	if route != nil {
+		routeKey = key + ":" + route.key()
+		routeLimit = redis_rate.PerSecond(route.rate)
+
+		retryAfter, err = r.allow(ctx, routeKey, routeLimit)
+		if err != nil {
+			r.refund(ctx, key, limit)
+
+			return retryAfter, err
+		}
+	}
+
+	quota := r.quotaProd
 	if preview {
-		key = redisKeyPreview
-		limit = redis_rate.PerSecond(r.ratePreivew)
-	} else {
-		key = redisKeyProd
-		limit = redis_rate.PerSecond(r.rateProd)
+		quota = r.quotaPreview
 	}
 
+	if quota <= 0 {
+		return 0, nil
+	}
+
+	retryAfter, err = r.takeQuota(ctx, preview, practiceID, quota, time.Now())
+	if err != nil {
+		r.refund(ctx, key, limit)
+
+		if route != nil {
+			r.refund(ctx, routeKey, routeLimit)
+		}
+	}
+
+	return retryAfter, err
+}
+
+func (r *Redis) limit(preview bool) (string, redis_rate.Limit) {
+	if preview {
+		return redisKeyPreview, redis_rate.PerSecond(r.ratePreivew)
+	}
+
+	return redisKeyProd, redis_rate.PerSecond(r.rateProd)
+}
+
+func (r *Redis) allow(ctx context.Context, key string, limit redis_rate.Limit) (time.Duration, error) {
 	res, err := r.limiter.Allow(ctx, key, limit)
 	if err != nil {
 		return 0, err
@@ -69,3 +204,37 @@ func (r *Redis) Allowed(ctx context.Context, preview bool) (time.Duration, error
 
 	return 0, nil
 }
+
+// refund gives back a token taken by allow. Errors are ignored since the token is returned to the
+// bucket over time anyway.
+func (r *Redis) refund(ctx context.Context, key string, limit redis_rate.Limit) {
+	emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+
+	//nolint
+	refundScript.Run(ctx, r.client, []string{redisRateKeyPrefix + key}, emissionInterval)
+}
+
+func (r *Redis) takeQuota(ctx context.Context, preview bool, practiceID string, quota int, now time.Time) (time.Duration, error) {
+	current := quotaBucketStart(now)
+
+	// The hash tag keeps every bucket for a practice in the same slot when running against a cluster.
+	prefix := fmt.Sprintf("%s:{%s:%s}:", redisQuotaKeyPrefix, environment(preview), practiceID)
+
+	keys := make([]string, quotaBuckets)
+	for i := range keys {
+		keys[i] = prefix + strconv.FormatInt(current.Add(-time.Duration(i)*quotaBucketSize).Unix(), 10)
+	}
+
+	res, err := quotaScript.Run(ctx, r.client, keys, quota, int(quotaWindow.Seconds())).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+
+	if res[0] == 0 {
+		oldest := current.Add(-time.Duration(res[1]) * quotaBucketSize)
+
+		return quotaRetryAfter(now, oldest), ErrQuotaExhausted
+	}
+
+	return 0, nil
+}
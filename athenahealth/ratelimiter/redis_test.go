@@ -2,6 +2,7 @@ package ratelimiter
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -54,3 +55,192 @@ func TestRedis_Allowed(t *testing.T) {
 	assert.Zero(retryAfter)
 	assert.NoError(err)
 }
+
+func TestRedis_AllowedRoute_route_limit(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), 100, 100).WithRouteLimit("GET", "/patients", 1)
+
+	retryAfter, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:")
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	retryAfter, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:")
+	assert.NotZero(retryAfter)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	retryAfter, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "PUT", "/patients/:id:")
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+}
+
+func TestRedis_AllowedRoute_daily_quota(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), 100, 100).WithDailyQuota(0, 2)
+
+	for range 2 {
+		_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients")
+		assert.NoError(err)
+	}
+
+	retryAfter, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients")
+	assert.ErrorIs(err, ErrQuotaExhausted)
+	assert.Greater(retryAfter, 23*time.Hour)
+
+	// Quotas are per practice.
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "2", "GET", "/patients")
+	assert.NoError(err)
+}
+
+func TestRedis_AllowedRoute_refunds(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), 2, 2).WithRouteLimit("GET", "/patients", 1).WithDailyQuota(0, 1)
+
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients")
+	assert.NoError(err)
+
+	// Requests refused by the route limit or the quota give back the token they took from the environment.
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients")
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/departments")
+	assert.ErrorIs(err, ErrQuotaExhausted)
+
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "2", "GET", "/departments")
+	assert.NoError(err)
+}
+
+func TestRedis_AllowedRoute_unlimited_route(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), 100, 100).
+		WithRouteLimit("", "/patients", 1).
+		WithRouteLimit("", "/patients/:id:/documents", 0).
+		WithDailyQuota(0, 1)
+
+	// A route with a rate of zero exempts its endpoints from the route they are nested under, and is
+	// not refunded when the quota refuses a request.
+	for range 5 {
+		_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:/documents/admin")
+		assert.NoError(err)
+
+		_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:/documents/admin")
+		assert.ErrorIs(err, ErrQuotaExhausted)
+
+		s.FlushAll()
+	}
+}
+
+// TestRedis_refund_redis_rate_state pins the details of redis_rate's buckets that refundScript depends on.
+func TestRedis_refund_redis_rate_state(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), 2, 2)
+
+	key, limit := rateLimiter.limit(false)
+
+	for range 2 {
+		_, err = rateLimiter.allow(context.Background(), key, limit)
+		assert.NoError(err)
+	}
+
+	_, err = rateLimiter.allow(context.Background(), key, limit)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	// The bucket is stored under redisRateKeyPrefix, with a theoretical arrival time in seconds since
+	// 2017-01-01 a second from now, after the 2 tokens were taken at 2 per second.
+	val, err := s.Get(redisRateKeyPrefix + key)
+	assert.NoError(err)
+
+	tat, err := strconv.ParseFloat(val, 64)
+	assert.NoError(err)
+
+	jan1st2017 := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.InDelta(time.Since(jan1st2017).Seconds()+1, tat, 0.1)
+
+	// A refunded token can be taken again.
+	rateLimiter.refund(context.Background(), key, limit)
+
+	_, err = rateLimiter.allow(context.Background(), key, limit)
+	assert.NoError(err)
+
+	_, err = rateLimiter.allow(context.Background(), key, limit)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	// Refunding every token empties the bucket.
+	rateLimiter.refund(context.Background(), key, limit)
+	rateLimiter.refund(context.Background(), key, limit)
+
+	assert.False(s.Exists(redisRateKeyPrefix + key))
+}
+
+func TestRedis_takeQuota_rolling(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), 100, 100)
+
+	start := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+
+	_, err = rateLimiter.takeQuota(context.Background(), false, "1", 2, start)
+	assert.NoError(err)
+
+	_, err = rateLimiter.takeQuota(context.Background(), false, "1", 2, start.Add(2*time.Hour))
+	assert.NoError(err)
+
+	retryAfter, err := rateLimiter.takeQuota(context.Background(), false, "1", 2, start.Add(3*time.Hour))
+	assert.ErrorIs(err, ErrQuotaExhausted)
+	assert.Equal(20*time.Hour+30*time.Minute, retryAfter)
+
+	_, err = rateLimiter.takeQuota(context.Background(), false, "1", 2, start.Add(24*time.Hour))
+	assert.NoError(err)
+}
@@ -0,0 +1,120 @@
+package ratelimiter
+
+import (
+	"strings"
+	"time"
+)
+
+// quotaWindow is the period the daily quota is enforced over. Calls are counted in hourly buckets,
+// so the window rolls forward an hour at a time.
+const quotaWindow = 24 * time.Hour
+const quotaBucketSize = time.Hour
+const quotaBuckets = int(quotaWindow / quotaBucketSize)
+
+type routeLimit struct {
+	// method is empty to match any method.
+	method string
+	// path matches normalized paths equal to or nested under it, e.g. /patients matches /patients/:id:/documents.
+	path string
+	// rate is the number of requests allowed per second. A rate of zero or less is unlimited.
+	rate int
+}
+
+func newRouteLimit(method, path string, rate int) *routeLimit {
+	return &routeLimit{
+		method: strings.ToUpper(method),
+		path:   strings.TrimSuffix(path, "/"),
+		rate:   rate,
+	}
+}
+
+func (r *routeLimit) key() string {
+	method := r.method
+	if len(method) == 0 {
+		method = "*"
+	}
+
+	return method + ":" + r.path
+}
+
+// limited reports whether the route has a rate, rather than exempting its endpoints from other routes.
+func (r *routeLimit) limited() bool {
+	return r.rate > 0
+}
+
+func (r *routeLimit) matches(method, path string) bool {
+	if len(r.method) > 0 && r.method != strings.ToUpper(method) {
+		return false
+	}
+
+	return path == r.path || strings.HasPrefix(path, r.path+"/")
+}
+
+// matchRoute returns the most specific route limit for method and path, or nil if none match.
+// Longer paths are more specific, and a route with a method beats one without at the same path.
+func matchRoute(routes []*routeLimit, method, path string) *routeLimit {
+	var match *routeLimit
+
+	for _, r := range routes {
+		if !r.matches(method, path) {
+			continue
+		}
+
+		if match == nil || len(r.path) > len(match.path) || (len(r.path) == len(match.path) && len(r.method) > 0) {
+			match = r
+		}
+	}
+
+	return match
+}
+
+// quotaBucketStart returns the start of the hourly bucket now falls in.
+func quotaBucketStart(now time.Time) time.Time {
+	return now.Truncate(quotaBucketSize)
+}
+
+// quotaRetryAfter returns how long until the bucket that started at oldest leaves the window.
+func quotaRetryAfter(now, oldest time.Time) time.Duration {
+	return oldest.Add(quotaWindow).Sub(now)
+}
+
+// dailyQuota counts calls over a rolling 24 hour window.
+type dailyQuota struct {
+	counts map[time.Time]int
+}
+
+func newDailyQuota() *dailyQuota {
+	return &dailyQuota{
+		counts: make(map[time.Time]int),
+	}
+}
+
+// take counts a call if fewer than quota calls were made in the window. Otherwise it returns how long until a call is allowed.
+func (q *dailyQuota) take(now time.Time, quota int) (time.Duration, bool) {
+	current := quotaBucketStart(now)
+	windowStart := current.Add(-quotaWindow + quotaBucketSize)
+
+	used := 0
+	oldest := current
+
+	for start, count := range q.counts {
+		if start.Before(windowStart) {
+			delete(q.counts, start)
+			continue
+		}
+
+		used += count
+
+		if start.Before(oldest) {
+			oldest = start
+		}
+	}
+
+	if used >= quota {
+		return quotaRetryAfter(now, oldest), false
+	}
+
+	q.counts[current]++
+
+	return 0, true
+}
@@ -0,0 +1,51 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	patients := newRouteLimit("", "/patients", 10)
+	patientDocuments := newRouteLimit("", "/patients/:id:/documents/", 5)
+	postPatients := newRouteLimit("post", "/patients", 2)
+
+	routes := []*routeLimit{patients, patientDocuments, postPatients}
+
+	assert.Equal(patients, matchRoute(routes, "GET", "/patients"))
+	assert.Equal(patients, matchRoute(routes, "GET", "/patients/:id:"))
+	assert.Equal(postPatients, matchRoute(routes, "POST", "/patients"))
+	assert.Equal(patientDocuments, matchRoute(routes, "POST", "/patients/:id:/documents/admin"))
+	assert.Nil(matchRoute(routes, "GET", "/patientsfoo"))
+	assert.Nil(matchRoute(routes, "GET", "/appointments/booked"))
+}
+
+func TestDailyQuota_take(t *testing.T) {
+	assert := assert.New(t)
+
+	q := newDailyQuota()
+
+	start := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+
+	_, ok := q.take(start, 2)
+	assert.True(ok)
+
+	_, ok = q.take(start.Add(2*time.Hour), 2)
+	assert.True(ok)
+
+	retryAfter, ok := q.take(start.Add(3*time.Hour), 2)
+	assert.False(ok)
+	// The first call's bucket started at 9:00 and leaves the window at 9:00 the next day.
+	assert.Equal(20*time.Hour+30*time.Minute, retryAfter)
+
+	// The window rolls forward and frees up the first call.
+	_, ok = q.take(start.Add(24*time.Hour), 2)
+	assert.True(ok)
+
+	_, ok = q.take(start.Add(24*time.Hour), 2)
+	assert.False(ok)
+}
@@ -0,0 +1,259 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultTokenBucketReserve = 0.1
+
+// TokenBucket is an in-process token bucket rate limiter with separate preview and production
+// buckets. Each bucket refills at the configured per-second rate up to its burst size.
+//
+// A fraction of each bucket is reserved per priority lane (see WithPriority): normal priority
+// requests leave one reserve for high priority requests, and low priority requests leave two.
+// Reserves only hold back burst capacity; sustained throughput is the same for every lane.
+//
+// Endpoints can be given their own, lower, rates with WithRouteLimit, and calls per practice can
+// be capped over a rolling 24 hour window with WithDailyQuota.
+type TokenBucket struct {
+	preview *bucket
+	prod    *bucket
+
+	reserveFraction float64
+
+	routes       []*routeLimit
+	routeBuckets map[string]*bucket
+
+	quotaPreview int
+	quotaProd    int
+	quotas       map[string]*dailyQuota
+
+	lock sync.Mutex
+}
+
+type bucket struct {
+	rate    float64
+	burst   float64
+	reserve float64
+
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewTokenBucket(ratePreview, rateProd int) *TokenBucket {
+	if ratePreview <= 0 {
+		ratePreview = defaultRatePerSecPreview
+	}
+
+	if rateProd <= 0 {
+		rateProd = defaultRatePerSecProd
+	}
+
+	t := &TokenBucket{
+		preview: newBucket(float64(ratePreview), float64(ratePreview), defaultTokenBucketReserve),
+		prod:    newBucket(float64(rateProd), float64(rateProd), defaultTokenBucketReserve),
+
+		reserveFraction: defaultTokenBucketReserve,
+
+		routeBuckets: make(map[string]*bucket),
+		quotas:       make(map[string]*dailyQuota),
+	}
+
+	return t
+}
+
+func newBucket(rate, burst, reserveFraction float64) *bucket {
+	b := &bucket{
+		rate:      rate,
+		updatedAt: time.Now(),
+	}
+
+	b.setBurst(burst, reserveFraction)
+	b.tokens = b.burst
+
+	return b
+}
+
+// WithBurst sets the maximum number of requests each bucket allows at once. It defaults to one second's worth of requests.
+func (t *TokenBucket) WithBurst(burstPreview, burstProd int) *TokenBucket {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if burstPreview > 0 {
+		t.preview.setBurst(float64(burstPreview), t.reserveFraction)
+		t.preview.tokens = t.preview.burst
+	}
+
+	if burstProd > 0 {
+		t.prod.setBurst(float64(burstProd), t.reserveFraction)
+		t.prod.tokens = t.prod.burst
+	}
+
+	return t
+}
+
+// WithReserve sets the fraction of the burst reserved for each higher priority lane.
+func (t *TokenBucket) WithReserve(fraction float64) *TokenBucket {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.reserveFraction = fraction
+
+	t.preview.setBurst(t.preview.burst, fraction)
+	t.prod.setBurst(t.prod.burst, fraction)
+
+	for _, b := range t.routeBuckets {
+		b.setBurst(b.burst, fraction)
+	}
+
+	return t
+}
+
+// WithRouteLimit limits requests to the endpoints under path, e.g. /patients, to rate per second
+// in each environment, on top of the environment's overall rate. An empty method matches any method.
+// When several routes match a request, the one with the longest path is used. A rate of zero or less
+// is unlimited, e.g. to exempt endpoints nested under a limited path.
+func (t *TokenBucket) WithRouteLimit(method, path string, rate int) *TokenBucket {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.routes = append(t.routes, newRouteLimit(method, path, rate))
+
+	return t
+}
+
+// WithDailyQuota limits the calls made for each practice over a rolling 24 hour window. A quota of zero is unlimited.
+func (t *TokenBucket) WithDailyQuota(quotaPreview, quotaProd int) *TokenBucket {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.quotaPreview = quotaPreview
+	t.quotaProd = quotaProd
+
+	return t
+}
+
+func (t *TokenBucket) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+	priority := PriorityFromContext(ctx)
+
+	b := t.bucket(preview)
+
+	retryAfter := b.wait(now, priority)
+	if retryAfter > 0 {
+		return retryAfter, ErrRateExceeded
+	}
+
+	b.tokens--
+
+	return 0, nil
+}
+
+func (t *TokenBucket) AllowedRoute(ctx context.Context, preview bool, practiceID, method, path string) (time.Duration, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+	priority := PriorityFromContext(ctx)
+
+	b := t.bucket(preview)
+
+	// Check every bucket before taking from any of them so a request that has to wait does not use up tokens.
+	retryAfter := b.wait(now, priority)
+
+	var rb *bucket
+	if route := matchRoute(t.routes, method, path); route != nil && route.limited() {
+		rb = t.routeBucket(preview, route)
+		retryAfter = max(retryAfter, rb.wait(now, priority))
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, ErrRateExceeded
+	}
+
+	quota := t.quotaProd
+	if preview {
+		quota = t.quotaPreview
+	}
+
+	if quota > 0 {
+		key := environment(preview) + ":" + practiceID
+
+		q, ok := t.quotas[key]
+		if !ok {
+			q = newDailyQuota()
+			t.quotas[key] = q
+		}
+
+		retryAfter, ok := q.take(now, quota)
+		if !ok {
+			return retryAfter, ErrQuotaExhausted
+		}
+	}
+
+	b.tokens--
+	if rb != nil {
+		rb.tokens--
+	}
+
+	return 0, nil
+}
+
+func (t *TokenBucket) bucket(preview bool) *bucket {
+	if preview {
+		return t.preview
+	}
+
+	return t.prod
+}
+
+func (t *TokenBucket) routeBucket(preview bool, route *routeLimit) *bucket {
+	key := environment(preview) + ":" + route.key()
+
+	b, ok := t.routeBuckets[key]
+	if !ok {
+		b = newBucket(float64(route.rate), float64(route.rate), t.reserveFraction)
+		t.routeBuckets[key] = b
+	}
+
+	return b
+}
+
+func (b *bucket) setBurst(burst, reserveFraction float64) {
+	b.burst = burst
+
+	// Always leave room for low priority requests to take a token from a full bucket.
+	b.reserve = min(burst*max(reserveFraction, 0), (burst-1)/2)
+}
+
+// wait refills the bucket and returns how long a request with the given priority has to wait for a token.
+func (b *bucket) wait(now time.Time, priority Priority) time.Duration {
+	// A bucket created during the request was updated after now, which must not take tokens from it.
+	if now.After(b.updatedAt) {
+		b.tokens = min(b.tokens+now.Sub(b.updatedAt).Seconds()*b.rate, b.burst)
+		b.updatedAt = now
+	}
+
+	// Tokens this request has to leave in the bucket for higher priority lanes.
+	reserved := b.reserve * float64(PriorityHigh-priority)
+
+	needed := 1 + reserved
+	if b.tokens < needed {
+		return time.Duration((needed - b.tokens) / b.rate * float64(time.Second))
+	}
+
+	return 0
+}
+
+func environment(preview bool) string {
+	if preview {
+		return "preview"
+	}
+
+	return "prod"
+}
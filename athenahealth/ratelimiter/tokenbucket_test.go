@@ -0,0 +1,165 @@
+package ratelimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTokenBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(0, 0)
+
+	assert.Equal(float64(defaultRatePerSecPreview), rateLimiter.preview.rate)
+	assert.Equal(float64(defaultRatePerSecProd), rateLimiter.prod.rate)
+	assert.Equal(float64(defaultRatePerSecProd), rateLimiter.prod.burst)
+}
+
+func TestTokenBucket_Allowed_burst(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(10, 10).WithBurst(3, 3).WithReserve(0)
+
+	for range 3 {
+		retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+		assert.Zero(retryAfter)
+		assert.NoError(err)
+	}
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.NotZero(retryAfter)
+	assert.LessOrEqual(retryAfter, 100*time.Millisecond)
+
+	// Environments are limited independently.
+	retryAfter, err = rateLimiter.Allowed(context.Background(), false)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+}
+
+func TestTokenBucket_Allowed_priority(t *testing.T) {
+	assert := assert.New(t)
+
+	// A reserve of 2 tokens per lane: low priority requests leave 4 tokens, normal priority requests leave 2.
+	rateLimiter := NewTokenBucket(1, 1).WithBurst(10, 10).WithReserve(0.2)
+
+	low := WithPriority(context.Background(), PriorityLow)
+	high := WithPriority(context.Background(), PriorityHigh)
+
+	allowed := func(ctx context.Context) int {
+		n := 0
+		for {
+			_, err := rateLimiter.Allowed(ctx, false)
+			if err != nil {
+				assert.ErrorIs(err, ErrRateExceeded)
+				return n
+			}
+			n++
+		}
+	}
+
+	assert.Equal(6, allowed(low))
+	assert.Equal(2, allowed(context.Background()))
+	assert.Equal(2, allowed(high))
+
+	retryAfter, err := rateLimiter.Allowed(low, false)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Greater(retryAfter, 4*time.Second)
+}
+
+func TestTokenBucket_WithReserve_low_priority_not_starved(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(1, 1).WithBurst(3, 3).WithReserve(1)
+
+	retryAfter, err := rateLimiter.Allowed(WithPriority(context.Background(), PriorityLow), false)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(PriorityNormal, PriorityFromContext(context.Background()))
+	assert.Equal(PriorityHigh, PriorityFromContext(WithPriority(context.Background(), PriorityHigh)))
+	assert.Equal(PriorityLow, PriorityFromContext(WithPriority(context.Background(), PriorityLow)))
+	assert.Equal(PriorityHigh, PriorityFromContext(WithPriority(context.Background(), Priority(5))))
+}
+
+func TestTokenBucket_AllowedRoute_route_limit(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(100, 100).WithReserve(0).WithRouteLimit("", "/patients", 2)
+
+	for range 2 {
+		retryAfter, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:")
+		assert.Zero(retryAfter)
+		assert.NoError(err)
+	}
+
+	retryAfter, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:")
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Greater(retryAfter, 400*time.Millisecond)
+
+	// Other endpoints only share the environment's rate.
+	retryAfter, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/appointments/booked")
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	// A request that has to wait does not take a token from the environment's bucket.
+	assert.InDelta(97, rateLimiter.prod.tokens, 0.5)
+}
+
+func TestTokenBucket_AllowedRoute_unlimited_route(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(100, 100).WithReserve(0).
+		WithRouteLimit("", "/patients", 1).
+		WithRouteLimit("", "/patients/:id:/documents", 0)
+
+	// A route with a rate of zero exempts its endpoints from the route they are nested under.
+	for range 5 {
+		retryAfter, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:/documents/admin")
+		assert.Zero(retryAfter)
+		assert.NoError(err)
+	}
+
+	_, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:")
+	assert.NoError(err)
+
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients/:id:")
+	assert.ErrorIs(err, ErrRateExceeded)
+}
+
+func TestTokenBucket_AllowedRoute_daily_quota(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(100, 100).WithDailyQuota(0, 2)
+
+	for range 2 {
+		_, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients")
+		assert.NoError(err)
+	}
+
+	retryAfter, err := rateLimiter.AllowedRoute(context.Background(), false, "1", "GET", "/patients")
+	assert.ErrorIs(err, ErrQuotaExhausted)
+	assert.Greater(retryAfter, 23*time.Hour)
+
+	// Quotas are per practice and environment.
+	_, err = rateLimiter.AllowedRoute(context.Background(), false, "2", "GET", "/patients")
+	assert.NoError(err)
+
+	for range 5 {
+		_, err = rateLimiter.AllowedRoute(context.Background(), true, "1", "GET", "/patients")
+		assert.NoError(err)
+	}
+}
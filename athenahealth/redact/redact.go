@@ -0,0 +1,233 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces values that may contain PHI.
+const Redacted = "[REDACTED]"
+
+// DefaultDenied are the query parameters, form fields and JSON fields redacted by default.
+var DefaultDenied = []string{
+	"address1",
+	"address2",
+	"altfirstname",
+	"anyphone",
+	"city",
+	"contacthomephone",
+	"contactmobilephone",
+	"contactname",
+	"customfieldvalue",
+	"dob",
+	"email",
+	"enterpriseid",
+	"firstname",
+	"guarantoraddress1",
+	"guarantoraddress2",
+	"guarantorcity",
+	"guarantordob",
+	"guarantoremail",
+	"guarantorfirstname",
+	"guarantorlastname",
+	"guarantormiddlename",
+	"guarantorphone",
+	"guarantorssn",
+	"guarantorzip",
+	"guardianfirstname",
+	"guardianlastname",
+	"guardianmiddlename",
+	"homephone",
+	"insuranceidnumber",
+	"insurancepolicyholder",
+	"insurancepolicyholderaddress1",
+	"insurancepolicyholderaddress2",
+	"insurancepolicyholdercity",
+	"insurancepolicyholderdob",
+	"insurancepolicyholderfirstname",
+	"insurancepolicyholderlastname",
+	"insurancepolicyholdermiddlename",
+	"insurancepolicyholderssn",
+	"insurancepolicyholderzip",
+	"insuredaddress",
+	"insuredaddress2",
+	"insuredcity",
+	"insureddob",
+	"insuredfirstname",
+	"insuredlastname",
+	"insuredmiddlename",
+	"insuredssn",
+	"insuredzip",
+	"lastemail",
+	"lastname",
+	"localpatientid",
+	"middlename",
+	"mobile",
+	"mobilephone",
+	"name",
+	"nextkinname",
+	"nextkinphone",
+	"notes",
+	"patientid",
+	"preferredname",
+	"previouslastname",
+	"searchvalue",
+	"sex",
+	"ssn",
+	"suffix",
+	"workphone",
+	"zip",
+}
+
+var idRegex = regexp.MustCompile(`(^|/)[^/]*\d[^/]*`)
+
+// customFieldValueRegex matches the custom field value in /patients/customfields/{customfieldid}/{customfieldvalue}.
+var customFieldValueRegex = regexp.MustCompile(`(/patients/customfields/[^/]+/)[^/]+`)
+
+// Redactor removes PHI from URLs and bodies so they can be logged. Path segments containing
+// digits (e.g. patient IDs) are replaced with :id:, and the values of denied query parameters,
+// form fields and JSON fields are replaced with Redacted.
+type Redactor struct {
+	denied  map[string]bool
+	allowed map[string]bool
+}
+
+func New() *Redactor {
+	r := &Redactor{
+		denied:  make(map[string]bool),
+		allowed: make(map[string]bool),
+	}
+
+	return r.WithDenied(DefaultDenied...)
+}
+
+// WithDenied redacts the values of the given query parameters, form fields and JSON fields in addition to DefaultDenied.
+func (r *Redactor) WithDenied(keys ...string) *Redactor {
+	for _, k := range keys {
+		r.denied[strings.ToLower(k)] = true
+	}
+
+	return r
+}
+
+// WithAllowed logs the values of the given query parameters, form fields and JSON fields, even if they are denied.
+func (r *Redactor) WithAllowed(keys ...string) *Redactor {
+	for _, k := range keys {
+		r.allowed[strings.ToLower(k)] = true
+	}
+
+	return r
+}
+
+func (r *Redactor) redacts(key string) bool {
+	key = strings.ToLower(key)
+
+	return r.denied[key] && !r.allowed[key]
+}
+
+func (r *Redactor) URL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Redacted
+	}
+
+	// Keep the version and practice ID, e.g. /v1/195900, which are not PHI.
+	prefix := ""
+	path := u.Path
+
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) >= 2 && parts[0] == "v1" {
+		prefix = "/" + parts[0] + "/" + parts[1]
+		path = strings.TrimPrefix(path, prefix)
+	}
+
+	path = customFieldValueRegex.ReplaceAllString(path, "$1"+Redacted)
+	path = idRegex.ReplaceAllString(path, "$1:id:")
+
+	s := prefix + path
+	if len(u.Host) > 0 {
+		s = u.Scheme + "://" + u.Host + s
+	}
+
+	if len(u.RawQuery) > 0 {
+		s += "?" + r.encode(u.Query())
+	}
+
+	return s
+}
+
+// Body returns a copy of a JSON or form encoded body with denied fields redacted. Other bodies,
+// and bodies that cannot be parsed, are omitted.
+func (r *Redactor) Body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			break
+		}
+
+		return r.encode(values)
+
+	case "application/json", "":
+		var v interface{}
+
+		err := json.Unmarshal(body, &v)
+		if err != nil {
+			break
+		}
+
+		b, err := json.Marshal(r.json(v))
+		if err != nil {
+			break
+		}
+
+		return string(b)
+	}
+
+	return fmt.Sprintf("[%d bytes omitted]", len(body))
+}
+
+// encode encodes values with denied values redacted.
+func (r *Redactor) encode(values url.Values) string {
+	redacted := make(url.Values, len(values))
+
+	for k, v := range values {
+		if r.redacts(k) {
+			redacted[k] = []string{Redacted}
+		} else {
+			redacted[k] = v
+		}
+	}
+
+	return strings.ReplaceAll(redacted.Encode(), url.QueryEscape(Redacted), Redacted)
+}
+
+func (r *Redactor) json(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fieldValue := range v {
+			if r.redacts(k) {
+				v[k] = Redacted
+			} else {
+				v[k] = r.json(fieldValue)
+			}
+		}
+
+	case []interface{}:
+		for i := range v {
+			v[i] = r.json(v[i])
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,71 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_URL(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New()
+
+	assert.Equal("https://api.preview.platform.athenahealth.com/v1/195900/patients?departmentid=1&firstname=[REDACTED]&lastname=[REDACTED]&limit=10",
+		r.URL("https://api.preview.platform.athenahealth.com/v1/195900/patients?firstname=Jane&lastname=Doe&departmentid=1&limit=10"))
+
+	assert.Equal("https://api.platform.athenahealth.com/v1/195900/patients/:id:/documents/:id:",
+		r.URL("https://api.platform.athenahealth.com/v1/195900/patients/123/documents/456"))
+
+	assert.Equal("/v1/1/patients/customfields/:id:/[REDACTED]",
+		r.URL("/v1/1/patients/customfields/1/Jane%20Doe"))
+
+	assert.Equal("/patients/:id:?showinsurance=true", r.URL("/patients/123?showinsurance=true"))
+
+	assert.Equal("/patients/:id:", r.URL("/patients/MRN123"))
+}
+
+func TestRedactor_WithAllowed_WithDenied(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New().WithAllowed("lastname").WithDenied("departmentid")
+
+	assert.Equal("/patients?departmentid=[REDACTED]&firstname=[REDACTED]&lastname=Doe",
+		r.URL("/patients?firstname=Jane&lastname=Doe&departmentid=1"))
+}
+
+func TestRedactor_Body_json(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New()
+
+	body := r.Body("application/json; charset=utf-8", []byte(`{
+		"patients": [{"patientid": "1", "firstname": "Jane", "dob": "01/01/1980", "ssn": "123-45-6789", "guarantorssn": "987-65-4321", "status": "active"}],
+		"totalcount": 1
+	}`))
+
+	assert.JSONEq(`{
+		"patients": [{"patientid": "[REDACTED]", "firstname": "[REDACTED]", "dob": "[REDACTED]", "ssn": "[REDACTED]", "guarantorssn": "[REDACTED]", "status": "active"}],
+		"totalcount": 1
+	}`, body)
+}
+
+func TestRedactor_Body_form(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New()
+
+	body := r.Body("application/x-www-form-urlencoded", []byte("departmentid=1&dob=01%2F01%2F1980&ssn=123-45-6789"))
+
+	assert.Equal("departmentid=1&dob=[REDACTED]&ssn=[REDACTED]", body)
+}
+
+func TestRedactor_Body_omitted(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New()
+
+	assert.Equal("[10 bytes omitted]", r.Body("image/jpeg", []byte("0123456789")))
+	assert.Equal("[9 bytes omitted]", r.Body("application/json", []byte(`{"ssn": "`)))
+	assert.Empty(r.Body("application/json", nil))
+}
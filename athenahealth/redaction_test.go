@@ -0,0 +1,290 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/redact"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/retrypolicy"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPHIPatientsResponse = `{
+	"patients": [{
+		"patientid": "98765",
+		"firstname": "Jane",
+		"lastname": "Doe",
+		"dob": "01/01/1980",
+		"ssn": "123-45-6789",
+		"guarantorssn": "987-65-4321",
+		"email": "jane.doe@example.com",
+		"homephone": "5555550100",
+		"appointmentid": "1"
+	}],
+	"totalcount": 1
+}`
+
+var testPHI = []string{
+	"98765",
+	"Jane",
+	"Doe",
+	"1980",
+	"123-45-6789",
+	"987-65-4321",
+	"jane.doe@example.com",
+	"5555550100",
+	"MRN-4242",
+}
+
+// testGetPatientPHI are the values in resources/GetPatient.json that identify the patient, their
+// guarantor or the insured.
+var testGetPatientPHI = []string{
+	"jason",
+	"mike",
+	"smith",
+	"foo",
+	"01/15/1985",
+	"15/04/1995",
+	"111223333",
+	"@eleanorhealth.com",
+	"8605544444",
+	"8605555555",
+	"8605555544",
+	"8608105503",
+	"8608183849",
+	"100 main st",
+	"200 two hundred st",
+	"boston",
+	"02210",
+	"12345",
+}
+
+func TestRedactor_Body_GetPatient(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := os.ReadFile("./resources/GetPatient.json")
+	assert.NoError(err)
+
+	redacted := strings.ToLower(redact.New().Body("application/json", b))
+
+	assert.Contains(redacted, "aetna")
+	for _, phi := range testGetPatientPHI {
+		assert.NotContains(redacted, phi)
+	}
+
+	var patients []map[string]interface{}
+	assert.NoError(json.Unmarshal([]byte(redacted), &patients))
+
+	for _, patient := range patients {
+		assert.Equal(strings.ToLower(redact.Redacted), patient["localpatientid"])
+		assert.NotEqual(strings.ToLower(redact.Redacted), patient["departmentid"])
+	}
+}
+
+func testLoggingClient(h http.HandlerFunc) (*HTTPClient, *bytes.Buffer, func()) {
+	athenaClient, ts := testClient(h)
+
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf).Level(zerolog.DebugLevel)
+
+	athenaClient.WithLogger(&logger).WithBodyLogging(true)
+
+	return athenaClient, buf, ts.Close
+}
+
+func TestHTTPClient_logging_redacts_PHI(t *testing.T) {
+	testCases := []struct {
+		name string
+		call func(*HTTPClient) error
+	}{
+		{
+			name: "ListPatients",
+			call: func(c *HTTPClient) error {
+				_, err := c.ListPatients(context.Background(), &ListPatientsOptions{
+					FirstName: "Jane",
+					LastName:  "Doe",
+				})
+				return err
+			},
+		},
+		{
+			name: "GetPatients",
+			call: func(c *HTTPClient) error {
+				_, err := c.GetPatients(context.Background(), "98765", &GetPatientOptions{ShowInsurance: true})
+				return err
+			},
+		},
+		{
+			name: "ListPatientsMatchingCustomField",
+			call: func(c *HTTPClient) error {
+				_, err := c.ListPatientsMatchingCustomField(context.Background(), &ListPatientsMatchingCustomFieldOptions{
+					CustomFieldID:    "1",
+					CustomFieldValue: "MRN-4242",
+				})
+				return err
+			},
+		},
+		{
+			name: "ListChangedPatients",
+			call: func(c *HTTPClient) error {
+				_, err := c.ListChangedPatients(context.Background(), &ListChangedPatientOptions{
+					PatientID: "98765",
+				})
+				return err
+			},
+		},
+		{
+			name: "ListBookedAppointments",
+			call: func(c *HTTPClient) error {
+				_, err := c.ListBookedAppointments(context.Background(), &ListBookedAppointmentsOptions{
+					PatientID: "98765",
+				})
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			h := func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(testPHIPatientsResponse))
+			}
+
+			athenaClient, buf, closeServer := testLoggingClient(h)
+			defer closeServer()
+
+			// Only logging is under test; the response may not match the endpoint.
+			//nolint
+			tc.call(athenaClient)
+
+			logs := buf.String()
+
+			assert.Contains(logs, "athenahealth API bodies")
+			assert.Contains(logs, redact.Redacted)
+
+			for _, phi := range testPHI {
+				assert.NotContains(logs, phi)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_logging_redacts_retried_error(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, buf, closeServer := testLoggingClient(nil)
+	defer closeServer()
+
+	calls := 0
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(2, time.Millisecond, time.Millisecond)).
+		WithMiddleware(func(next RoundTrip) RoundTrip {
+			return func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: syscall.ECONNRESET}
+				}
+
+				return next(req)
+			}
+		})
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/patients/search?firstname=Jane&lastname=Doe", nil, nil, nil)
+	assert.NoError(err)
+	assert.Equal(2, calls)
+
+	assert.Contains(buf.String(), "athenahealth API request retrying")
+	assert.Contains(buf.String(), syscall.ECONNRESET.Error())
+	assert.NotContains(buf.String(), "Jane")
+	assert.NotContains(buf.String(), "Doe")
+}
+
+func TestHTTPClient_logging_redacts_detailed_message(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error": "Could not register the patient.", "detailedmessage": "A patient named Jane Doe born 01/01/1980 already exists."}`))
+	}
+
+	athenaClient, buf, closeServer := testLoggingClient(h)
+	defer closeServer()
+
+	athenaClient.WithRetryPolicy(retrypolicy.NewExponential(2, time.Millisecond, time.Millisecond).WithMethods(http.MethodPost))
+
+	_, err := athenaClient.PostForm(context.Background(), "/patients", url.Values{"firstname": {"Jane"}}, nil)
+	assert.Error(err)
+
+	assert.Contains(buf.String(), "athenahealth API error")
+	assert.Contains(buf.String(), "athenahealth API request retrying")
+	assert.Contains(buf.String(), "Could not register the patient.")
+	assert.NotContains(buf.String(), "Jane")
+	assert.NotContains(buf.String(), "1980")
+
+	// The error returned to the caller keeps the detailed message.
+	assert.Contains(err.Error(), "Jane Doe")
+}
+
+func TestHTTPClient_logging_redacts_request_body(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, buf, closeServer := testLoggingClient(nil)
+	defer closeServer()
+
+	_, err := athenaClient.PostForm(context.Background(), "/patients", url.Values{
+		"firstname":    {"Jane"},
+		"ssn":          {"123-45-6789"},
+		"departmentid": {"1"},
+	}, nil)
+	assert.NoError(err)
+
+	logs := buf.String()
+
+	assert.Contains(logs, "departmentid=1")
+	assert.NotContains(logs, "Jane")
+	assert.NotContains(logs, "123-45-6789")
+}
+
+func TestHTTPClient_WithBodyLogging_disabled(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, buf, closeServer := testLoggingClient(nil)
+	defer closeServer()
+
+	athenaClient.WithBodyLogging(false)
+
+	_, err := athenaClient.Get(context.Background(), "/patients", nil, nil)
+	assert.NoError(err)
+
+	assert.Contains(buf.String(), "athenahealth API response")
+	assert.NotContains(buf.String(), "athenahealth API bodies")
+}
+
+func TestHTTPClient_WithRedactor(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, buf, closeServer := testLoggingClient(nil)
+	defer closeServer()
+
+	athenaClient.WithRedactor(redact.New().WithAllowed("lastname"))
+
+	_, err := athenaClient.ListPatients(context.Background(), &ListPatientsOptions{
+		FirstName: "Jane",
+		LastName:  "Doe",
+	})
+	assert.NoError(err)
+
+	assert.NotContains(buf.String(), "Jane")
+	assert.Contains(buf.String(), "lastname=Doe")
+}
@@ -0,0 +1,21 @@
+package retrypolicy
+
+import (
+	"net/http"
+	"time"
+)
+
+type Default struct {
+}
+
+func NewDefault() *Default {
+	return &Default{}
+}
+
+func (d *Default) Retryable(method string) bool {
+	return false
+}
+
+func (d *Default) Retry(attempt int, res *http.Response, err error) (time.Duration, bool) {
+	return 0, false
+}
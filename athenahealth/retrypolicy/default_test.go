@@ -0,0 +1,26 @@
+package retrypolicy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault_Retryable(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewDefault()
+
+	assert.False(policy.Retryable(http.MethodGet))
+}
+
+func TestDefault_Retry(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewDefault()
+
+	retryAfter, retry := policy.Retry(1, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	assert.Zero(retryAfter)
+	assert.False(retry)
+}
@@ -0,0 +1,128 @@
+package retrypolicy
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const defaultMaxAttempts = 3
+const defaultBaseDelay = 100 * time.Millisecond
+const defaultMaxDelay = 5 * time.Second
+
+// Exponential retries GET requests (and any methods opted in with WithMethods) that
+// fail with a connection reset, a 429 or a 502/503/504, waiting an exponentially
+// increasing, fully jittered delay between attempts. A Retry-After header on the
+// response takes precedence over the computed delay, but is capped at the max delay.
+type Exponential struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	methods map[string]bool
+}
+
+func NewExponential(maxAttempts int, baseDelay, maxDelay time.Duration) *Exponential {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	if baseDelay <= 0 {
+		baseDelay = defaultBaseDelay
+	}
+
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelay
+	}
+
+	return &Exponential{
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		maxDelay:    maxDelay,
+
+		methods: map[string]bool{
+			http.MethodGet: true,
+		},
+	}
+}
+
+// WithMethods opts additional idempotent methods, such as PUT and DELETE, in to retries.
+func (e *Exponential) WithMethods(methods ...string) *Exponential {
+	for _, method := range methods {
+		e.methods[method] = true
+	}
+
+	return e
+}
+
+func (e *Exponential) Retryable(method string) bool {
+	return e.methods[method]
+}
+
+func (e *Exponential) Retry(attempt int, res *http.Response, err error) (time.Duration, bool) {
+	if attempt >= e.maxAttempts {
+		return 0, false
+	}
+
+	if res != nil {
+		switch res.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return min(retryAfter, e.maxDelay), true
+		}
+	} else if !isConnectionReset(err) {
+		return 0, false
+	}
+
+	return e.backoff(attempt), true
+}
+
+func (e *Exponential) backoff(attempt int) time.Duration {
+	delay := e.maxDelay
+
+	// Guard against overflowing the shift for large attempt counts.
+	if attempt < 32 {
+		delay = min(e.baseDelay<<(attempt-1), e.maxDelay)
+	}
+
+	return rand.N(delay + 1)
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+func isConnectionReset(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,160 @@
+package retrypolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExponential(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(0, 0, 0)
+
+	assert.Equal(defaultMaxAttempts, policy.maxAttempts)
+	assert.Equal(defaultBaseDelay, policy.baseDelay)
+	assert.Equal(defaultMaxDelay, policy.maxDelay)
+}
+
+func TestExponential_Retryable(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(3, time.Millisecond, time.Second)
+
+	assert.True(policy.Retryable(http.MethodGet))
+	assert.False(policy.Retryable(http.MethodPost))
+	assert.False(policy.Retryable(http.MethodPut))
+	assert.False(policy.Retryable(http.MethodDelete))
+
+	policy.WithMethods(http.MethodPut, http.MethodDelete)
+
+	assert.True(policy.Retryable(http.MethodPut))
+	assert.True(policy.Retryable(http.MethodDelete))
+	assert.False(policy.Retryable(http.MethodPost))
+}
+
+func TestExponential_Retry_statusCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(3, time.Millisecond, time.Second)
+
+	for _, statusCode := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		retryAfter, retry := policy.Retry(1, &http.Response{StatusCode: statusCode}, errors.New("api error"))
+		assert.True(retry, statusCode)
+		assert.LessOrEqual(retryAfter, time.Millisecond)
+	}
+
+	for _, statusCode := range []int{http.StatusOK, http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError} {
+		_, retry := policy.Retry(1, &http.Response{StatusCode: statusCode}, nil)
+		assert.False(retry, statusCode)
+	}
+}
+
+func TestExponential_Retry_errors(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(3, time.Millisecond, time.Second)
+
+	for _, err := range []error{
+		&url.Error{Op: "Get", URL: "/", Err: syscall.ECONNRESET},
+		fmt.Errorf("read: %w", syscall.EPIPE),
+		io.EOF,
+		io.ErrUnexpectedEOF,
+	} {
+		_, retry := policy.Retry(1, nil, err)
+		assert.True(retry, err.Error())
+	}
+
+	for _, err := range []error{
+		&url.Error{Op: "Get", URL: "/", Err: context.DeadlineExceeded},
+		errors.New("unknown"),
+		nil,
+	} {
+		_, retry := policy.Retry(1, nil, err)
+		assert.False(retry)
+	}
+}
+
+func TestExponential_Retry_maxAttempts(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(3, time.Millisecond, time.Second)
+	res := &http.Response{StatusCode: http.StatusServiceUnavailable}
+
+	_, retry := policy.Retry(2, res, nil)
+	assert.True(retry)
+
+	_, retry = policy.Retry(3, res, nil)
+	assert.False(retry)
+}
+
+func TestExponential_Retry_backoff(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(100, 10*time.Millisecond, 50*time.Millisecond)
+	res := &http.Response{StatusCode: http.StatusServiceUnavailable}
+
+	for attempt := 1; attempt < 100; attempt++ {
+		retryAfter, retry := policy.Retry(attempt, res, nil)
+		assert.True(retry)
+		assert.GreaterOrEqual(retryAfter, time.Duration(0))
+		assert.LessOrEqual(retryAfter, min(10*time.Millisecond<<(min(attempt, 32)-1), 50*time.Millisecond))
+	}
+}
+
+func TestExponential_Retry_RetryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(3, time.Millisecond, 2*time.Minute)
+
+	res := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"2"}},
+	}
+
+	retryAfter, retry := policy.Retry(1, res, nil)
+	assert.True(retry)
+	assert.Equal(2*time.Second, retryAfter)
+
+	res.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+	retryAfter, retry = policy.Retry(1, res, nil)
+	assert.True(retry)
+	assert.Greater(retryAfter, 58*time.Second)
+	assert.LessOrEqual(retryAfter, time.Minute)
+
+	res.Header.Set("Retry-After", "soon")
+
+	retryAfter, retry = policy.Retry(1, res, nil)
+	assert.True(retry)
+	assert.LessOrEqual(retryAfter, time.Millisecond)
+}
+
+func TestExponential_Retry_RetryAfter_maxDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := NewExponential(3, time.Millisecond, 5*time.Second)
+
+	res := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": {"86400"}},
+	}
+
+	retryAfter, retry := policy.Retry(1, res, nil)
+	assert.True(retry)
+	assert.Equal(5*time.Second, retryAfter)
+
+	res.Header.Set("Retry-After", time.Now().Add(24*time.Hour).UTC().Format(http.TimeFormat))
+
+	retryAfter, retry = policy.Retry(1, res, nil)
+	assert.True(retry)
+	assert.Equal(5*time.Second, retryAfter)
+}
@@ -1,14 +1,13 @@
 package stats
 
 import (
-	"net/url"
-	"regexp"
+	"errors"
+	"strconv"
 
 	"github.com/DataDog/datadog-go/statsd"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
 )
 
-var idRegex = regexp.MustCompile(`(/)(\d+)(/?)`)
-
 type Datadog struct {
 	client statsd.ClientInterface
 }
@@ -36,11 +35,32 @@ func (d *Datadog) ResponseError() error {
 	return d.client.Incr("athenahealth.responses.error", []string{}, 1.0)
 }
 
-func cleanPath(path string) string {
-	u, err := url.Parse(path)
-	if err != nil {
-		return ""
+func (d *Datadog) TokenRefreshSuccess() error {
+	return d.client.Incr("athenahealth.token_refresh.success", []string{}, 1.0)
+}
+
+func (d *Datadog) TokenRefreshError() error {
+	return d.client.Incr("athenahealth.token_refresh.error", []string{}, 1.0)
+}
+
+func (d *Datadog) Response(res *Response) error {
+	tags := []string{
+		"http_method:" + res.Method,
+		"http_path:" + res.Path,
+		"http_status_code:" + strconv.Itoa(res.StatusCode),
+		"http_status_class:" + res.StatusClass(),
 	}
 
-	return idRegex.ReplaceAllString(u.Path, "$1:id:$3")
+	return errors.Join(
+		d.client.Incr("athenahealth.responses", tags, 1.0),
+		d.client.Timing("athenahealth.response.duration", res.Duration, tags, 1.0),
+		d.client.Histogram("athenahealth.request.bytes", float64(res.RequestBytes), tags, 1.0),
+		d.client.Histogram("athenahealth.response.bytes", float64(res.ResponseBytes), tags, 1.0),
+		d.client.Histogram("athenahealth.request.retries", float64(res.Retries), tags, 1.0),
+		d.client.Timing("athenahealth.rate_limit.wait", res.RateLimitWait, tags, 1.0),
+	)
+}
+
+func cleanPath(path string) string {
+	return pathutil.Normalize(path)
 }
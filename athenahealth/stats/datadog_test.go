@@ -2,6 +2,7 @@ package stats
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
 	"github.com/stretchr/testify/assert"
@@ -9,13 +10,23 @@ import (
 
 type mockClient struct {
 	statsd.ClientInterface
-	incrFn func(name string, tags []string, rate float64) error
+	incrFn      func(name string, tags []string, rate float64) error
+	timingFn    func(name string, value time.Duration, tags []string, rate float64) error
+	histogramFn func(name string, value float64, tags []string, rate float64) error
 }
 
 func (m *mockClient) Incr(name string, tags []string, rate float64) error {
 	return m.incrFn(name, tags, rate)
 }
 
+func (m *mockClient) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	return m.timingFn(name, value, tags, rate)
+}
+
+func (m *mockClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	return m.histogramFn(name, value, tags, rate)
+}
+
 func TestDatadog_Request(t *testing.T) {
 	assert := assert.New(t)
 
@@ -33,6 +44,84 @@ func TestDatadog_Request(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestDatadog_TokenRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &mockClient{}
+
+	var names []string
+	client.incrFn = func(name string, tags []string, rate float64) error {
+		names = append(names, name)
+		return nil
+	}
+
+	datadog := NewDatadog(client)
+
+	assert.NoError(datadog.TokenRefreshSuccess())
+	assert.NoError(datadog.TokenRefreshError())
+
+	assert.Equal([]string{"athenahealth.token_refresh.success", "athenahealth.token_refresh.error"}, names)
+}
+
+func TestDatadog_Response(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &mockClient{}
+
+	expectedTags := []string{
+		"http_method:GET",
+		"http_path:/patients/:id:",
+		"http_status_code:404",
+		"http_status_class:4xx",
+	}
+
+	counters := map[string]bool{}
+	client.incrFn = func(name string, tags []string, rate float64) error {
+		assert.Equal(expectedTags, tags)
+		counters[name] = true
+		return nil
+	}
+
+	timings := map[string]time.Duration{}
+	client.timingFn = func(name string, value time.Duration, tags []string, rate float64) error {
+		assert.Equal(expectedTags, tags)
+		timings[name] = value
+		return nil
+	}
+
+	histograms := map[string]float64{}
+	client.histogramFn = func(name string, value float64, tags []string, rate float64) error {
+		assert.Equal(expectedTags, tags)
+		histograms[name] = value
+		return nil
+	}
+
+	datadog := NewDatadog(client)
+
+	err := datadog.Response(&Response{
+		Method:        "GET",
+		Path:          "/patients/:id:",
+		StatusCode:    404,
+		Duration:      time.Second,
+		RequestBytes:  10,
+		ResponseBytes: 20,
+		Retries:       2,
+		RateLimitWait: time.Millisecond,
+	})
+	assert.NoError(err)
+
+	assert.True(counters["athenahealth.responses"])
+	assert.Equal(map[string]time.Duration{
+		"athenahealth.response.duration": time.Second,
+		"athenahealth.rate_limit.wait":   time.Millisecond,
+	}, timings)
+	assert.Equal(map[string]float64{
+		"athenahealth.request.bytes":   10,
+		"athenahealth.response.bytes":  20,
+		"athenahealth.request.retries": 2,
+	}, histograms)
+}
+
 func TestRemoveIDsFromPath(t *testing.T) {
 	assert := assert.New(t)
 
@@ -18,3 +18,15 @@ func (d *Default) ResponseSuccess() error {
 func (d *Default) ResponseError() error {
 	return nil
 }
+
+func (d *Default) TokenRefreshSuccess() error {
+	return nil
+}
+
+func (d *Default) TokenRefreshError() error {
+	return nil
+}
+
+func (d *Default) Response(res *Response) error {
+	return nil
+}
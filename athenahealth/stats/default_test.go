@@ -29,3 +29,27 @@ func TestDefault_ResponseError(t *testing.T) {
 	err := stats.ResponseError()
 	assert.NoError(err)
 }
+
+func TestDefault_TokenRefreshSuccess(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := NewDefault()
+	err := stats.TokenRefreshSuccess()
+	assert.NoError(err)
+}
+
+func TestDefault_TokenRefreshError(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := NewDefault()
+	err := stats.TokenRefreshError()
+	assert.NoError(err)
+}
+
+func TestDefault_Response(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := NewDefault()
+	err := stats.Response(&Response{})
+	assert.NoError(err)
+}
@@ -0,0 +1,138 @@
+// Package otelstats records athenahealth client metrics with OpenTelemetry.
+package otelstats
+
+import (
+	"context"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/eleanorhealth/go-athenahealth/athenahealth"
+
+// Stats records metrics with a caller supplied OpenTelemetry meter provider. Requests and
+// responses are recorded with their method and path, with numeric IDs replaced by :id:, and
+// responses with their status code.
+type Stats struct {
+	requests         metric.Int64Counter
+	responseDuration metric.Float64Histogram
+	requestBytes     metric.Int64Histogram
+	responseBytes    metric.Int64Histogram
+	retries          metric.Int64Counter
+	rateLimitWait    metric.Float64Histogram
+	tokenRefreshes   metric.Int64Counter
+}
+
+func New(meterProvider metric.MeterProvider) (*Stats, error) {
+	if meterProvider == nil {
+		panic("meterProvider is nil")
+	}
+
+	meter := meterProvider.Meter(meterName)
+
+	o := &Stats{}
+
+	var err error
+
+	o.requests, err = meter.Int64Counter("athenahealth.requests",
+		metric.WithDescription("Requests sent to the athenahealth API."))
+	if err != nil {
+		return nil, err
+	}
+
+	o.responseDuration, err = meter.Float64Histogram("athenahealth.response.duration",
+		metric.WithDescription("Time taken for the athenahealth API to respond."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	o.requestBytes, err = meter.Int64Histogram("athenahealth.request.size",
+		metric.WithDescription("Size of request bodies sent to the athenahealth API."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	o.responseBytes, err = meter.Int64Histogram("athenahealth.response.size",
+		metric.WithDescription("Size of response bodies received from the athenahealth API."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	o.retries, err = meter.Int64Counter("athenahealth.retries",
+		metric.WithDescription("Responses to retried attempts of requests to the athenahealth API."))
+	if err != nil {
+		return nil, err
+	}
+
+	o.rateLimitWait, err = meter.Float64Histogram("athenahealth.rate_limit.wait",
+		metric.WithDescription("Time requests to the athenahealth API waited for the rate limiter."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	o.tokenRefreshes, err = meter.Int64Counter("athenahealth.token_refreshes",
+		metric.WithDescription("Background token refreshes."))
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (o *Stats) Request(method, path string) error {
+	o.requests.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("url.template", pathutil.Normalize(path)),
+	))
+
+	return nil
+}
+
+// ResponseSuccess is a no-op. Responses are recorded with their attributes by Response.
+func (o *Stats) ResponseSuccess() error {
+	return nil
+}
+
+// ResponseError is a no-op. Responses are recorded with their attributes by Response.
+func (o *Stats) ResponseError() error {
+	return nil
+}
+
+func (o *Stats) TokenRefreshSuccess() error {
+	o.tokenRefreshes.Add(context.Background(), 1, metric.WithAttributes(attribute.String("result", "success")))
+
+	return nil
+}
+
+func (o *Stats) TokenRefreshError() error {
+	o.tokenRefreshes.Add(context.Background(), 1, metric.WithAttributes(attribute.String("result", "error")))
+
+	return nil
+}
+
+func (o *Stats) Response(res *stats.Response) error {
+	ctx := context.Background()
+
+	route := attribute.NewSet(
+		attribute.String("http.request.method", res.Method),
+		attribute.String("url.template", pathutil.Normalize(res.Path)),
+	)
+	attrs := metric.WithAttributes(append(route.ToSlice(), attribute.Int("http.response.status_code", res.StatusCode))...)
+
+	o.responseDuration.Record(ctx, res.Duration.Seconds(), attrs)
+	o.requestBytes.Record(ctx, res.RequestBytes, attrs)
+	o.responseBytes.Record(ctx, res.ResponseBytes, attrs)
+	o.rateLimitWait.Record(ctx, res.RateLimitWait.Seconds(), metric.WithAttributeSet(route))
+
+	if res.Retries > 0 {
+		o.retries.Add(ctx, 1, attrs)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package otelstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collectMetrics(assert *assert.Assertions, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	rm := metricdata.ResourceMetrics{}
+	assert.NoError(reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+
+	return metrics
+}
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+
+	reader := sdkmetric.NewManualReader()
+
+	otelStats, err := New(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	assert.NoError(err)
+
+	assert.NoError(otelStats.Request("GET", "/patients/123"))
+	assert.NoError(otelStats.Request("GET", "/patients/456"))
+	assert.NoError(otelStats.TokenRefreshError())
+
+	assert.NoError(otelStats.Response(&stats.Response{
+		Method:        "GET",
+		Path:          "/patients/:id:",
+		StatusCode:    503,
+		Duration:      time.Second,
+		ResponseBytes: 100,
+		Retries:       1,
+	}))
+
+	metrics := collectMetrics(assert, reader)
+
+	requests := metrics["athenahealth.requests"].Data.(metricdata.Sum[int64])
+	assert.Len(requests.DataPoints, 1)
+	assert.Equal(int64(2), requests.DataPoints[0].Value)
+
+	path, _ := requests.DataPoints[0].Attributes.Value("url.template")
+	assert.Equal("/patients/:id:", path.AsString())
+
+	duration := metrics["athenahealth.response.duration"].Data.(metricdata.Histogram[float64])
+	assert.Len(duration.DataPoints, 1)
+	assert.Equal(uint64(1), duration.DataPoints[0].Count)
+	assert.Equal(float64(1), duration.DataPoints[0].Sum)
+
+	statusCode, _ := duration.DataPoints[0].Attributes.Value(attribute.Key("http.response.status_code"))
+	assert.Equal(int64(503), statusCode.AsInt64())
+
+	retries := metrics["athenahealth.retries"].Data.(metricdata.Sum[int64])
+	assert.Equal(int64(1), retries.DataPoints[0].Value)
+
+	tokenRefreshes := metrics["athenahealth.token_refreshes"].Data.(metricdata.Sum[int64])
+	result, _ := tokenRefreshes.DataPoints[0].Attributes.Value("result")
+	assert.Equal("error", result.AsString())
+}
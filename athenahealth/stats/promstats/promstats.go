@@ -0,0 +1,132 @@
+// Package promstats records athenahealth client metrics with Prometheus.
+package promstats
+
+import (
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stats records metrics on a caller supplied Prometheus registry. Requests and responses are
+// labelled by method and path, with numeric IDs replaced by :id:, and responses by status class, e.g. 2xx.
+type Stats struct {
+	requests         *prometheus.CounterVec
+	responses        *prometheus.CounterVec
+	responseDuration *prometheus.HistogramVec
+	requestBytes     *prometheus.HistogramVec
+	responseBytes    *prometheus.HistogramVec
+	retries          *prometheus.CounterVec
+	rateLimitWait    *prometheus.HistogramVec
+	tokenRefreshes   *prometheus.CounterVec
+}
+
+func New(registerer prometheus.Registerer) (*Stats, error) {
+	if registerer == nil {
+		panic("registerer is nil")
+	}
+
+	responseLabels := []string{"method", "path", "status_class"}
+	sizeBuckets := prometheus.ExponentialBuckets(256, 4, 8)
+
+	p := &Stats{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "athenahealth_requests_total",
+			Help: "Requests sent to the athenahealth API.",
+		}, []string{"method", "path"}),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "athenahealth_responses_total",
+			Help: "Responses received from the athenahealth API.",
+		}, responseLabels),
+		responseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "athenahealth_response_duration_seconds",
+			Help:    "Time taken for the athenahealth API to respond.",
+			Buckets: prometheus.DefBuckets,
+		}, responseLabels),
+		requestBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "athenahealth_request_size_bytes",
+			Help:    "Size of request bodies sent to the athenahealth API.",
+			Buckets: sizeBuckets,
+		}, responseLabels),
+		responseBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "athenahealth_response_size_bytes",
+			Help:    "Size of response bodies received from the athenahealth API.",
+			Buckets: sizeBuckets,
+		}, responseLabels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "athenahealth_retries_total",
+			Help: "Responses to retried attempts of requests to the athenahealth API.",
+		}, responseLabels),
+		rateLimitWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "athenahealth_rate_limit_wait_seconds",
+			Help:    "Time requests to the athenahealth API waited for the rate limiter.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "path"}),
+		tokenRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "athenahealth_token_refreshes_total",
+			Help: "Background token refreshes.",
+		}, []string{"result"}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		p.requests,
+		p.responses,
+		p.responseDuration,
+		p.requestBytes,
+		p.responseBytes,
+		p.retries,
+		p.rateLimitWait,
+		p.tokenRefreshes,
+	} {
+		err := registerer.Register(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Stats) Request(method, path string) error {
+	p.requests.WithLabelValues(method, pathutil.Normalize(path)).Inc()
+
+	return nil
+}
+
+// ResponseSuccess is a no-op. Responses are counted with their labels by Response.
+func (p *Stats) ResponseSuccess() error {
+	return nil
+}
+
+// ResponseError is a no-op. Responses are counted with their labels by Response.
+func (p *Stats) ResponseError() error {
+	return nil
+}
+
+func (p *Stats) TokenRefreshSuccess() error {
+	p.tokenRefreshes.WithLabelValues("success").Inc()
+
+	return nil
+}
+
+func (p *Stats) TokenRefreshError() error {
+	p.tokenRefreshes.WithLabelValues("error").Inc()
+
+	return nil
+}
+
+func (p *Stats) Response(res *stats.Response) error {
+	path := pathutil.Normalize(res.Path)
+	labels := []string{res.Method, path, res.StatusClass()}
+
+	p.responses.WithLabelValues(labels...).Inc()
+	p.responseDuration.WithLabelValues(labels...).Observe(res.Duration.Seconds())
+	p.requestBytes.WithLabelValues(labels...).Observe(float64(res.RequestBytes))
+	p.responseBytes.WithLabelValues(labels...).Observe(float64(res.ResponseBytes))
+	p.rateLimitWait.WithLabelValues(res.Method, path).Observe(res.RateLimitWait.Seconds())
+
+	if res.Retries > 0 {
+		p.retries.WithLabelValues(labels...).Inc()
+	}
+
+	return nil
+}
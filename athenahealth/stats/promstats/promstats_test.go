@@ -0,0 +1,148 @@
+package promstats_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats/promstats"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTokenProvider struct{}
+
+func (t *testTokenProvider) Provide(context.Context) (string, time.Time, error) {
+	return "token", time.Now().Add(time.Hour), nil
+}
+
+// rewriteTransport sends every request to the athena stand-in.
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+
+	return r.next.RoundTrip(req)
+}
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.HasSuffix(r.URL.Path, "/patients/2") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"The patient is not found."}`))
+			return
+		}
+
+		w.Write([]byte(`[{"patientid":"1"}]`))
+	}))
+	defer ts.Close()
+
+	target, err := url.Parse(ts.URL)
+	assert.NoError(err)
+
+	httpClient := &http.Client{
+		Transport: &rewriteTransport{target: target, next: ts.Client().Transport},
+	}
+
+	registry := prometheus.NewRegistry()
+
+	promStats, err := promstats.New(registry)
+	assert.NoError(err)
+
+	client := athenahealth.NewHTTPClient(httpClient, "1", "", "").
+		WithTokenProvider(&testTokenProvider{}).
+		WithStats(promStats)
+
+	_, err = client.GetPatient(context.Background(), "1", nil)
+	assert.NoError(err)
+
+	_, err = client.GetPatient(context.Background(), "11", nil)
+	assert.NoError(err)
+
+	_, err = client.GetPatient(context.Background(), "2", nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	expected := `
+# HELP athenahealth_requests_total Requests sent to the athenahealth API.
+# TYPE athenahealth_requests_total counter
+athenahealth_requests_total{method="GET",path="/patients/:id:"} 3
+# HELP athenahealth_responses_total Responses received from the athenahealth API.
+# TYPE athenahealth_responses_total counter
+athenahealth_responses_total{method="GET",path="/patients/:id:",status_class="2xx"} 2
+athenahealth_responses_total{method="GET",path="/patients/:id:",status_class="4xx"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "athenahealth_requests_total", "athenahealth_responses_total")
+	assert.NoError(err)
+
+	// Every response is observed by the latency histogram.
+	assert.Equal(2, testutil.CollectAndCount(registry, "athenahealth_response_duration_seconds"))
+
+	assert.NoError(promStats.TokenRefreshSuccess())
+	assert.NoError(promStats.TokenRefreshError())
+	assert.NoError(promStats.TokenRefreshError())
+
+	expected = `
+# HELP athenahealth_token_refreshes_total Background token refreshes.
+# TYPE athenahealth_token_refreshes_total counter
+athenahealth_token_refreshes_total{result="error"} 2
+athenahealth_token_refreshes_total{result="success"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "athenahealth_token_refreshes_total")
+	assert.NoError(err)
+}
+
+func TestStats_Response(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+
+	promStats, err := promstats.New(registry)
+	assert.NoError(err)
+
+	err = promStats.Response(&stats.Response{
+		Method:        "POST",
+		Path:          "/patients/:id:/documents",
+		StatusCode:    503,
+		Duration:      time.Second,
+		RequestBytes:  1024,
+		Retries:       1,
+		RateLimitWait: time.Millisecond,
+	})
+	assert.NoError(err)
+
+	expected := `
+# HELP athenahealth_retries_total Responses to retried attempts of requests to the athenahealth API.
+# TYPE athenahealth_retries_total counter
+athenahealth_retries_total{method="POST",path="/patients/:id:/documents",status_class="5xx"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "athenahealth_retries_total")
+	assert.NoError(err)
+}
+
+func TestNew_already_registered(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+
+	_, err := promstats.New(registry)
+	assert.NoError(err)
+
+	_, err = promstats.New(registry)
+	assert.Error(err)
+}
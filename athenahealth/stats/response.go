@@ -0,0 +1,29 @@
+package stats
+
+import (
+	"strconv"
+	"time"
+)
+
+// Response describes a single attempt of a request to athena.
+type Response struct {
+	Method string
+	// Path has numeric IDs replaced by :id:, e.g. /patients/:id:.
+	Path       string
+	StatusCode int
+	Duration   time.Duration
+
+	RequestBytes  int64
+	ResponseBytes int64
+
+	// Retries is the number of attempts retried by the retry policy before this one. Replays of the
+	// request with a new token after a 401 are not counted.
+	Retries int
+	// RateLimitWait is how long the attempt waited for the rate limiter before it was sent.
+	RateLimitWait time.Duration
+}
+
+// StatusClass returns the class of the status code, e.g. 2xx.
+func (r *Response) StatusClass() string {
+	return strconv.Itoa(r.StatusCode/100) + "xx"
+}
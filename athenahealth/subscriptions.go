@@ -17,6 +17,8 @@ type Subscription struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-list-of-appointment-slot-change-subscription(s)
 func (h *HTTPClient) GetSubscription(ctx context.Context, feedType string) (*Subscription, error) {
+	ctx = withOperation(ctx, "GetSubscription")
+
 	out := &Subscription{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/%s/changed/subscription", feedType), nil, out)
@@ -41,6 +43,8 @@ type listSubscriptionEventsResponse struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-list-of-appointment-slot-change-events-to-which-you-can-subscribe
 func (h *HTTPClient) ListSubscriptionEvents(ctx context.Context, feedType string) ([]*SubscriptionEvent, error) {
+	ctx = withOperation(ctx, "ListSubscriptionEvents")
+
 	out := &listSubscriptionEventsResponse{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/%s/changed/subscription/events", feedType), nil, &out)
@@ -61,6 +65,8 @@ type SubscribeOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Subscribe-to-all/specific-change-events-for-appointment-slots
 func (h *HTTPClient) Subscribe(ctx context.Context, feedType string, opts *SubscribeOptions) error {
+	ctx = withOperation(ctx, "Subscribe")
+
 	var form url.Values
 
 	if opts != nil {
@@ -86,6 +92,8 @@ type UnsubscribeOptions struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Unsubscribe-to-all/specific-change-events-for-appointment-slots
 func (h *HTTPClient) Unsubscribe(ctx context.Context, feedType string, opts *UnsubscribeOptions) error {
+	ctx = withOperation(ctx, "Unsubscribe")
+
 	var form url.Values
 
 	if opts != nil {
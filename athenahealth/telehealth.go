@@ -17,6 +17,8 @@ type GetTelehealthInviteURLResult struct {
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Retrieve-athenaone-telehealth-invite-url
 func (h *HTTPClient) GetTelehealthInviteURL(ctx context.Context, apptID string) (*GetTelehealthInviteURLResult, error) {
+	ctx = withOperation(ctx, "GetTelehealthInviteURL")
+
 	if apptID == "" {
 		return nil, fmt.Errorf("cannot GetTelehealthInviteURL with empty apptID [%s]", apptID)
 	}
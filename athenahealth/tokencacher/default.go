@@ -32,6 +32,18 @@ func (d *Default) Get(ctx context.Context) (string, error) {
 	return d.token, nil
 }
 
+// ExpiresAt returns when the cached token expires.
+func (d *Default) ExpiresAt(ctx context.Context) (time.Time, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if len(d.token) == 0 {
+		return time.Time{}, ErrTokenNotExist
+	}
+
+	return d.expiresAt, nil
+}
+
 func (d *Default) Set(ctx context.Context, token string, expiresAt time.Time) error {
 	d.lock.Lock()
 	defer d.lock.Unlock()
@@ -41,3 +53,13 @@ func (d *Default) Set(ctx context.Context, token string, expiresAt time.Time) er
 
 	return nil
 }
+
+func (d *Default) Delete(ctx context.Context) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.token = ""
+	d.expiresAt = time.Time{}
+
+	return nil
+}
@@ -64,3 +64,36 @@ func TestDefault_Set(t *testing.T) {
 	assert.True(expiresAt.Equal(cacher.expiresAt))
 	assert.NoError(err)
 }
+
+func TestDefault_Delete(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewDefault()
+	cacher.token = "foo"
+	cacher.expiresAt = time.Now().Add(time.Minute * 1)
+
+	err := cacher.Delete(context.Background())
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestDefault_ExpiresAt(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewDefault()
+
+	_, err := cacher.ExpiresAt(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	expiresAt := time.Now().Add(-time.Minute * 1)
+	cacher.Set(context.Background(), "foo", expiresAt)
+
+	cachedExpiresAt, err := cacher.ExpiresAt(context.Background())
+
+	assert.True(expiresAt.Equal(cachedExpiresAt))
+	assert.NoError(err)
+}
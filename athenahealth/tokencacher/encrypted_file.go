@@ -0,0 +1,229 @@
+package tokencacher
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const encryptedFileLockSuffix = ".lock"
+
+// KeyProvider provides the AES key used to encrypt and decrypt the token. The key must be
+// 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	Key(ctx context.Context) ([]byte, error)
+}
+
+// StaticKey is a KeyProvider that always provides the same key.
+type StaticKey []byte
+
+func (s StaticKey) Key(ctx context.Context) ([]byte, error) {
+	return s, nil
+}
+
+// EncryptedFile caches the token in a file encrypted with AES-GCM. Writes go to a temporary
+// file that is renamed over the cache file, and reads and writes are serialized across processes
+// with a lock on a sibling ".lock" file, so processes sharing the same path never see a partial write.
+type EncryptedFile struct {
+	path        string
+	lockPath    string
+	keyProvider KeyProvider
+
+	lock sync.Mutex
+}
+
+func NewEncryptedFile(path string, keyProvider KeyProvider) *EncryptedFile {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	if keyProvider == nil {
+		panic("keyProvider is nil")
+	}
+
+	return &EncryptedFile{
+		path:        path,
+		lockPath:    path + encryptedFileLockSuffix,
+		keyProvider: keyProvider,
+	}
+}
+
+func (e *EncryptedFile) Get(ctx context.Context) (string, error) {
+	c, err := e.read(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if time.Now().After(c.ExpiresAt) {
+		return "", ErrTokenExpired
+	}
+
+	return c.Token, nil
+}
+
+// ExpiresAt returns when the cached token expires.
+func (e *EncryptedFile) ExpiresAt(ctx context.Context) (time.Time, error) {
+	c, err := e.read(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.ExpiresAt, nil
+}
+
+func (e *EncryptedFile) read(ctx context.Context) (*fileCache, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	unlock, err := lockFile(e.lockPath, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	contents, err := os.ReadFile(e.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrTokenNotExist
+		}
+
+		return nil, err
+	}
+
+	if len(contents) == 0 {
+		return nil, ErrTokenNotExist
+	}
+
+	aead, err := e.aead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(contents) < nonceSize {
+		return nil, errors.New("Error decrypting token: ciphertext too short")
+	}
+
+	plaintext, err := aead.Open(nil, contents[:nonceSize], contents[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error decrypting token: %s", err)
+	}
+
+	c := &fileCache{}
+	err = json.Unmarshal(plaintext, c)
+	if err != nil {
+		return nil, fmt.Errorf("Error unmarshaling token: %s", err)
+	}
+
+	return c, nil
+}
+
+func (e *EncryptedFile) Set(ctx context.Context, token string, expiresAt time.Time) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	c := &fileCache{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+
+	plaintext, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	aead, err := e.aead(ctx)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	ciphertext := aead.Seal(nonce, nonce, plaintext, nil)
+
+	unlock, err := lockFile(e.lockPath, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeFileAtomic(e.path, ciphertext)
+}
+
+func (e *EncryptedFile) Delete(ctx context.Context) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	unlock, err := lockFile(e.lockPath, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = os.Remove(e.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (e *EncryptedFile) aead(ctx context.Context) (cipher.AEAD, error) {
+	key, err := e.keyProvider.Key(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory as path and renames
+// it over path, so readers see either the old or the new contents and never a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := tmp.Name()
+
+	// Clean up the temporary file if anything below fails. After a successful
+	// rename it no longer exists and this is a no-op.
+	defer os.Remove(tmpPath)
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
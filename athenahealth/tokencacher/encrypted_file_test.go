@@ -0,0 +1,195 @@
+package tokencacher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEncryptionKey = StaticKey(bytes.Repeat([]byte("k"), 32))
+
+func TestEncryptedFile_Set_Get(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	cacher := NewEncryptedFile(path, testEncryptionKey)
+
+	token := "foo"
+	err := cacher.Set(context.Background(), token, time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	// The token is not stored in plain text.
+	b, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.NotContains(string(b), token)
+
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	cachedToken, err := cacher.Get(context.Background())
+
+	assert.Equal(token, cachedToken)
+	assert.NoError(err)
+
+	// Only the cache and lock files are left behind.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(err)
+	assert.Len(entries, 2)
+}
+
+func TestEncryptedFile_Get_ErrTokenNotExist(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncryptedFile(filepath.Join(t.TempDir(), "token"), testEncryptionKey)
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.Error(err)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestEncryptedFile_Get_ErrTokenExpired(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncryptedFile(filepath.Join(t.TempDir(), "token"), testEncryptionKey)
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(-time.Minute*1))
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.Error(err)
+	assert.True(errors.Is(err, ErrTokenExpired))
+}
+
+func TestEncryptedFile_Get_wrong_key(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	err := NewEncryptedFile(path, testEncryptionKey).Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	token, err := NewEncryptedFile(path, StaticKey(bytes.Repeat([]byte("x"), 32))).Get(context.Background())
+
+	assert.Empty(token)
+	assert.Error(err)
+	assert.False(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestEncryptedFile_invalid_key(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncryptedFile(filepath.Join(t.TempDir(), "token"), StaticKey("too short"))
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.Error(err)
+}
+
+type testKeyProvider struct {
+	err error
+}
+
+func (t *testKeyProvider) Key(ctx context.Context) ([]byte, error) {
+	return testEncryptionKey, t.err
+}
+
+func TestEncryptedFile_KeyProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	keyProvider := &testKeyProvider{}
+	cacher := NewEncryptedFile(filepath.Join(t.TempDir(), "token"), keyProvider)
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+
+	keyProvider.err = errors.New("key unavailable")
+
+	_, err = cacher.Get(context.Background())
+	assert.ErrorIs(err, keyProvider.err)
+}
+
+func TestEncryptedFile_Delete(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncryptedFile(filepath.Join(t.TempDir(), "token"), testEncryptionKey)
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	err = cacher.Delete(context.Background())
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// Deleting a token that does not exist is not an error.
+	err = cacher.Delete(context.Background())
+	assert.NoError(err)
+}
+
+func TestEncryptedFile_concurrent_writers(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	var wg sync.WaitGroup
+
+	// Separate instances do not share the in-process mutex, so they are only
+	// serialized by the file lock, as separate processes would be.
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cacher := NewEncryptedFile(path, testEncryptionKey)
+
+			err := cacher.Set(context.Background(), fmt.Sprintf("token-%d", i), time.Now().Add(time.Minute*1))
+			assert.NoError(err)
+
+			_, err = cacher.Get(context.Background())
+			assert.NoError(err)
+		}()
+	}
+
+	wg.Wait()
+
+	token, err := NewEncryptedFile(path, testEncryptionKey).Get(context.Background())
+	assert.NoError(err)
+	assert.Contains(token, "token-")
+}
+
+func TestEncryptedFile_ExpiresAt(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncryptedFile(filepath.Join(t.TempDir(), "token"), testEncryptionKey)
+
+	_, err := cacher.ExpiresAt(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	expiresAt := time.Now().Add(time.Minute * 1)
+	cacher.Set(context.Background(), "foo", expiresAt)
+
+	cachedExpiresAt, err := cacher.ExpiresAt(context.Background())
+
+	assert.True(expiresAt.Equal(cachedExpiresAt))
+	assert.NoError(err)
+}
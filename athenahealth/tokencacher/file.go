@@ -34,34 +34,56 @@ func (f *File) Get(ctx context.Context) (string, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	c, err := f.read()
+	if err != nil {
+		return "", err
+	}
+
+	if time.Now().After(c.ExpiresAt) {
+		return "", ErrTokenExpired
+	}
+
+	return c.Token, nil
+}
+
+// ExpiresAt returns when the cached token expires.
+func (f *File) ExpiresAt(ctx context.Context) (time.Time, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	c, err := f.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.ExpiresAt, nil
+}
+
+func (f *File) read() (*fileCache, error) {
 	_, err := os.Stat(f.path)
 	if os.IsNotExist(err) {
 		err = os.WriteFile(f.path, nil, 0600)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	contents, err := os.ReadFile(f.path)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(contents) == 0 {
-		return "", ErrTokenNotExist
+		return nil, ErrTokenNotExist
 	}
 
 	c := &fileCache{}
 	err = json.Unmarshal(contents, c)
 	if err != nil {
-		return "", fmt.Errorf("Error unmarshaling token: %s", err)
+		return nil, fmt.Errorf("Error unmarshaling token: %s", err)
 	}
 
-	if time.Now().After(c.ExpiresAt) {
-		return "", ErrTokenExpired
-	}
-
-	return c.Token, nil
+	return c, nil
 }
 
 func (f *File) Set(ctx context.Context, token string, expiresAt time.Time) error {
@@ -85,3 +107,15 @@ func (f *File) Set(ctx context.Context, token string, expiresAt time.Time) error
 
 	return nil
 }
+
+func (f *File) Delete(ctx context.Context) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	err := os.Remove(f.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -104,3 +105,49 @@ func TestFile_Set(t *testing.T) {
 	assert.Equal(token, c.Token)
 	assert.True(expiresAt.Equal(c.ExpiresAt))
 }
+
+func TestFile_Delete(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp("", "go-athenahealth_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	cacher := NewFile(file.Name())
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	err = cacher.Delete(context.Background())
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// Deleting a token that does not exist is not an error.
+	os.Remove(file.Name())
+
+	err = cacher.Delete(context.Background())
+	assert.NoError(err)
+}
+
+func TestFile_ExpiresAt(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewFile(filepath.Join(t.TempDir(), "token"))
+
+	_, err := cacher.ExpiresAt(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	expiresAt := time.Now().Add(time.Minute * 1)
+	cacher.Set(context.Background(), "foo", expiresAt)
+
+	cachedExpiresAt, err := cacher.ExpiresAt(context.Background())
+
+	assert.True(expiresAt.Equal(cachedExpiresAt))
+	assert.NoError(err)
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package tokencacher
+
+// lockFile is a no-op on platforms without file locking. Writes are still atomic, but
+// concurrent writers in separate processes are not serialized.
+func lockFile(path string, exclusive bool) (func(), error) {
+	return func() {}, nil
+}
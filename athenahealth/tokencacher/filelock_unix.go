@@ -0,0 +1,39 @@
+//go:build unix
+
+package tokencacher
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an advisory lock on the file at path, creating it if needed. The lock is
+// exclusive for writers and shared for readers, and is held until the returned func is called.
+func lockFile(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+
+	for {
+		err = syscall.Flock(int(f.Fd()), how)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		//nolint
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package tokencacher
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile takes a lock on the file at path, creating it if needed. The lock is exclusive
+// for writers and shared for readers, and is held until the returned func is called.
+func lockFile(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+
+	ol := new(windows.Overlapped)
+
+	err = windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, ol)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		//nolint
+		windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+		f.Close()
+	}, nil
+}
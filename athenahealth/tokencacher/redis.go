@@ -2,17 +2,66 @@ package tokencacher
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 )
 
 const RedisDefaultKey = "athena_token"
 
+const redisLockKeySuffix = ":lock"
+const redisLockPollInterval = 50 * time.Millisecond
+const redisDefaultLockLease = 10 * time.Second
+const redisDefaultLockWaitTimeout = 5 * time.Second
+
+// lockScript takes the lock if it is free, and reports whether the caller holds it. A caller that
+// already holds the lock, e.g. one checking the cache again before fetching the token, must not
+// wait for itself.
+var lockScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return 1
+end
+return 0
+`)
+
+// unlockScript deletes the lock only if it is still held by the caller, so a
+// lock whose lease expired and was taken by another process is left alone.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
 type Redis struct {
 	client *redis.Client
+	prefix string
 	key    string
+
+	// lock is shared with the copies made by WithNamespace, so WithLock applies to them whenever it is called.
+	lock *redisLock
+	// held is the value of the lock taken by this cacher, if any. Each namespace has its own.
+	held *redisHeldLock
+}
+
+type redisLock struct {
+	mu          sync.Mutex
+	enabled     bool
+	lease       time.Duration
+	waitTimeout time.Duration
+}
+
+type redisHeldLock struct {
+	mu    sync.Mutex
+	value string
 }
 
 func NewRedis(client *redis.Client, key string) *Redis {
@@ -20,33 +69,240 @@ func NewRedis(client *redis.Client, key string) *Redis {
 		panic("client is nil")
 	}
 
+	if len(key) == 0 {
+		key = RedisDefaultKey
+	}
+
 	r := &Redis{
 		client: client,
+		prefix: key,
 		key:    key,
+		lock:   &redisLock{},
+		held:   &redisHeldLock{},
+	}
+
+	return r
+}
+
+type redisCache struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// WithNamespace returns a copy of the cacher whose key is scoped to an environment and client ID
+// (e.g. athena_token:preview:client-id) so clients for different environments or credentials can
+// share the same Redis. HTTPClient calls it automatically when the cacher is configured.
+func (r *Redis) WithNamespace(environment, clientID string) TokenCacher {
+	c := *r
+
+	c.key = fmt.Sprintf("%s:%s:%s", r.prefix, environment, clientID)
+	c.held = &redisHeldLock{}
+
+	return &c
+}
+
+// WithLock enables coordinating token fetches across processes sharing the same Redis. When the
+// token does not exist, the first caller of Get takes a lock (held for at most lease) and gets
+// ErrTokenNotExist so it fetches the token, while callers using other cachers wait up to
+// waitTimeout for the token to be Set before giving up and fetching it themselves. Callers using
+// the cacher holding the lock are not made to wait. The lock is released by Set, or by Unlock if
+// the token could not be fetched.
+func (r *Redis) WithLock(lease, waitTimeout time.Duration) *Redis {
+	if lease <= 0 {
+		lease = redisDefaultLockLease
 	}
 
-	if len(r.key) == 0 {
-		r.key = RedisDefaultKey
+	if waitTimeout <= 0 {
+		waitTimeout = redisDefaultLockWaitTimeout
 	}
 
+	r.lock.mu.Lock()
+	defer r.lock.mu.Unlock()
+
+	r.lock.enabled = true
+	r.lock.lease = lease
+	r.lock.waitTimeout = waitTimeout
+
 	return r
 }
 
+func (r *Redis) lockConfig() (bool, time.Duration, time.Duration) {
+	r.lock.mu.Lock()
+	defer r.lock.mu.Unlock()
+
+	return r.lock.enabled, r.lock.lease, r.lock.waitTimeout
+}
+
+func (r *Redis) lockKey() string {
+	return r.key + redisLockKeySuffix
+}
+
 func (r *Redis) Get(ctx context.Context) (string, error) {
-	val, err := r.client.Get(context.Background(), r.key).Result()
+	val, err := r.get(ctx)
+
+	enabled, lease, waitTimeout := r.lockConfig()
+	if !enabled || !isMissing(err) {
+		return val, err
+	}
+
+	held, lockErr := r.acquire(ctx, lease)
+	if lockErr != nil {
+		return "", lockErr
+	}
+
+	if held {
+		return "", err
+	}
+
+	return r.wait(ctx, waitTimeout)
+}
+
+// acquire takes the lock, or reports that this cacher already holds it. Each acquisition has its own
+// value, so the lock can only be released by the cacher that took it.
+func (r *Redis) acquire(ctx context.Context, lease time.Duration) (bool, error) {
+	r.held.mu.Lock()
+	defer r.held.mu.Unlock()
+
+	value := r.held.value
+	if len(value) == 0 {
+		value = uuid.NewString()
+	}
+
+	held, err := lockScript.Run(ctx, r.client, []string{r.lockKey()}, value, lease.Milliseconds()).Bool()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
+		return false, err
+	}
+
+	if held {
+		r.held.value = value
+	} else {
+		// The lease of the lock this cacher held expired and the lock was taken by another process.
+		r.held.value = ""
+	}
+
+	return held, nil
+}
+
+// Unlock releases the lock taken by Get, e.g. when the token could not be fetched, so that other
+// processes do not wait for it until its lease expires.
+func (r *Redis) Unlock(ctx context.Context) error {
+	r.held.mu.Lock()
+	defer r.held.mu.Unlock()
+
+	if len(r.held.value) == 0 {
+		return nil
+	}
+
+	err := unlockScript.Run(ctx, r.client, []string{r.lockKey()}, r.held.value).Err()
+	if err != nil {
+		return err
+	}
+
+	r.held.value = ""
+
+	return nil
+}
+
+// wait polls for the token to be set by the process holding the lock. If the token does not
+// appear within the wait timeout, ErrTokenNotExist is returned so the caller fetches it instead.
+func (r *Redis) wait(ctx context.Context, waitTimeout time.Duration) (string, error) {
+	timeout := time.NewTimer(waitTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(redisLockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+
+		case <-timeout.C:
 			return "", ErrTokenNotExist
+
+		case <-ticker.C:
+		}
+
+		val, err := r.get(ctx)
+		if !isMissing(err) {
+			return val, err
 		}
+	}
+}
+
+func isMissing(err error) bool {
+	return errors.Is(err, ErrTokenNotExist) || errors.Is(err, ErrTokenExpired)
+}
 
+func (r *Redis) get(ctx context.Context) (string, error) {
+	c, err := r.read(ctx)
+	if err != nil {
 		return "", err
 	}
 
-	return val, nil
+	if time.Now().After(c.ExpiresAt) {
+		return "", ErrTokenExpired
+	}
+
+	return c.Token, nil
+}
+
+// ExpiresAt returns when the cached token expires. Unlike Get, it never takes the lock.
+func (r *Redis) ExpiresAt(ctx context.Context) (time.Time, error) {
+	c, err := r.read(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.ExpiresAt, nil
+}
+
+func (r *Redis) read(ctx context.Context) (*redisCache, error) {
+	val, err := r.client.Get(ctx, r.key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrTokenNotExist
+		}
+
+		return nil, err
+	}
+
+	c := &redisCache{}
+	err = json.Unmarshal(val, c)
+	if err != nil || len(c.Token) == 0 {
+		// Values written by earlier versions are the bare token without an expiry.
+		// Treat them as missing so a new token is fetched and stored in the current format.
+		return nil, ErrTokenNotExist
+	}
+
+	return c, nil
 }
 
 func (r *Redis) Set(ctx context.Context, token string, expiresAt time.Time) error {
-	_, err := r.client.Set(context.Background(), r.key, token, time.Second*time.Duration(expiresAt.Unix()-time.Now().Unix())).Result()
+	c := &redisCache{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	// Keep already expired tokens around briefly rather than passing Redis a
+	// non-positive TTL; Get reports them as expired either way.
+	ttl := max(time.Until(expiresAt), time.Second)
+
+	_, err = r.client.Set(ctx, r.key, b, ttl).Result()
+	if err != nil {
+		return err
+	}
+
+	return r.Unlock(ctx)
+}
+
+func (r *Redis) Delete(ctx context.Context) error {
+	_, err := r.client.Del(ctx, r.key).Result()
 
 	return err
 }
@@ -2,6 +2,7 @@ package tokencacher
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -20,9 +21,13 @@ func TestRedis_Get(t *testing.T) {
 	}
 	defer s.Close()
 
-	expectedToken := "foo"
+	c := &redisCache{
+		Token:     "foo",
+		ExpiresAt: time.Now().Add(time.Minute * 1),
+	}
 
-	s.Set(RedisDefaultKey, expectedToken)
+	b, _ := json.Marshal(c)
+	s.Set(RedisDefaultKey, string(b))
 	s.SetTTL(RedisDefaultKey, time.Minute*1)
 
 	cacher := NewRedis(redis.NewClient(&redis.Options{
@@ -31,10 +36,122 @@ func TestRedis_Get(t *testing.T) {
 
 	token, err := cacher.Get(context.Background())
 
-	assert.Equal(expectedToken, token)
+	assert.Equal(c.Token, token)
 	assert.NoError(err)
 }
 
+func TestRedis_Get_ErrTokenExpired(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	c := &redisCache{
+		Token:     "foo",
+		ExpiresAt: time.Now().Add(-time.Minute * 1),
+	}
+
+	b, _ := json.Marshal(c)
+	s.Set(RedisDefaultKey, string(b))
+	s.SetTTL(RedisDefaultKey, time.Minute*1)
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.Error(err)
+	assert.True(errors.Is(err, ErrTokenExpired))
+}
+
+func TestRedis_Get_legacy_value(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	// Earlier versions stored the bare token.
+	s.Set(RedisDefaultKey, "foo")
+	s.SetTTL(RedisDefaultKey, time.Minute*1)
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestRedis_Get_context_cancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = cacher.Get(ctx)
+	assert.ErrorIs(err, context.Canceled)
+}
+
+func TestRedis_WithNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	cacher := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+
+	preview := cacher.WithNamespace("preview", "client-id")
+	prod := cacher.WithNamespace("prod", "client-id")
+
+	// The shared cacher is left alone.
+	assert.Equal(RedisDefaultKey, cacher.key)
+	assert.Equal(RedisDefaultKey+":preview:client-id"+redisLockKeySuffix, preview.(*Redis).lockKey())
+
+	err = preview.Set(context.Background(), "preview-token", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	err = prod.Set(context.Background(), "prod-token", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	assert.True(s.Exists(RedisDefaultKey + ":preview:client-id"))
+	assert.True(s.Exists(RedisDefaultKey + ":prod:client-id"))
+
+	token, err := preview.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("preview-token", token)
+
+	token, err = prod.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("prod-token", token)
+}
+
 func TestRedis_Get_ErrTokenNotExist(t *testing.T) {
 	assert := assert.New(t)
 
@@ -73,9 +190,289 @@ func TestRedis_Set(t *testing.T) {
 
 	assert.NoError(err)
 
-	token, _ := s.Get(RedisDefaultKey)
+	val, _ := s.Get(RedisDefaultKey)
 	ttl := s.TTL(RedisDefaultKey)
 
-	assert.Equal(expectedToken, token)
-	assert.True(time.Now().Add(time.Second * ttl).After(time.Now()))
+	c := &redisCache{}
+	json.Unmarshal([]byte(val), c)
+
+	assert.Equal(expectedToken, c.Token)
+	assert.True(c.ExpiresAt.After(time.Now()))
+	assert.True(ttl > 0)
+}
+
+func TestRedis_Delete(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	err = cacher.Delete(context.Background())
+	assert.NoError(err)
+
+	assert.False(s.Exists(RedisDefaultKey))
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestRedis_WithLock_Get_waits_for_token(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	cacherA := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+	cacherB := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+
+	// The first caller takes the lock and is told to fetch the token.
+	token, err := cacherA.Get(context.Background())
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+	assert.True(s.Exists(RedisDefaultKey + redisLockKeySuffix))
+
+	tokenCh := make(chan string)
+	go func() {
+		token, err := cacherB.Get(context.Background())
+		assert.NoError(err)
+
+		tokenCh <- token
+	}()
+
+	time.Sleep(redisLockPollInterval * 2)
+
+	expectedToken := "foo"
+	err = cacherA.Set(context.Background(), expectedToken, time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	// The other caller picks up the token instead of fetching it, and the lock is released.
+	assert.Equal(expectedToken, <-tokenCh)
+	assert.False(s.Exists(RedisDefaultKey + redisLockKeySuffix))
+}
+
+func TestRedis_WithLock_Get_wait_timeout(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	cacherA := NewRedis(client, "").WithLock(time.Second*10, time.Millisecond*200)
+	cacherB := NewRedis(client, "").WithLock(time.Second*10, time.Millisecond*200)
+
+	_, err = cacherA.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// The lock holder never sets the token, so the other caller falls back to fetching it.
+	start := time.Now()
+	token, err := cacherB.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+	assert.GreaterOrEqual(time.Since(start), time.Millisecond*200)
+}
+
+func TestRedis_WithLock_Get_lease_expired(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	cacherA := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+	cacherB := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+
+	_, err = cacherA.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	s.FastForward(time.Second * 11)
+
+	// The lease expired, so the next caller takes over the lock.
+	_, err = cacherB.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// A Set from the original holder does not release the lock it no longer holds.
+	err = cacherA.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+	assert.True(s.Exists(RedisDefaultKey + redisLockKeySuffix))
+
+	err = cacherB.Set(context.Background(), "bar", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+	assert.False(s.Exists(RedisDefaultKey + redisLockKeySuffix))
+}
+
+func TestRedis_WithLock_Get_context_cancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	cacherA := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+	cacherB := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+
+	_, err = cacherA.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	_, err = cacherB.Get(ctx)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func TestRedis_ExpiresAt(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "").WithLock(time.Minute, time.Second)
+
+	_, err = cacher.ExpiresAt(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// The lock is not taken.
+	assert.False(s.Exists(RedisDefaultKey + redisLockKeySuffix))
+
+	expiresAt := time.Now().Add(time.Minute * 1)
+	cacher.Set(context.Background(), "foo", expiresAt)
+
+	cachedExpiresAt, err := cacher.ExpiresAt(context.Background())
+
+	assert.True(expiresAt.Equal(cachedExpiresAt))
+	assert.NoError(err)
+}
+
+func TestRedis_WithLock_Get_lock_holder(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "").WithLock(time.Second*10, time.Second*5)
+
+	_, err = cacher.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// The lock holder checking the cache again is not made to wait for itself.
+	start := time.Now()
+	_, err = cacher.Get(context.Background())
+
+	assert.True(errors.Is(err, ErrTokenNotExist))
+	assert.Less(time.Since(start), redisLockPollInterval)
+}
+
+func TestRedis_Unlock(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	holder := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+	waiter := NewRedis(client, "").WithLock(time.Second*10, time.Second*5)
+
+	_, err = holder.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	// The holder failed to fetch the token, so the waiter takes the lock instead of waiting for the lease.
+	assert.NoError(holder.Unlock(context.Background()))
+
+	start := time.Now()
+	_, err = waiter.Get(context.Background())
+
+	assert.True(errors.Is(err, ErrTokenNotExist))
+	assert.Less(time.Since(start), redisLockPollInterval)
+
+	// Unlocking without holding the lock leaves the waiter's lock alone.
+	assert.NoError(holder.Unlock(context.Background()))
+	assert.True(s.Exists(RedisDefaultKey + redisLockKeySuffix))
+}
+
+func TestRedis_WithLock_after_WithNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	preview := cacher.WithNamespace("preview", "client-id")
+	prod := cacher.WithNamespace("prod", "client-id")
+
+	cacher.WithLock(time.Second*10, time.Second*5)
+
+	_, err = preview.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	_, err = prod.Get(context.Background())
+	assert.True(errors.Is(err, ErrTokenNotExist))
+
+	previewLock, err := s.Get(RedisDefaultKey + ":preview:client-id" + redisLockKeySuffix)
+	assert.NoError(err)
+
+	prodLock, err := s.Get(RedisDefaultKey + ":prod:client-id" + redisLockKeySuffix)
+	assert.NoError(err)
+
+	// Each namespace holds its lock with its own value.
+	assert.NotEqual(previewLock, prodLock)
 }
@@ -0,0 +1,14 @@
+package tokencacher
+
+import (
+	"context"
+	"time"
+)
+
+// TokenCacher caches API tokens. It is athenahealth.TokenCacher, declared here so that cachers can
+// return copies of themselves scoped to a namespace.
+type TokenCacher interface {
+	Get(context.Context) (string, error)
+	Set(context.Context, string, time.Time) error
+	Delete(context.Context) error
+}
@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -16,6 +17,9 @@ const (
 
 	// ProdAuthURL is the URL used to authenticate in the production environment.
 	ProdAuthURL = "https://api.platform.athenahealth.com/oauth2/v1/token"
+
+	// DefaultScope is the scope requested when no scopes are configured.
+	DefaultScope = "athena/service/Athenanet.MDP.*"
 )
 
 type Default struct {
@@ -23,6 +27,7 @@ type Default struct {
 
 	clientID string
 	secret   string
+	scopes   []string
 
 	authURL string
 }
@@ -33,6 +38,7 @@ func NewDefault(httpClient *http.Client, clientID, secret string, preview bool)
 
 		clientID: clientID,
 		secret:   secret,
+		scopes:   []string{DefaultScope},
 	}
 
 	if preview {
@@ -44,6 +50,33 @@ func NewDefault(httpClient *http.Client, clientID, secret string, preview bool)
 	return d
 }
 
+func (d *Default) WithScopes(scopes ...string) *Default {
+	d.scopes = scopes
+
+	return d
+}
+
+// WithAuthURL overrides the token endpoint, e.g. to authenticate against a local stand-in for athena.
+func (d *Default) WithAuthURL(authURL string) *Default {
+	d.authURL = authURL
+
+	return d
+}
+
+// AuthURL returns the token endpoint.
+func (d *Default) AuthURL() string {
+	return d.authURL
+}
+
+// ForAuthURL returns a copy of the provider that requests tokens from authURL, leaving the provider itself
+// alone so it can be shared by clients for different environments.
+func (d *Default) ForAuthURL(authURL string) *Default {
+	c := *d
+	c.authURL = authURL
+
+	return &c
+}
+
 type authResponse struct {
 	AccessToken string      `json:"access_token"`
 	ExpiresIn   json.Number `json:"expires_in"`
@@ -52,7 +85,7 @@ type authResponse struct {
 func (d *Default) Provide(ctx context.Context) (string, time.Time, error) {
 	vals := url.Values{
 		"grant_type": {"client_credentials"},
-		"scope":      {"athena/service/Athenanet.MDP.*"},
+		"scope":      {strings.Join(d.scopes, " ")},
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", d.authURL, bytes.NewBufferString(vals.Encode()))
@@ -63,28 +96,49 @@ func (d *Default) Provide(ctx context.Context) (string, time.Time, error) {
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBasicAuth(d.clientID, d.secret)
 
-	res, err := d.httpClient.Do(req)
+	return requestToken(d.httpClient, req)
+}
+
+// requestToken sends a token request to the authorization server and parses the access token
+// and its expiration time from the response.
+func requestToken(httpClient *http.Client, req *http.Request) (string, time.Time, error) {
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return "", time.Now(), err
 	}
 	defer res.Body.Close()
 
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
 	if res.StatusCode != http.StatusOK {
-		return "", time.Now(), fmt.Errorf("%s", res.Status)
+		err := &OAuthError{}
+
+		//nolint
+		json.Unmarshal(resBody, err)
+
+		err.HTTPResponse = res
+
+		return "", time.Now(), err
 	}
 
 	authRes := &authResponse{}
-	err = json.NewDecoder(res.Body).Decode(authRes)
+	err = json.Unmarshal(resBody, authRes)
 	if err != nil {
 		return "", time.Now(), err
 	}
 
-	expiresIn, err := authRes.ExpiresIn.Int64()
+	// expires_in may arrive as a JSON string or number, and json.Number accepts both.
+	expiresIn, err := authRes.ExpiresIn.Float64()
 	if err != nil {
 		return "", time.Now(), err
 	}
 
-	expiresAt := time.Now().Add(time.Second * time.Duration(expiresIn))
+	expiresAt := time.Now().Add(time.Duration(expiresIn * float64(time.Second)))
 
 	return authRes.AccessToken, expiresAt, nil
 }
@@ -3,6 +3,7 @@ package tokenprovider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,12 +25,25 @@ func TestNewDefault(t *testing.T) {
 	assert.Equal(key, p.clientID)
 	assert.Equal(secret, p.secret)
 	assert.Equal(PreviewAuthURL, p.authURL)
+	assert.Equal([]string{DefaultScope}, p.scopes)
 
 	preview = false
 	p = NewDefault(&http.Client{}, "", "", preview)
 	assert.Equal(ProdAuthURL, p.authURL)
 }
 
+func TestDefault_ForAuthURL(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDefault(&http.Client{}, "client-id", "secret", true).WithScopes("scope")
+
+	c := d.ForAuthURL("https://auth.internal/token")
+
+	assert.Equal("https://auth.internal/token", c.AuthURL())
+	assert.Equal([]string{"scope"}, c.scopes)
+	assert.Equal(PreviewAuthURL, d.AuthURL())
+}
+
 func TestDefault_Provide(t *testing.T) {
 	assert := assert.New(t)
 
@@ -56,3 +70,88 @@ func TestDefault_Provide(t *testing.T) {
 	assert.True(expiresAt.After(time.Now()))
 	assert.NoError(err)
 }
+
+func TestDefault_Provide_ExpiresIn(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, expiresIn := range []string{`"3600"`, `3600`, `3600.0`} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"access_token":"foo","expires_in":` + expiresIn + `}`))
+		}))
+
+		p := NewDefault(ts.Client(), "", "", false).WithAuthURL(ts.URL)
+
+		token, expiresAt, err := p.Provide(context.Background())
+
+		assert.NoError(err, expiresIn)
+		assert.Equal("foo", token)
+		assert.WithinDuration(time.Now().Add(time.Hour), expiresAt, time.Second, expiresIn)
+
+		ts.Close()
+	}
+}
+
+func TestDefault_Provide_WithScopes(t *testing.T) {
+	assert := assert.New(t)
+
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("athena/service/Athenanet.MDP.* system/Patient.read", r.FormValue("scope"))
+
+		called = true
+
+		w.Write([]byte(`{"access_token":"foo","expires_in":"60"}`))
+	}))
+	defer ts.Close()
+
+	p := NewDefault(ts.Client(), "", "", false).
+		WithAuthURL(ts.URL).
+		WithScopes(DefaultScope, "system/Patient.read")
+
+	_, _, err := p.Provide(context.Background())
+
+	assert.NoError(err)
+	assert.True(called)
+}
+
+func TestDefault_Provide_OAuthError(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"Client authentication failed"}`))
+	}))
+	defer ts.Close()
+
+	p := NewDefault(ts.Client(), "", "", false).WithAuthURL(ts.URL)
+
+	token, _, err := p.Provide(context.Background())
+
+	assert.Empty(token)
+
+	oauthErr := &OAuthError{}
+	assert.True(errors.As(err, &oauthErr))
+	assert.Equal("invalid_client", oauthErr.ErrorCode)
+	assert.Equal("Client authentication failed", oauthErr.ErrorDescription)
+	assert.Equal(http.StatusUnauthorized, oauthErr.HTTPResponse.StatusCode)
+	assert.Contains(oauthErr.Error(), "invalid_client")
+	assert.Contains(oauthErr.Error(), "Client authentication failed")
+}
+
+func TestDefault_Provide_OAuthError_no_body(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	p := NewDefault(ts.Client(), "", "", false).WithAuthURL(ts.URL)
+
+	_, _, err := p.Provide(context.Background())
+
+	oauthErr := &OAuthError{}
+	assert.True(errors.As(err, &oauthErr))
+	assert.Empty(oauthErr.ErrorCode)
+	assert.Contains(oauthErr.Error(), "502 Bad Gateway")
+}
@@ -0,0 +1,29 @@
+package tokenprovider
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// OAuthError represents an error response from the athenahealth token endpoint.
+// https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
+type OAuthError struct {
+	ErrorCode        string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+
+	HTTPResponse *http.Response `json:"-"`
+}
+
+func (o *OAuthError) Error() string {
+	details := "no error description"
+	if len(o.ErrorDescription) > 0 {
+		details = o.ErrorDescription
+	}
+
+	var status string
+	if o.HTTPResponse != nil {
+		status = o.HTTPResponse.Status
+	}
+
+	return fmt.Sprintf("athenahealth OAuth error (%s): %s (%s)", status, o.ErrorCode, details)
+}
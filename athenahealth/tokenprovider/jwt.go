@@ -0,0 +1,262 @@
+package tokenprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ClientAssertionType is the client_assertion_type sent with a signed JWT client assertion.
+	ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// jwtAssertionLifetime is how long a client assertion is valid for after it is issued.
+	jwtAssertionLifetime = 5 * time.Minute
+)
+
+// JWT authenticates with a JWT client assertion signed by an RSA or ECDSA private key
+// (private_key_jwt) instead of a shared client secret.
+type JWT struct {
+	httpClient *http.Client
+
+	clientID string
+	key      crypto.Signer
+	keyID    string
+	scopes   []string
+
+	authURL string
+}
+
+func NewJWT(httpClient *http.Client, clientID string, key crypto.Signer, preview bool) *JWT {
+	if key == nil {
+		panic("key is nil")
+	}
+
+	j := &JWT{
+		httpClient: httpClient,
+
+		clientID: clientID,
+		key:      key,
+		scopes:   []string{DefaultScope},
+	}
+
+	if preview {
+		j.authURL = PreviewAuthURL
+	} else {
+		j.authURL = ProdAuthURL
+	}
+
+	return j
+}
+
+// WithKeyID sets the kid header so the authorization server can select the matching public key.
+func (j *JWT) WithKeyID(keyID string) *JWT {
+	j.keyID = keyID
+
+	return j
+}
+
+func (j *JWT) WithScopes(scopes ...string) *JWT {
+	j.scopes = scopes
+
+	return j
+}
+
+// WithAuthURL overrides the token endpoint, e.g. to authenticate against a local stand-in for athena.
+// The auth URL is also the audience of the client assertion.
+func (j *JWT) WithAuthURL(authURL string) *JWT {
+	j.authURL = authURL
+
+	return j
+}
+
+// AuthURL returns the token endpoint.
+func (j *JWT) AuthURL() string {
+	return j.authURL
+}
+
+// ForAuthURL returns a copy of the provider that requests tokens from authURL, leaving the provider itself
+// alone so it can be shared by clients for different environments.
+func (j *JWT) ForAuthURL(authURL string) *JWT {
+	c := *j
+	c.authURL = authURL
+
+	return &c
+}
+
+func (j *JWT) Provide(ctx context.Context) (string, time.Time, error) {
+	assertion, err := j.assertion()
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	vals := url.Values{
+		"grant_type":            {"client_credentials"},
+		"scope":                 {strings.Join(j.scopes, " ")},
+		"client_assertion_type": {ClientAssertionType},
+		"client_assertion":      {assertion},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", j.authURL, bytes.NewBufferString(vals.Encode()))
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	return requestToken(j.httpClient, req)
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// assertion builds and signs the client assertion.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-3
+func (j *JWT) assertion() (string, error) {
+	alg, hash, err := signingAlgorithm(j.key)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	header, err := json.Marshal(&jwtHeader{
+		Algorithm: alg,
+		Type:      "JWT",
+		KeyID:     j.keyID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(&jwtClaims{
+		Issuer:    j.clientID,
+		Subject:   j.clientID,
+		Audience:  j.authURL,
+		ID:        uuid.NewString(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(jwtAssertionLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	sig, err := j.key.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return "", err
+	}
+
+	if pub, ok := j.key.Public().(*ecdsa.PublicKey); ok {
+		sig, err = jwsECDSASignature(pub, sig)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// jwsECDSASignature converts an ASN.1 ECDSA signature, as returned by crypto.Signer, to the
+// fixed-size concatenation of r and s that JWS requires.
+func jwsECDSASignature(pub *ecdsa.PublicKey, sig []byte) ([]byte, error) {
+	var rs struct {
+		R, S *big.Int
+	}
+
+	_, err := asn1.Unmarshal(sig, &rs)
+	if err != nil {
+		return nil, err
+	}
+
+	size := (pub.Curve.Params().BitSize + 7) / 8
+
+	out := make([]byte, 2*size)
+	rs.R.FillBytes(out[:size])
+	rs.S.FillBytes(out[size:])
+
+	return out, nil
+}
+
+func signingAlgorithm(key crypto.Signer) (string, crypto.Hash, error) {
+	switch k := key.Public().(type) {
+	case *rsa.PublicKey:
+		return "RS256", crypto.SHA256, nil
+
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "ES256", crypto.SHA256, nil
+		case elliptic.P384():
+			return "ES384", crypto.SHA384, nil
+		case elliptic.P521():
+			return "ES512", crypto.SHA512, nil
+		}
+
+		return "", 0, fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+	}
+
+	return "", 0, fmt.Errorf("unsupported key type %T", key.Public())
+}
+
+// ParsePrivateKeyPEM parses a PEM encoded PKCS #8, PKCS #1 (RSA) or SEC 1 (EC) private key for use with NewJWT.
+func ParsePrivateKeyPEM(b []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unable to parse private key")
+}
@@ -0,0 +1,247 @@
+package tokenprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// verifyAssertion checks the signature of a client assertion with the public key and returns its header and claims.
+func verifyAssertion(assertion string, pub crypto.PublicKey) (*jwtHeader, *jwtClaims, error) {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("malformed assertion")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		digest := crypto.SHA256.New()
+		digest.Write(signingInput)
+
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest.Sum(nil), sig)
+		if err != nil {
+			return nil, nil, err
+		}
+
+	case *ecdsa.PublicKey:
+		digest := crypto.SHA256.New()
+		digest.Write(signingInput)
+
+		r := new(big.Int).SetBytes(sig[:len(sig)/2])
+		s := new(big.Int).SetBytes(sig[len(sig)/2:])
+
+		if !ecdsa.Verify(pub, digest.Sum(nil), r, s) {
+			return nil, nil, errors.New("invalid signature")
+		}
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := &jwtHeader{}
+	claims := &jwtClaims{}
+
+	err = json.Unmarshal(headerJSON, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = json.Unmarshal(claimsJSON, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return header, claims, nil
+}
+
+func testJWTTokenServer(assert *assert.Assertions, pub crypto.PublicKey, expectedAlg string) *httptest.Server {
+	var ts *httptest.Server
+
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(http.MethodPost, r.Method)
+		assert.Equal("client_credentials", r.FormValue("grant_type"))
+		assert.Equal(ClientAssertionType, r.FormValue("client_assertion_type"))
+
+		_, _, ok := r.BasicAuth()
+		assert.False(ok)
+
+		header, claims, err := verifyAssertion(r.FormValue("client_assertion"), pub)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		assert.Equal(expectedAlg, header.Algorithm)
+		assert.Equal("key-id", header.KeyID)
+		assert.Equal("client-id", claims.Issuer)
+		assert.Equal("client-id", claims.Subject)
+		assert.Equal(ts.URL, claims.Audience)
+		assert.NotEmpty(claims.ID)
+		assert.Greater(claims.ExpiresAt, time.Now().Unix())
+
+		assert.Equal("athena/service/Athenanet.MDP.* system/Patient.read", r.FormValue("scope"))
+
+		b, _ := json.Marshal(&authResponse{
+			AccessToken: "foo",
+			ExpiresIn:   "60",
+		})
+		w.Write(b)
+	}))
+
+	return ts
+}
+
+func TestNewJWT(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	p := NewJWT(&http.Client{}, "client-id", key, true)
+
+	assert.NotNil(p.httpClient)
+	assert.Equal("client-id", p.clientID)
+	assert.Equal(PreviewAuthURL, p.authURL)
+	assert.Equal([]string{DefaultScope}, p.scopes)
+
+	p = NewJWT(&http.Client{}, "client-id", key, false)
+	assert.Equal(ProdAuthURL, p.authURL)
+}
+
+func TestJWT_ForAuthURL(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	p := NewJWT(&http.Client{}, "client-id", key, true).WithKeyID("key-id")
+
+	c := p.ForAuthURL("https://auth.internal/token")
+
+	assert.Equal("https://auth.internal/token", c.AuthURL())
+	assert.Equal("key-id", c.keyID)
+	assert.Equal(PreviewAuthURL, p.AuthURL())
+}
+
+func TestJWT_Provide_RSA(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+
+	ts := testJWTTokenServer(assert, &key.PublicKey, "RS256")
+	defer ts.Close()
+
+	p := NewJWT(ts.Client(), "client-id", key, false).
+		WithKeyID("key-id").
+		WithScopes(DefaultScope, "system/Patient.read")
+	p.authURL = ts.URL
+
+	token, expiresAt, err := p.Provide(context.Background())
+
+	assert.NoError(err)
+	assert.Equal("foo", token)
+	assert.True(expiresAt.After(time.Now()))
+}
+
+func TestJWT_Provide_ECDSA(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	ts := testJWTTokenServer(assert, &key.PublicKey, "ES256")
+	defer ts.Close()
+
+	p := NewJWT(ts.Client(), "client-id", key, false).
+		WithKeyID("key-id").
+		WithScopes(DefaultScope, "system/Patient.read")
+	p.authURL = ts.URL
+
+	token, expiresAt, err := p.Provide(context.Background())
+
+	assert.NoError(err)
+	assert.Equal("foo", token)
+	assert.True(expiresAt.After(time.Now()))
+}
+
+func TestJWT_Provide_wrong_key(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	ts := testJWTTokenServer(assert, &otherKey.PublicKey, "ES256")
+	defer ts.Close()
+
+	p := NewJWT(ts.Client(), "client-id", key, false)
+	p.authURL = ts.URL
+
+	token, _, err := p.Provide(context.Background())
+
+	assert.Error(err)
+	assert.Empty(token)
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	assert.NoError(err)
+
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	assert.NoError(err)
+
+	for _, block := range []*pem.Block{
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{Type: "EC PRIVATE KEY", Bytes: sec1},
+	} {
+		key, err := ParsePrivateKeyPEM(pem.EncodeToMemory(block))
+		assert.NoError(err, block.Type)
+		assert.NotNil(key, block.Type)
+	}
+
+	_, err = ParsePrivateKeyPEM([]byte("not a key"))
+	assert.Error(err)
+}
@@ -0,0 +1,176 @@
+package athenahealth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTokenRefreshFraction is the fraction of a token's lifetime remaining when it is refreshed
+	// if an invalid fraction is given to WithTokenRefresher.
+	defaultTokenRefreshFraction = 0.2
+
+	// tokenRefreshMinInterval is the minimum time between refreshes, including after a failure.
+	tokenRefreshMinInterval = time.Second
+
+	// tokenRefreshMaxBackoff caps the backoff between retries when the token provider fails.
+	tokenRefreshMaxBackoff = time.Minute
+)
+
+// tokenRefresher is the state of the background token refresher started by WithTokenRefresher.
+type tokenRefresher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	lock sync.Mutex
+}
+
+// WithTokenRefresher starts a background goroutine that keeps the cached token fresh, so requests never
+// have to wait on the token provider. A token is fetched immediately unless the token cacher implements
+// ExpiringTokenCacher and holds a valid token, and is then renewed once refreshFraction of its lifetime
+// remains (e.g. 0.2 renews a 1 hour token after 48 minutes). The refresher runs until ctx is cancelled
+// or Close is called. Refresh failures are retried with exponential backoff while the old token stays cached.
+func (h *HTTPClient) WithTokenRefresher(ctx context.Context, refreshFraction float64) *HTTPClient {
+	if refreshFraction <= 0 || refreshFraction >= 1 {
+		refreshFraction = defaultTokenRefreshFraction
+	}
+
+	h.tokenRefresher.lock.Lock()
+	defer h.tokenRefresher.lock.Unlock()
+
+	h.tokenRefresher.stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	h.tokenRefresher.cancel = cancel
+	h.tokenRefresher.done = done
+
+	go func() {
+		defer close(done)
+
+		h.refreshTokens(ctx, refreshFraction)
+	}()
+
+	return h
+}
+
+// Close stops the background token refresher, if one was started, and waits for it to exit.
+func (h *HTTPClient) Close() error {
+	h.tokenRefresher.lock.Lock()
+	defer h.tokenRefresher.lock.Unlock()
+
+	h.tokenRefresher.stop()
+
+	return nil
+}
+
+// stop cancels the refresher and waits for it to exit. It must be called with lock held.
+func (t *tokenRefresher) stop() {
+	if t.cancel == nil {
+		return
+	}
+
+	t.cancel()
+	<-t.done
+
+	t.cancel = nil
+	t.done = nil
+}
+
+func (h *HTTPClient) refreshTokens(ctx context.Context, refreshFraction float64) {
+	backoff := tokenRefreshMinInterval
+
+	// lifetime is the longest lifetime seen of a token, which tells when a cached token is due to be
+	// refreshed. A token found in the cache is assumed to have been fresh when it was first seen.
+	var lifetime time.Duration
+
+	refreshStats, _ := h.stats.(TokenRefreshStats)
+
+	for {
+		var wait time.Duration
+
+		ttl, cached := h.cachedTokenTTL(ctx)
+		if cached {
+			lifetime = max(lifetime, ttl)
+		}
+
+		if refreshAt := time.Duration(float64(lifetime) * refreshFraction); cached && ttl > refreshAt {
+			wait = ttl - refreshAt
+
+			h.logger.Debug().
+				Str("expiresIn", ttl.String()).
+				Str("refreshIn", wait.String()).
+				Msg("athenahealth cached token still valid")
+		} else {
+			// The fetch is shared with requests that need a token at the same time.
+			fetched, err := h.fetchTokenOnce(ctx, false)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				h.logger.Error().
+					Err(err).
+					Str("retryAfter", backoff.String()).
+					Msg("athenahealth token refresh failed")
+
+				if refreshStats != nil {
+					//nolint
+					refreshStats.TokenRefreshError()
+				}
+
+				wait = backoff
+				backoff = min(backoff*2, tokenRefreshMaxBackoff)
+			} else if fetched.expiresAt.IsZero() {
+				// A request found a new token in the cache while the refresh was waiting for it; check
+				// the cache again shortly.
+				wait = tokenRefreshMinInterval
+			} else {
+				lifetime = time.Until(fetched.expiresAt)
+				wait = lifetime - time.Duration(float64(lifetime)*refreshFraction)
+
+				h.logger.Info().
+					Str("expiresIn", lifetime.String()).
+					Str("refreshIn", wait.String()).
+					Msg("athenahealth token refreshed")
+
+				if refreshStats != nil {
+					//nolint
+					refreshStats.TokenRefreshSuccess()
+				}
+
+				backoff = tokenRefreshMinInterval
+			}
+		}
+
+		timer := time.NewTimer(max(wait, tokenRefreshMinInterval))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+
+		case <-timer.C:
+		}
+	}
+}
+
+// cachedTokenTTL returns how long the cached token remains valid, if the token cacher can tell and
+// the token has not expired.
+func (h *HTTPClient) cachedTokenTTL(ctx context.Context) (time.Duration, bool) {
+	tokenCacher, ok := h.tokenCacher.(ExpiringTokenCacher)
+	if !ok {
+		return 0, false
+	}
+
+	expiresAt, err := tokenCacher.ExpiresAt(ctx)
+	if err != nil {
+		return 0, false
+	}
+
+	ttl := time.Until(expiresAt)
+
+	return ttl, ttl > 0
+}
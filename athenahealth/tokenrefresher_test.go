@@ -0,0 +1,237 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient_WithTokenRefresher(t *testing.T) {
+	assert := assert.New(t)
+
+	var provided atomic.Int32
+	var successes atomic.Int32
+
+	tokenCacher := tokencacher.NewDefault()
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenCacher(tokenCacher).
+		WithTokenProvider(&testTokenProvider{
+			ProvideFunc: func() (string, time.Time, error) {
+				n := provided.Add(1)
+
+				// Cached tokens expire 1 minute early, leaving a 1.5 second lifetime.
+				return fmt.Sprintf("token-%d", n), time.Now().Add(time.Minute + 1500*time.Millisecond), nil
+			},
+		}).
+		WithStats(&testStats{
+			TokenRefreshSuccessFunc: func() error {
+				successes.Add(1)
+				return nil
+			},
+		})
+
+	athenaClient.WithTokenRefresher(context.Background(), 0.5)
+	defer athenaClient.Close()
+
+	// The first token is fetched immediately.
+	assert.Eventually(func() bool {
+		token, err := tokenCacher.Get(context.Background())
+		return err == nil && token == "token-1"
+	}, time.Second, 10*time.Millisecond)
+
+	// And renewed before it expires.
+	assert.Eventually(func() bool {
+		token, err := tokenCacher.Get(context.Background())
+		return err == nil && token == "token-2"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// The refresh is recorded after the token is cached.
+	assert.Eventually(func() bool {
+		return successes.Load() >= 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHTTPClient_WithTokenRefresher_provider_error(t *testing.T) {
+	assert := assert.New(t)
+
+	var provided atomic.Int32
+	var failures atomic.Int32
+
+	// The old token is refreshed after 1 second, and stays valid for another second.
+	tokenCacher := tokencacher.NewDefault()
+	tokenCacher.Set(context.Background(), "old-token", time.Now().Add(2*time.Second))
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenCacher(tokenCacher).
+		WithTokenProvider(&testTokenProvider{
+			ProvideFunc: func() (string, time.Time, error) {
+				if provided.Add(1) == 1 {
+					return "", time.Time{}, errors.New("provider unavailable")
+				}
+
+				return "new-token", time.Now().Add(time.Hour), nil
+			},
+		}).
+		WithStats(&testStats{
+			TokenRefreshErrorFunc: func() error {
+				failures.Add(1)
+				return nil
+			},
+		})
+
+	athenaClient.WithTokenRefresher(context.Background(), 0.5)
+	defer athenaClient.Close()
+
+	assert.Eventually(func() bool {
+		return failures.Load() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// The old token is still served while the refresher backs off.
+	token, err := tokenCacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("old-token", token)
+
+	assert.Eventually(func() bool {
+		token, err := tokenCacher.Get(context.Background())
+		return err == nil && token == "new-token"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestHTTPClient_WithTokenRefresher_cached_token(t *testing.T) {
+	assert := assert.New(t)
+
+	var provided atomic.Int32
+
+	// A token refreshed by another client sharing the cache.
+	tokenCacher := tokencacher.NewDefault()
+	tokenCacher.Set(context.Background(), "shared-token", time.Now().Add(time.Hour))
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenCacher(tokenCacher).
+		WithTokenProvider(&testTokenProvider{
+			ProvideFunc: func() (string, time.Time, error) {
+				provided.Add(1)
+
+				return "new-token", time.Now().Add(time.Hour), nil
+			},
+		})
+
+	athenaClient.WithTokenRefresher(context.Background(), 0.2)
+	defer athenaClient.Close()
+
+	assert.Never(func() bool {
+		return provided.Load() > 0
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	token, err := tokenCacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("shared-token", token)
+}
+
+func TestHTTPClient_WithTokenRefresher_coalesced(t *testing.T) {
+	assert := assert.New(t)
+
+	var provided atomic.Int32
+	release := make(chan struct{})
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenProvider(&testTokenProvider{
+			ProvideFunc: func() (string, time.Time, error) {
+				n := provided.Add(1)
+				<-release
+
+				return fmt.Sprintf("token-%d", n), time.Now().Add(time.Hour), nil
+			},
+		})
+
+	athenaClient.WithTokenRefresher(context.Background(), 0.5)
+	defer athenaClient.Close()
+
+	// The refresher starts fetching the first token.
+	assert.Eventually(func() bool {
+		return provided.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	tokens := make(chan string, 1)
+	go func() {
+		token, err := athenaClient.token(context.Background())
+		assert.NoError(err)
+
+		tokens <- token
+	}()
+
+	// A request needing a token while the refresh is in flight waits for it instead of fetching another.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.Equal("token-1", <-tokens)
+	assert.Equal(int32(1), provided.Load())
+}
+
+func TestHTTPClient_Close_concurrent(t *testing.T) {
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenProvider(&testTokenProvider{})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			athenaClient.WithTokenRefresher(context.Background(), 0.2)
+		}()
+
+		go func() {
+			defer wg.Done()
+			athenaClient.Close()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.NoError(t, athenaClient.Close())
+}
+
+func TestHTTPClient_WithTokenRefresher_stops(t *testing.T) {
+	assert := assert.New(t)
+
+	var provided atomic.Int32
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "", "").
+		WithTokenProvider(&testTokenProvider{
+			ProvideFunc: func() (string, time.Time, error) {
+				provided.Add(1)
+
+				// Already expired once cached, so the refresher would run as often as it can.
+				return testToken, time.Now(), nil
+			},
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	athenaClient.WithTokenRefresher(ctx, 0.2)
+
+	assert.Eventually(func() bool {
+		return provided.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(athenaClient.Close())
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(int32(1), provided.Load())
+
+	// Close is safe to call more than once, and without a refresher.
+	assert.NoError(athenaClient.Close())
+	assert.NoError(NewHTTPClient(&http.Client{}, "", "", "").Close())
+}
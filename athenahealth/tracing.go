@@ -0,0 +1,107 @@
+package athenahealth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/pathutil"
+)
+
+const (
+	attributeHTTPMethod      = "http.request.method"
+	attributeHTTPStatusCode  = "http.response.status_code"
+	attributeURLTemplate     = "url.template"
+	attributeXRequestID      = "http.request.header.x-request-id"
+	attributePracticeID      = "athenahealth.practice_id"
+	attributePreview         = "athenahealth.preview"
+	attributeAttempt         = "athenahealth.attempt"
+	attributeRetryAfter      = "athenahealth.retry_after"
+	attributeRateLimited     = "athenahealth.rate_limited"
+	attributeRateLimitWait   = "athenahealth.rate_limit_wait"
+	attributeRateLimitChecks = "athenahealth.rate_limit_checks"
+)
+
+// SpanKind is the role of a span, as in OpenTelemetry.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindClient
+)
+
+// Attribute describes a span or an event. Value is a string, []string, bool or int.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) AddEvent(name string, attrs ...Attribute) {}
+func (noopSpan) SetAttributes(attrs ...Attribute)         {}
+func (noopSpan) RecordError(err error)                    {}
+func (noopSpan) End()                                     {}
+
+// WithTracer enables tracing, e.g. with oteltracer.New for OpenTelemetry (oteltracer.WithTracerProvider is
+// shorthand for it, kept out of this package so that it does not link OpenTelemetry). Each request produces a
+// client span, parented by the span in the request's context, with retries recorded as events and token
+// fetches and waits for the rate limiter as child spans.
+func (h *HTTPClient) WithTracer(tracer Tracer) *HTTPClient {
+	h.tracer = tracer
+
+	return h
+}
+
+func (h *HTTPClient) startRequestSpan(ctx context.Context, method, path, xRequestID string) (context.Context, Span) {
+	route := pathutil.Normalize(path)
+	practiceID, _ := h.practice(ctx)
+
+	return h.tracer.Start(ctx, method+" "+route, SpanKindClient,
+		Attribute{attributeHTTPMethod, method},
+		Attribute{attributeURLTemplate, route},
+		Attribute{attributeXRequestID, []string{xRequestID}},
+		Attribute{attributePracticeID, practiceID},
+		Attribute{attributePreview, h.environment.Preview},
+	)
+}
+
+func (h *HTTPClient) endRequestSpan(span Span, res *http.Response, err error) {
+	if res != nil {
+		span.SetAttributes(Attribute{attributeHTTPStatusCode, res.StatusCode})
+	}
+
+	if err != nil {
+		span.RecordError(h.redactError(err))
+	}
+
+	span.End()
+}
+
+func (h *HTTPClient) startRateLimitSpan(ctx context.Context, method, route string) (context.Context, Span) {
+	return h.tracer.Start(ctx, "athenahealth.rate_limit", SpanKindInternal,
+		Attribute{attributeHTTPMethod, method},
+		Attribute{attributeURLTemplate, route},
+	)
+}
+
+// endRateLimitSpan ends the span of a wait for the rate limiter, which checked it the given number of times.
+func endRateLimitSpan(span Span, checks int, wait time.Duration, err error) {
+	span.SetAttributes(
+		Attribute{attributeRateLimited, checks > 1},
+		Attribute{attributeRateLimitChecks, checks},
+		Attribute{attributeRateLimitWait, wait.String()},
+	)
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
@@ -0,0 +1,244 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/retrypolicy"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpanContextKey struct{}
+
+// testTracer records the spans it starts.
+type testTracer struct {
+	spans []*testSpan
+	lock  sync.Mutex
+}
+
+type testSpan struct {
+	name       string
+	kind       SpanKind
+	parent     *testSpan
+	attributes map[string]interface{}
+	events     []string
+	err        error
+	ended      bool
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	parent, _ := ctx.Value(testSpanContextKey{}).(*testSpan)
+
+	span := &testSpan{
+		name:       name,
+		kind:       kind,
+		parent:     parent,
+		attributes: make(map[string]interface{}),
+	}
+	span.SetAttributes(attrs...)
+
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, testSpanContextKey{}, span), span
+}
+
+func (t *testTracer) findSpans(name string) []*testSpan {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var found []*testSpan
+	for _, span := range t.spans {
+		if span.name == name {
+			found = append(found, span)
+		}
+	}
+
+	return found
+}
+
+func (s *testSpan) AddEvent(name string, attrs ...Attribute) {
+	s.events = append(s.events, name)
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+func TestHTTPClient_WithTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := &testTracer{}
+
+	var xRequestID string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		xRequestID = r.Header.Get(XRequestIDHeaderKey)
+		w.Write([]byte(`{}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithTracer(tracer).
+		WithTokenCacher(tokencacher.NewDefault())
+
+	defer ts.Close()
+
+	ctx, parent := tracer.Start(context.Background(), "parent", SpanKindInternal)
+
+	_, err := athenaClient.request(ctx, http.MethodGet, "/patients/123", nil, nil, nil)
+	assert.NoError(err)
+
+	parent.End()
+
+	requestSpans := tracer.findSpans("GET /patients/:id:")
+	assert.Len(requestSpans, 1)
+
+	requestSpan := requestSpans[0]
+	assert.Equal(SpanKindClient, requestSpan.kind)
+	assert.Equal(parent, requestSpan.parent)
+	assert.True(requestSpan.ended)
+	assert.NoError(requestSpan.err)
+
+	assert.Equal(map[string]interface{}{
+		attributeHTTPMethod:     "GET",
+		attributeURLTemplate:    "/patients/:id:",
+		attributeXRequestID:     []string{xRequestID},
+		attributePracticeID:     testPracticeID,
+		attributePreview:        true,
+		attributeHTTPStatusCode: http.StatusOK,
+	}, requestSpan.attributes)
+
+	for _, name := range []string{"athenahealth.token", "athenahealth.rate_limit"} {
+		childSpans := tracer.findSpans(name)
+		assert.Len(childSpans, 1, name)
+		assert.Equal(requestSpan, childSpans[0].parent, name)
+		assert.Equal(SpanKindInternal, childSpans[0].kind, name)
+		assert.True(childSpans[0].ended, name)
+	}
+}
+
+func TestHTTPClient_WithTracer_retry(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := &testTracer{}
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	checks := 0
+	rateLimiter := &testRateLimiter{}
+	rateLimiter.AllowedFunc = func(preview bool) (time.Duration, error) {
+		checks++
+		if checks > 5 {
+			return 0, nil
+		}
+
+		return time.Millisecond, ratelimiter.ErrRateExceeded
+	}
+
+	athenaClient, ts := testClient(h)
+	athenaClient.WithTracer(tracer).
+		WithRateLimiter(rateLimiter).
+		WithRetryPolicy(retrypolicy.NewExponential(3, time.Millisecond, time.Millisecond))
+
+	defer ts.Close()
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/patients", nil, nil, nil)
+	assert.Error(err)
+	assert.Equal(3, calls)
+
+	requestSpans := tracer.findSpans("GET /patients")
+	assert.Len(requestSpans, 1)
+
+	requestSpan := requestSpans[0]
+	assert.Error(requestSpan.err)
+	assert.Equal(http.StatusServiceUnavailable, requestSpan.attributes[attributeHTTPStatusCode])
+	assert.Equal([]string{"retry", "retry"}, requestSpan.events)
+
+	// Each attempt waits for the rate limiter in a single span, however many times it is checked.
+	rateLimitSpans := tracer.findSpans("athenahealth.rate_limit")
+	if assert.Len(rateLimitSpans, 3) {
+		assert.Equal(true, rateLimitSpans[0].attributes[attributeRateLimited])
+		assert.Equal(6, rateLimitSpans[0].attributes[attributeRateLimitChecks])
+		assert.NoError(rateLimitSpans[0].err)
+
+		assert.Equal(false, rateLimitSpans[1].attributes[attributeRateLimited])
+		assert.Equal(1, rateLimitSpans[1].attributes[attributeRateLimitChecks])
+	}
+}
+
+func TestHTTPClient_WithTracer_token_error(t *testing.T) {
+	assert := assert.New(t)
+
+	errTokenProvider := errors.New("token provider error")
+
+	tracer := &testTracer{}
+
+	athenaClient, ts := testClient(nil)
+	athenaClient.WithTracer(tracer).
+		WithTokenCacher(tokencacher.NewDefault()).
+		WithTokenProvider(&testTokenProvider{
+			ProvideFunc: func() (string, time.Time, error) {
+				return "", time.Time{}, errTokenProvider
+			},
+		})
+
+	defer ts.Close()
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/patients", nil, nil, nil)
+	assert.ErrorIs(err, errTokenProvider)
+
+	tokenSpans := tracer.findSpans("athenahealth.token")
+	assert.Len(tokenSpans, 1)
+	assert.ErrorIs(tokenSpans[0].err, errTokenProvider)
+}
+
+func TestHTTPClient_WithTracer_redacts_errors(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := &testTracer{}
+
+	athenaClient, ts := testClient(nil)
+	athenaClient.WithTracer(tracer).
+		WithMiddleware(func(next RoundTrip) RoundTrip {
+			return func(req *http.Request) (*http.Response, error) {
+				return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: syscall.ECONNREFUSED}
+			}
+		})
+
+	defer ts.Close()
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/patients/search?firstname=Jane&lastname=Doe", nil, nil, nil)
+	assert.ErrorIs(err, syscall.ECONNREFUSED)
+
+	requestSpans := tracer.findSpans("GET /patients/search")
+	assert.Len(requestSpans, 1)
+
+	spanErr := requestSpans[0].err
+	assert.ErrorIs(spanErr, syscall.ECONNREFUSED)
+	assert.NotContains(spanErr.Error(), "Jane")
+	assert.NotContains(spanErr.Error(), "Doe")
+	assert.Contains(spanErr.Error(), "/patients/search")
+}